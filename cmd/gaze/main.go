@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	charmlog "github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
@@ -199,10 +200,32 @@ func runAnalyze(p analyzeParams) error {
 		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
 	}
 
+	// --verbose implies --classify.
+	if p.verbose {
+		p.classify = true
+	}
+
+	// Normalize zero to -1 (not set). The flag default is -1 but
+	// struct literals in tests may leave these fields at their Go
+	// zero value (0). Both mean "use config/default".
+	contractualThresh := p.contractualThresh
+	if contractualThresh == 0 {
+		contractualThresh = -1
+	}
+	incidentalThresh := p.incidentalThresh
+	if incidentalThresh == 0 {
+		incidentalThresh = -1
+	}
+	cfg, cfgErr := loadConfig(p.configPath, contractualThresh, incidentalThresh)
+	if cfgErr != nil {
+		return fmt.Errorf("loading config: %w", cfgErr)
+	}
+
 	opts := analysis.Options{
 		IncludeUnexported: p.includeUnexported,
 		FunctionFilter:    p.function,
 		Version:           version,
+		Rules:             cfg.Detection.EffectiveRules(),
 	}
 
 	logger.Info("analyzing package", "pkg", p.pkgPath)
@@ -221,28 +244,8 @@ func runAnalyze(p analyzeParams) error {
 
 	logger.Info("analysis complete", "functions", len(results))
 
-	// --verbose implies --classify.
-	if p.verbose {
-		p.classify = true
-	}
-
 	// Run mechanical classification if requested.
 	if p.classify {
-		// Normalize zero to -1 (not set). The flag default is -1 but
-		// struct literals in tests may leave these fields at their Go
-		// zero value (0). Both mean "use config/default".
-		contractualThresh := p.contractualThresh
-		if contractualThresh == 0 {
-			contractualThresh = -1
-		}
-		incidentalThresh := p.incidentalThresh
-		if incidentalThresh == 0 {
-			incidentalThresh = -1
-		}
-		cfg, cfgErr := loadConfig(p.configPath, contractualThresh, incidentalThresh)
-		if cfgErr != nil {
-			return fmt.Errorf("loading config: %w", cfgErr)
-		}
 		results, err = runClassify(results, p.pkgPath, cfg, p.verbose)
 		if err != nil {
 			return fmt.Errorf("classification: %w", err)
@@ -387,6 +390,7 @@ type crapParams struct {
 	patterns        []string
 	format          string
 	opts            crap.Options
+	cfg             *config.GazeConfig
 	maxCrapload     int
 	maxGazeCrapload int
 	moduleDir       string
@@ -399,22 +403,35 @@ type crapParams struct {
 
 	// coverageFunc overrides buildContractCoverageFunc for testing.
 	// When nil, the production buildContractCoverageFunc is called.
-	coverageFunc func([]string, string, io.Writer) func(string, string) (float64, bool)
+	coverageFunc func([]string, string, *config.GazeConfig, io.Writer) func(taxonomy.FunctionID) (float64, bool)
 }
 
 func newSchemaCmd() *cobra.Command {
-	return &cobra.Command{
+	var configPath string
+
+	cmd := &cobra.Command{
 		Use:   "schema",
 		Short: "Print the JSON Schema for Gaze analysis output",
 		Long: `Print the JSON Schema (Draft 2020-12) that documents the
 structure of gaze analyze --format=json output. Useful for
-validating output or generating client types.`,
+validating output or generating client types. Custom side effect
+types declared by detection rules in .gaze.yaml are included.`,
 		Args: cobra.NoArgs,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			_, err := fmt.Fprintln(cmd.OutOrStdout(), report.Schema)
+			cfg, err := loadConfig(configPath, -1, -1)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
+			schema := report.SchemaWithCustomTypes(cfg.Detection.CustomTypes())
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), schema)
 			return err
 		},
 	}
+
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file (default: search CWD)")
+
+	return cmd
 }
 
 // runCrap is the extracted, testable body of the crap command.
@@ -431,7 +448,7 @@ func runCrap(p crapParams) error {
 		if buildCoverage == nil {
 			buildCoverage = buildContractCoverageFunc
 		}
-		ccFunc := buildCoverage(p.patterns, p.moduleDir, p.stderr)
+		ccFunc := buildCoverage(p.patterns, p.moduleDir, p.cfg, p.stderr)
 		if ccFunc != nil {
 			p.opts.ContractCoverageFunc = ccFunc
 		}
@@ -544,41 +561,52 @@ func resolvePackagePaths(patterns []string, moduleDir string) ([]string, error)
 	return pkgPaths, nil
 }
 
-// analyzePackageCoverage runs the 4-step quality pipeline on a single
-// package (analysis -> classify -> test-load -> quality assess) and
-// returns the quality reports. Returns nil if any step fails.
+// analyzePackageCoverage runs the 4-step quality pipeline over a set
+// of packages (analysis -> classify -> test-load -> quality assess)
+// and returns the function-centric contract coverage, unioned across
+// all tests targeting each function. The packages are assessed
+// together so that external test packages and integration test
+// packages count toward the contract coverage of every analyzed
+// package they exercise. Packages that fail analysis are skipped;
+// returns nil if no coverage could be produced.
 func analyzePackageCoverage(
-	pkgPath string,
+	pkgPaths []string,
 	gazeConfig *config.GazeConfig,
 	stderr io.Writer,
-) []taxonomy.QualityReport {
+) []taxonomy.FunctionCoverage {
 	analysisOpts := analysis.Options{
 		IncludeUnexported: false,
 		Version:           version,
+		Rules:             gazeConfig.Detection.EffectiveRules(),
 	}
 
-	// Step 1: Analyze (Spec 001).
-	results, err := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
-	if err != nil {
-		logger.Debug("quality pipeline: analysis failed", "pkg", pkgPath, "err", err)
-		return nil
-	}
-	if len(results) == 0 {
-		logger.Debug("quality pipeline: no analysis results", "pkg", pkgPath)
-		return nil
+	// Steps 1-2: Analyze (Spec 001) and classify (Spec 002).
+	var classified []taxonomy.AnalysisResult
+	for _, pkgPath := range pkgPaths {
+		results, err := analysis.LoadAndAnalyze(pkgPath, analysisOpts)
+		if err != nil {
+			logger.Debug("quality pipeline: analysis failed", "pkg", pkgPath, "err", err)
+			continue
+		}
+		if len(results) == 0 {
+			logger.Debug("quality pipeline: no analysis results", "pkg", pkgPath)
+			continue
+		}
+		results, err = runClassify(results, pkgPath, gazeConfig, false)
+		if err != nil {
+			logger.Debug("quality pipeline: classification failed", "pkg", pkgPath, "err", err)
+			continue
+		}
+		classified = append(classified, results...)
 	}
-
-	// Step 2: Classify (Spec 002).
-	classified, err := runClassify(results, pkgPath, gazeConfig, false)
-	if err != nil {
-		logger.Debug("quality pipeline: classification failed", "pkg", pkgPath, "err", err)
+	if len(classified) == 0 {
 		return nil
 	}
 
-	// Step 3: Load test package.
-	testPkg, err := loadTestPackage(pkgPath)
+	// Step 3: Load test packages.
+	testPkgs, err := loadTestPackages(stderr, pkgPaths...)
 	if err != nil {
-		logger.Debug("quality pipeline: test package load failed", "pkg", pkgPath, "err", err)
+		logger.Debug("quality pipeline: test package load failed", "err", err)
 		return nil
 	}
 
@@ -587,25 +615,27 @@ func analyzePackageCoverage(
 		Version: version,
 		Stderr:  stderr,
 	}
-	reports, _, err := quality.Assess(classified, testPkg, qualOpts)
+	_, summary, err := quality.AssessPackages(classified, testPkgs, qualOpts)
 	if err != nil {
-		logger.Debug("quality pipeline: quality assessment failed", "pkg", pkgPath, "err", err)
+		logger.Debug("quality pipeline: quality assessment failed", "err", err)
 		return nil
 	}
-	return reports
+	return summary.FunctionCoverage
 }
 
 // buildContractCoverageFunc runs the quality pipeline across the
-// given package patterns and returns a ContractCoverageFunc callback
-// for GazeCRAP scoring. This is best-effort: if the quality pipeline
+// given package patterns with the command's config and returns a
+// ContractCoverageFunc callback for GazeCRAP scoring. A nil cfg
+// selects the defaults. This is best-effort: if the quality pipeline
 // fails for any package (no tests, config errors, etc.), those
 // packages are silently skipped. Returns nil if no coverage data
 // could be collected.
 func buildContractCoverageFunc(
 	patterns []string,
 	moduleDir string,
+	cfg *config.GazeConfig,
 	stderr io.Writer,
-) func(pkg, function string) (float64, bool) {
+) func(id taxonomy.FunctionID) (float64, bool) {
 	pkgPaths, err := resolvePackagePaths(patterns, moduleDir)
 	if err != nil {
 		logger.Debug("quality pipeline: failed to resolve packages", "err", err)
@@ -616,25 +646,15 @@ func buildContractCoverageFunc(
 		return nil
 	}
 
-	// Load config once for all packages.
-	gazeConfig, cfgErr := loadConfig("", -1, -1)
-	if cfgErr != nil {
-		logger.Debug("quality pipeline: config load failed", "err", cfgErr)
-		gazeConfig = config.DefaultConfig()
+	if cfg == nil {
+		cfg = config.DefaultConfig()
 	}
 
-	// Build coverage map: "shortPkg:qualifiedName" -> percentage.
-	coverageMap := make(map[string]float64)
+	// Build coverage map: FunctionID -> percentage.
+	coverageMap := make(map[taxonomy.FunctionID]float64)
 
-	for _, pkgPath := range pkgPaths {
-		reports := analyzePackageCoverage(pkgPath, gazeConfig, stderr)
-		for _, report := range reports {
-			shortPkg := extractShortPkgName(report.TargetFunction.Package)
-			key := shortPkg + ":" + report.TargetFunction.QualifiedName()
-			if existing, ok := coverageMap[key]; !ok || report.ContractCoverage.Percentage > existing {
-				coverageMap[key] = report.ContractCoverage.Percentage
-			}
-		}
+	for _, fc := range analyzePackageCoverage(pkgPaths, cfg, stderr) {
+		coverageMap[fc.Function.FunctionID()] = fc.ContractCoverage.Percentage
 	}
 
 	if len(coverageMap) == 0 {
@@ -644,31 +664,24 @@ func buildContractCoverageFunc(
 	logger.Info("quality pipeline complete",
 		"functions_with_coverage", len(coverageMap))
 
-	return func(pkg, function string) (float64, bool) {
-		key := pkg + ":" + function
-		pct, ok := coverageMap[key]
+	return func(id taxonomy.FunctionID) (float64, bool) {
+		pct, ok := coverageMap[id]
 		return pct, ok
 	}
 }
 
-// extractShortPkgName returns the short package name from a full
-// import path. For "github.com/unbound-force/gaze/internal/crap", it
-// returns "crap".
-func extractShortPkgName(importPath string) string {
-	if idx := strings.LastIndex(importPath, "/"); idx >= 0 {
-		return importPath[idx+1:]
-	}
-	return importPath
-}
-
 func newCrapCmd() *cobra.Command {
 	var (
 		format            string
-		coverProfile      string
+		coverProfiles     []string
+		coverDataDirs     []string
+		complexity        string
 		crapThreshold     float64
 		gazeCrapThreshold float64
 		maxCrapload       int
 		maxGazeCrapload   int
+		configPath        string
+		goTest            goTestFlags
 	)
 
 	cmd := &cobra.Command{
@@ -679,16 +692,43 @@ cyclomatic complexity with test coverage. Reports per-function
 CRAP scores and the project's CRAPload (count of functions above
 the threshold).
 
-If no coverage profile is provided, runs 'go test -coverprofile'
-automatically.`,
+With --complexity=cognitive, cognitive complexity replaces
+cyclomatic complexity in the formula. It charges nested control
+flow more and a switch once regardless of its number of cases.
+
+Coverage from several test tiers can be combined: --coverprofile
+may be repeated, and --covdata reads the GOCOVERDIR data written by
+binaries built with 'go build -cover'. Blocks covered by more than
+one source are merged. If neither is provided, runs
+'go test -coverprofile' automatically.
+
+The generated run is configured by the coverage.go_test section of
+.gaze.yaml (tags, race, timeout, count, coverpkg, env, run), which
+the --tags, --race, --timeout, --count, --coverpkg, --env and --run
+flags override. Packages whose tests fail are listed and left out
+of the scores; the remaining packages are still scored.`,
 		Args: cobra.MinimumNArgs(1),
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			moduleDir, err := os.Getwd()
 			if err != nil {
 				return fmt.Errorf("getting working directory: %w", err)
 			}
+			metric, err := crap.ParseComplexityMetric(complexity)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig(configPath, -1, -1)
+			if err != nil {
+				return fmt.Errorf("loading config: %w", err)
+			}
 			opts := crap.DefaultOptions()
-			opts.CoverProfile = coverProfile
+			opts.GoTest = cfg.Coverage.GoTest
+			if err := goTest.apply(cmd, &opts.GoTest); err != nil {
+				return err
+			}
+			opts.CoverProfiles = coverProfiles
+			opts.CoverDataDirs = coverDataDirs
+			opts.Complexity = metric
 			opts.CRAPThreshold = crapThreshold
 			opts.GazeCRAPThreshold = gazeCrapThreshold
 			opts.Stderr = os.Stderr
@@ -696,6 +736,7 @@ automatically.`,
 				patterns:        args,
 				format:          format,
 				opts:            opts,
+				cfg:             cfg,
 				maxCrapload:     maxCrapload,
 				maxGazeCrapload: maxGazeCrapload,
 				moduleDir:       moduleDir,
@@ -707,8 +748,12 @@ automatically.`,
 
 	cmd.Flags().StringVar(&format, "format", "text",
 		"output format: text or json")
-	cmd.Flags().StringVar(&coverProfile, "coverprofile", "",
-		"path to coverage profile (default: generate via go test)")
+	cmd.Flags().StringArrayVar(&coverProfiles, "coverprofile", nil,
+		"coverage profiles to merge, repeatable (default: generate via go test)")
+	cmd.Flags().StringArrayVar(&coverDataDirs, "covdata", nil,
+		"GOCOVERDIR directories of binary coverage data to merge, repeatable")
+	cmd.Flags().StringVar(&complexity, "complexity", "cyclomatic",
+		"complexity metric: cyclomatic or cognitive")
 	cmd.Flags().Float64Var(&crapThreshold, "crap-threshold", 15,
 		"CRAP score threshold for flagging functions")
 	cmd.Flags().Float64Var(&gazeCrapThreshold, "gaze-crap-threshold", 15,
@@ -717,10 +762,85 @@ automatically.`,
 		"fail if CRAPload exceeds this (0 = no limit)")
 	cmd.Flags().IntVar(&maxGazeCrapload, "max-gaze-crapload", 0,
 		"fail if GazeCRAPload exceeds this (0 = no limit)")
+	cmd.Flags().StringVar(&configPath, "config", "",
+		"path to .gaze.yaml config file (default: search CWD)")
+	goTest.register(cmd)
 
 	return cmd
 }
 
+// goTestFlags holds the crap command's go test flags, which override
+// the coverage.go_test section of .gaze.yaml when set.
+type goTestFlags struct {
+	tags     []string
+	race     bool
+	timeout  time.Duration
+	count    int
+	coverPkg string
+	env      []string
+	run      string
+}
+
+// register defines the go test flags on cmd.
+func (f *goTestFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVar(&f.tags, "tags", nil,
+		"build tags for the generated go test run")
+	cmd.Flags().BoolVar(&f.race, "race", false,
+		"run the generated go test with the race detector")
+	cmd.Flags().DurationVar(&f.timeout, "timeout", 0,
+		"go test -timeout for the generated run (0 = go test default)")
+	cmd.Flags().IntVar(&f.count, "count", 0,
+		"go test -count for the generated run (0 = go test default)")
+	cmd.Flags().StringVar(&f.coverPkg, "coverpkg", "",
+		"go test -coverpkg for the generated run, e.g. ./...")
+	cmd.Flags().StringArrayVar(&f.env, "env", nil,
+		"KEY=VALUE environment variable for the generated go test run, repeatable")
+	cmd.Flags().StringVar(&f.run, "run", "",
+		"go test -run filter for the generated run")
+}
+
+// apply overrides goTest with the flags set on cmd's command line.
+// Environment variables are added to those of the config.
+func (f *goTestFlags) apply(cmd *cobra.Command, goTest *config.GoTestConfig) error {
+	flags := cmd.Flags()
+	if flags.Changed("tags") {
+		goTest.Tags = f.tags
+	}
+	if flags.Changed("race") {
+		goTest.Race = f.race
+	}
+	if flags.Changed("timeout") {
+		goTest.Timeout = f.timeout
+	}
+	if flags.Changed("count") {
+		if f.count < 0 {
+			return fmt.Errorf("--count=%d is invalid: must not be negative", f.count)
+		}
+		goTest.Count = f.count
+	}
+	if flags.Changed("coverpkg") {
+		goTest.CoverPkg = f.coverPkg
+	}
+	if flags.Changed("run") {
+		goTest.Run = f.run
+	}
+	if len(f.env) > 0 {
+		env := make(map[string]string, len(goTest.Env)+len(f.env))
+		for k, v := range goTest.Env {
+			env[k] = v
+		}
+		for _, kv := range f.env {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok || k == "" {
+				return fmt.Errorf("--env=%q is invalid: must be KEY=VALUE", kv)
+			}
+			env[k] = v
+		}
+		goTest.Env = env
+	}
+	return nil
+}
+
 // docscanParams holds the parsed flags for the docscan command.
 type docscanParams struct {
 	pkgPath    string
@@ -815,6 +935,7 @@ type qualityParams struct {
 	incidentalThresh     int
 	minContractCoverage  int
 	maxOverSpecification int
+	coverProfile         string
 	stdout               io.Writer
 	stderr               io.Writer
 }
@@ -825,22 +946,17 @@ func runQuality(p qualityParams) error {
 		return fmt.Errorf("invalid format %q: must be 'text' or 'json'", p.format)
 	}
 
-	// Step 1: Load and analyze the package (Spec 001).
-	opts := analysis.Options{
-		IncludeUnexported: false,
-		Version:           version,
-	}
-	logger.Info("analyzing package", "pkg", p.pkgPath)
-	results, err := analysis.LoadAndAnalyze(p.pkgPath, opts)
+	// The argument may be a pattern such as "./..." so that external
+	// and integration test packages are assessed against every
+	// production package they exercise.
+	pkgPaths, err := resolvePackagePaths([]string{p.pkgPath}, "")
 	if err != nil {
 		return err
 	}
-	if len(results) == 0 {
-		logger.Warn("no functions found to analyze")
-		return nil
+	if len(pkgPaths) == 0 {
+		return fmt.Errorf("no packages found for %q", p.pkgPath)
 	}
 
-	// Step 2: Classify side effects (Spec 002).
 	contractualThresh := p.contractualThresh
 	if contractualThresh == 0 {
 		contractualThresh = -1
@@ -853,13 +969,43 @@ func runQuality(p qualityParams) error {
 	if cfgErr != nil {
 		return fmt.Errorf("loading config: %w", cfgErr)
 	}
-	results, err = runClassify(results, p.pkgPath, cfg, p.verbose)
-	if err != nil {
-		return fmt.Errorf("classification: %w", err)
+
+	// Steps 1-2: Load, analyze (Spec 001), and classify (Spec 002)
+	// each package. When a pattern matches several packages, those
+	// without analyzable production code (e.g., integration test
+	// packages) are skipped here and only contribute tests.
+	opts := analysis.Options{
+		IncludeUnexported: false,
+		Version:           version,
+		Rules:             cfg.Detection.EffectiveRules(),
+	}
+	var results []taxonomy.AnalysisResult
+	for _, pkgPath := range pkgPaths {
+		logger.Info("analyzing package", "pkg", pkgPath)
+		pkgResults, err := analysis.LoadAndAnalyze(pkgPath, opts)
+		if err != nil {
+			if len(pkgPaths) == 1 {
+				return err
+			}
+			logger.Debug("skipping package", "pkg", pkgPath, "err", err)
+			continue
+		}
+		if len(pkgResults) == 0 {
+			continue
+		}
+		pkgResults, err = runClassify(pkgResults, pkgPath, cfg, p.verbose)
+		if err != nil {
+			return fmt.Errorf("classification: %w", err)
+		}
+		results = append(results, pkgResults...)
+	}
+	if len(results) == 0 {
+		logger.Warn("no functions found to analyze")
+		return nil
 	}
 
-	// Step 3: Load the test package with test files.
-	testPkg, err := loadTestPackage(p.pkgPath)
+	// Step 3: Load the test packages with test files.
+	testPkgs, err := loadTestPackages(p.stderr, pkgPaths...)
 	if err != nil {
 		return fmt.Errorf("loading test package: %w", err)
 	}
@@ -871,7 +1017,17 @@ func runQuality(p qualityParams) error {
 		Version:    version,
 		Stderr:     p.stderr,
 	}
-	reports, summary, err := quality.Assess(results, testPkg, qualOpts)
+	if p.coverProfile != "" {
+		// Profile paths are module-relative; without a module root
+		// they are resolved against the working directory.
+		moduleDir, _ := findModuleRoot()
+		lines, err := crap.ParseLineCoverage([]string{p.coverProfile}, moduleDir)
+		if err != nil {
+			return fmt.Errorf("parsing coverage profile: %w", err)
+		}
+		qualOpts.LineExecuted = lines.Executed
+	}
+	reports, summary, err := quality.AssessPackages(results, testPkgs, qualOpts)
 	if err != nil {
 		return fmt.Errorf("quality assessment: %w", err)
 	}
@@ -892,8 +1048,14 @@ func runQuality(p qualityParams) error {
 	return checkQualityThresholds(p, reports, summary)
 }
 
-// loadTestPackage loads a Go package with test files included.
-func loadTestPackage(pkgPath string) (*packages.Package, error) {
+// loadTestPackages loads the given packages with test files included
+// and returns every package that declares test functions: in-package
+// test variants, external `foo_test` packages, and test-only packages
+// such as integration suites. Packages that fail to load or
+// type-check are skipped with a warning on stderr, so one broken
+// package does not prevent assessing the rest; it is an error only
+// when every package fails.
+func loadTestPackages(stderr io.Writer, pkgPaths ...string) ([]*packages.Package, error) {
 	cfg := &packages.Config{
 		Mode: packages.NeedName |
 			packages.NeedFiles |
@@ -906,41 +1068,64 @@ func loadTestPackage(pkgPath string) (*packages.Package, error) {
 			packages.NeedTypesSizes,
 		Tests: true,
 	}
-	pkgs, err := packages.Load(cfg, pkgPath)
+	pkgs, err := packages.Load(cfg, pkgPaths...)
 	if err != nil {
 		return nil, fmt.Errorf("loading test package: %w", err)
 	}
 	if len(pkgs) == 0 {
-		return nil, fmt.Errorf("no packages found for %q", pkgPath)
+		return nil, fmt.Errorf("no packages found for %q", strings.Join(pkgPaths, " "))
 	}
 
-	// Check for package load errors.
+	// When Tests=true, packages.Load returns several packages per
+	// pattern: the base package, the internal test package (same
+	// name, with test files merged), possibly an external test
+	// package (with _test suffix), and the generated test main.
+	// Keep those that contain test function declarations, and set
+	// aside those with load errors. A broken package shows up once
+	// per variant, so it is recorded once per import path.
+	var testPkgs, failed []*packages.Package
+	seen := make(map[string]bool)
+	failedPaths := make(map[string]bool)
 	for _, pkg := range pkgs {
 		if len(pkg.Errors) > 0 {
-			msgs := make([]string, len(pkg.Errors))
-			for i, e := range pkg.Errors {
-				msgs[i] = e.Error()
+			if !failedPaths[pkg.PkgPath] {
+				failedPaths[pkg.PkgPath] = true
+				failed = append(failed, pkg)
 			}
-			return nil, fmt.Errorf("package %s has errors: %s",
-				pkg.PkgPath, strings.Join(msgs, "; "))
+			continue
+		}
+		if seen[pkg.ID] || !quality.HasTestSyntax(pkg) {
+			continue
 		}
+		seen[pkg.ID] = true
+		testPkgs = append(testPkgs, pkg)
 	}
 
-	// When Tests=true, packages.Load returns multiple packages:
-	// the base package, the internal test package (same name, with
-	// test files merged), and possibly an external test package
-	// (with _test suffix). Prefer the package that contains test
-	// function declarations in its syntax.
-	for _, pkg := range pkgs {
-		if quality.HasTestSyntax(pkg) {
-			return pkg, nil
+	if len(testPkgs) == 0 && len(failed) > 0 {
+		pkg := failed[0]
+		msgs := make([]string, len(pkg.Errors))
+		for i, e := range pkg.Errors {
+			msgs[i] = e.Error()
+		}
+		return nil, fmt.Errorf("package %s has errors: %s",
+			pkg.PkgPath, strings.Join(msgs, "; "))
+	}
+	if stderr != nil {
+		for _, pkg := range failed {
+			_, _ = fmt.Fprintf(stderr,
+				"warning: package %s has errors; its tests are not assessed: %v\n",
+				pkg.PkgPath, pkg.Errors[0])
 		}
 	}
 
 	// No package has test syntax — return an error rather than
-	// silently returning a non-test package that would produce
+	// silently returning non-test packages that would produce
 	// empty quality results.
-	return nil, fmt.Errorf("no test package found for %q — does the package have *_test.go files?", pkgPath)
+	if len(testPkgs) == 0 {
+		return nil, fmt.Errorf("no test package found for %q — does the package have *_test.go files?",
+			strings.Join(pkgPaths, " "))
+	}
+	return testPkgs, nil
 }
 
 // checkQualityThresholds enforces CI threshold flags on quality
@@ -1029,16 +1214,24 @@ func newQualityCmd() *cobra.Command {
 		incidentalThresh     int
 		minContractCoverage  int
 		maxOverSpecification int
+		coverProfile         string
 	)
 
 	cmd := &cobra.Command{
-		Use:   "quality [package]",
+		Use:   "quality [package|pattern]",
 		Short: "Assess test quality via side effect mapping",
 		Long: `Analyze how well a package's tests assert on the contractual
 side effects of the functions they test. Reports Contract Coverage
 (ratio of contractual effects that are asserted on) and Over-
 Specification Score (assertions on incidental implementation details).
 
+The argument may be a pattern such as ./... — tests in external
+(package foo_test) and integration test packages are then paired
+with targets in every matched package.
+
+With --coverprofile, contractual effects whose source line the
+profile shows never ran are flagged as never executed.
+
 Requires the target package to have existing test files.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(_ *cobra.Command, args []string) error {
@@ -1052,6 +1245,7 @@ Requires the target package to have existing test files.`,
 				incidentalThresh:     incidentalThresh,
 				minContractCoverage:  minContractCoverage,
 				maxOverSpecification: maxOverSpecification,
+				coverProfile:         coverProfile,
 				stdout:               os.Stdout,
 				stderr:               os.Stderr,
 			})
@@ -1074,6 +1268,8 @@ Requires the target package to have existing test files.`,
 		"fail if contract coverage is below this percentage (0 = no limit)")
 	cmd.Flags().IntVar(&maxOverSpecification, "max-over-specification", 0,
 		"fail if over-specification count exceeds this (0 = no limit)")
+	cmd.Flags().StringVar(&coverProfile, "coverprofile", "",
+		"path to a coverage profile; flags contractual effects never executed")
 
 	return cmd
 }
@@ -1134,11 +1330,16 @@ func runSelfCheck(p selfCheckParams) error {
 	if err != nil {
 		return fmt.Errorf("finding module root: %w", err)
 	}
+	cfg, err := loadConfig("", -1, -1)
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
 
 	cp := crapParams{
 		patterns:        []string{"./..."},
 		format:          p.format,
 		opts:            crap.DefaultOptions(),
+		cfg:             cfg,
 		maxCrapload:     p.maxCrapload,
 		maxGazeCrapload: p.maxGazeCrapload,
 		moduleDir:       moduleDir,
@@ -1146,6 +1347,7 @@ func runSelfCheck(p selfCheckParams) error {
 		stderr:          p.stderr,
 	}
 	cp.opts.Stderr = p.stderr
+	cp.opts.GoTest = cfg.Coverage.GoTest
 
 	doCrap := p.runCrapFunc
 	if doCrap == nil {
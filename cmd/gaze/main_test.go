@@ -9,6 +9,9 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
 
 	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/crap"
@@ -454,6 +457,30 @@ func TestSchemaCmd_ContainsSchemaFields(t *testing.T) {
 	}
 }
 
+func TestSchemaCmd_IncludesCustomTypes(t *testing.T) {
+	cfgPath := filepath.Join(t.TempDir(), ".gaze.yaml")
+	cfgYAML := `detection:
+  rules:
+    - function: "example.com/mq.(*Producer).Send"
+      effect: EventPublish
+      tier: P2
+`
+	if err := os.WriteFile(cfgPath, []byte(cfgYAML), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := newSchemaCmd()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--config", cfgPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("schema command failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), `"EventPublish"`) {
+		t.Error("schema output missing custom type EventPublish")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // runDocscan tests
 // ---------------------------------------------------------------------------
@@ -726,7 +753,7 @@ func stubAnalyze(_ []string, _ string, _ crap.Options) (*crap.Report, error) {
 	return stubReport(), nil
 }
 
-func stubCoverageNil(_ []string, _ string, _ io.Writer) func(string, string) (float64, bool) {
+func stubCoverageNil(_ []string, _ string, _ *config.GazeConfig, _ io.Writer) func(taxonomy.FunctionID) (float64, bool) {
 	return nil
 }
 
@@ -753,6 +780,35 @@ func TestRunCrap_TextOutput(t *testing.T) {
 	}
 }
 
+// TestRunCrap_CoverageUsesConfig verifies that the contract coverage
+// pipeline receives the config loaded by the command rather than
+// loading its own.
+func TestRunCrap_CoverageUsesConfig(t *testing.T) {
+	cfg := config.DefaultConfig()
+	var got *config.GazeConfig
+	var stdout, stderr bytes.Buffer
+	err := runCrap(crapParams{
+		patterns:    []string{"./..."},
+		format:      "text",
+		opts:        crap.DefaultOptions(),
+		cfg:         cfg,
+		moduleDir:   ".",
+		stdout:      &stdout,
+		stderr:      &stderr,
+		analyzeFunc: stubAnalyze,
+		coverageFunc: func(_ []string, _ string, c *config.GazeConfig, _ io.Writer) func(taxonomy.FunctionID) (float64, bool) {
+			got = c
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("runCrap returned error: %v", err)
+	}
+	if got != cfg {
+		t.Errorf("coverage pipeline got config %p, want the command's config %p", got, cfg)
+	}
+}
+
 func TestRunCrap_JSONOutput(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	err := runCrap(crapParams{
@@ -914,6 +970,36 @@ func TestRunSelfCheck_HappyPath(t *testing.T) {
 	}
 }
 
+func TestRunSelfCheck_GoTestFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgYAML := "coverage:\n  go_test:\n    tags: [integration]\n    race: true\n"
+	if err := os.WriteFile(filepath.Join(dir, ".gaze.yaml"), []byte(cfgYAML), 0o644); err != nil {
+		t.Fatalf("writing config: %v", err)
+	}
+	t.Chdir(dir)
+
+	var delegatedParams crapParams
+	err := runSelfCheck(selfCheckParams{
+		format: "text",
+		stdout: &bytes.Buffer{},
+		stderr: &bytes.Buffer{},
+		moduleRootFunc: func() (string, error) {
+			return dir, nil
+		},
+		runCrapFunc: func(p crapParams) error {
+			delegatedParams = p
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("runSelfCheck returned error: %v", err)
+	}
+	g := delegatedParams.opts.GoTest
+	if strings.Join(g.Tags, ",") != "integration" || !g.Race {
+		t.Errorf("expected go test options from .gaze.yaml, got %+v", g)
+	}
+}
+
 func TestRunSelfCheck_ModuleRootError(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	err := runSelfCheck(selfCheckParams{
@@ -993,6 +1079,63 @@ func TestRunQuality_JSONFormat(t *testing.T) {
 	}
 }
 
+func TestRunQuality_CoverProfile(t *testing.T) {
+	// Classification reads the module from the working directory.
+	t.Chdir(filepath.Join("..", ".."))
+
+	// Add's body never ran; every other function is absent from
+	// the profile and so left unflagged.
+	profile := filepath.Join(t.TempDir(), "cover.out")
+	content := "mode: set\n" +
+		"github.com/unbound-force/gaze/internal/quality/testdata/src/welltested/welltested.go:9.24,11.2 1 0\n"
+	if err := os.WriteFile(profile, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing cover profile: %v", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	err := runQuality(qualityParams{
+		pkgPath:      "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+		format:       "json",
+		coverProfile: profile,
+		stdout:       &stdout,
+		stderr:       &stderr,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var output struct {
+		Summary taxonomy.PackageSummary `json:"quality_summary"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &output); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if output.Summary.NeverExecutedContracts == 0 {
+		t.Error("expected Add's return value to be flagged as never executed")
+	}
+	for _, fc := range output.Summary.FunctionCoverage {
+		for _, ec := range fc.Effects {
+			if ec.NeverExecuted && fc.Function.Function != "Add" {
+				t.Errorf("%s: effect %s flagged, but only Add is in the profile",
+					fc.Function.Function, ec.Type)
+			}
+		}
+	}
+}
+
+func TestRunQuality_CoverProfileMissing(t *testing.T) {
+	err := runQuality(qualityParams{
+		pkgPath:      "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+		format:       "text",
+		coverProfile: filepath.Join(t.TempDir(), "missing.out"),
+		stdout:       &bytes.Buffer{},
+		stderr:       &bytes.Buffer{},
+	})
+	if err == nil || !strings.Contains(err.Error(), "parsing coverage profile") {
+		t.Errorf("expected coverage profile error, got %v", err)
+	}
+}
+
 func TestRunQuality_TargetFlag(t *testing.T) {
 	var stdout, stderr bytes.Buffer
 	err := runQuality(qualityParams{
@@ -1056,6 +1199,66 @@ func TestRunQuality_BadPackage(t *testing.T) {
 	}
 }
 
+// writeTestModule creates a module named "test" in a temp directory
+// from the given file contents, keyed by slash-separated path, and
+// changes into it for the rest of the test.
+func writeTestModule(t *testing.T, files map[string]string) {
+	t.Helper()
+	dir := t.TempDir()
+	files["go.mod"] = "module test\n\ngo 1.24.2\n"
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("creating %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+	t.Chdir(dir)
+}
+
+func TestLoadTestPackages_SkipsBrokenPackage(t *testing.T) {
+	writeTestModule(t, map[string]string{
+		"good/good.go":      "package good\n\nfunc Add(a, b int) int { return a + b }\n",
+		"good/good_test.go": "package good\n\nimport \"testing\"\n\nfunc TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal(\"wrong sum\")\n\t}\n}\n",
+		"bad/bad.go":        "package bad\n\nfunc Broken() int { return undefinedName }\n",
+		"bad/bad_test.go":   "package bad\n\nimport \"testing\"\n\nfunc TestBroken(t *testing.T) { Broken() }\n",
+	})
+
+	var stderr bytes.Buffer
+	pkgs, err := loadTestPackages(&stderr, "./good", "./bad")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pkgs) != 1 || pkgs[0].PkgPath != "test/good" {
+		var paths []string
+		for _, pkg := range pkgs {
+			paths = append(paths, pkg.PkgPath)
+		}
+		t.Errorf("expected only test/good, got %v", paths)
+	}
+	warn := "warning: package test/bad has errors; its tests are not assessed"
+	if got := stderr.String(); strings.Count(got, warn) != 1 {
+		t.Errorf("expected one warning about test/bad, got:\n%s", got)
+	}
+}
+
+func TestLoadTestPackages_AllBroken(t *testing.T) {
+	writeTestModule(t, map[string]string{
+		"bad/bad.go":      "package bad\n\nfunc Broken() int { return undefinedName }\n",
+		"bad/bad_test.go": "package bad\n\nimport \"testing\"\n\nfunc TestBroken(t *testing.T) { Broken() }\n",
+	})
+
+	_, err := loadTestPackages(&bytes.Buffer{}, "./bad")
+	if err == nil {
+		t.Fatal("expected error when every package fails to load")
+	}
+	if !strings.Contains(err.Error(), "package test/bad has errors") {
+		t.Errorf("expected error naming test/bad, got: %v", err)
+	}
+}
+
 // ---------------------------------------------------------------------------
 // checkQualityThresholds tests (SC-005)
 // ---------------------------------------------------------------------------
@@ -1297,39 +1500,6 @@ func TestRunInit_ForceFlag(t *testing.T) {
 	}
 }
 
-// ---------------------------------------------------------------------------
-// extractShortPkgName tests
-// ---------------------------------------------------------------------------
-
-func TestExtractShortPkgName_WithSlash(t *testing.T) {
-	got := extractShortPkgName("github.com/unbound-force/gaze/internal/crap")
-	if got != "crap" {
-		t.Errorf("extractShortPkgName(...crap) = %q, want %q", got, "crap")
-	}
-}
-
-func TestExtractShortPkgName_NoSlash(t *testing.T) {
-	got := extractShortPkgName("main")
-	if got != "main" {
-		t.Errorf("extractShortPkgName(main) = %q, want %q", got, "main")
-	}
-}
-
-func TestExtractShortPkgName_TrailingSlash(t *testing.T) {
-	// Last segment after final slash is an empty string when path ends with /.
-	got := extractShortPkgName("github.com/user/repo/")
-	if got != "" {
-		t.Errorf("extractShortPkgName(.../repo/) = %q, want %q (empty)", got, "")
-	}
-}
-
-func TestExtractShortPkgName_Empty(t *testing.T) {
-	got := extractShortPkgName("")
-	if got != "" {
-		t.Errorf("extractShortPkgName('') = %q, want %q", got, "")
-	}
-}
-
 // ---------------------------------------------------------------------------
 // resolvePackagePaths tests (US4 — T023)
 // ---------------------------------------------------------------------------
@@ -1414,26 +1584,26 @@ func TestAnalyzePackageCoverage_ValidPackage(t *testing.T) {
 	}
 	gazeConfig := config.DefaultConfig()
 	var stderr bytes.Buffer
-	reports := analyzePackageCoverage(
-		"github.com/unbound-force/gaze/internal/quality/testdata/src/welltested",
+	coverage := analyzePackageCoverage(
+		[]string{"github.com/unbound-force/gaze/internal/quality/testdata/src/welltested"},
 		gazeConfig,
 		&stderr,
 	)
-	if len(reports) == 0 {
-		t.Error("expected non-nil quality reports for well-tested package")
+	if len(coverage) == 0 {
+		t.Error("expected function coverage for well-tested package")
 	}
 }
 
 func TestAnalyzePackageCoverage_InvalidPackage(t *testing.T) {
 	gazeConfig := config.DefaultConfig()
 	var stderr bytes.Buffer
-	reports := analyzePackageCoverage(
-		"github.com/nonexistent/does/not/exist",
+	coverage := analyzePackageCoverage(
+		[]string{"github.com/nonexistent/does/not/exist"},
 		gazeConfig,
 		&stderr,
 	)
-	if reports != nil {
-		t.Error("expected nil reports for non-existent package")
+	if coverage != nil {
+		t.Error("expected nil coverage for non-existent package")
 	}
 }
 
@@ -1448,6 +1618,7 @@ func TestBuildContractCoverageFunc_InvalidPattern(t *testing.T) {
 	fn := buildContractCoverageFunc(
 		[]string{"github.com/nonexistent/package/does/not/exist"},
 		t.TempDir(), // empty dir — packages.Load will find nothing
+		config.DefaultConfig(),
 		&buf,
 	)
 	// Either nil (no packages resolved) or a valid closure that
@@ -1456,9 +1627,9 @@ func TestBuildContractCoverageFunc_InvalidPattern(t *testing.T) {
 	if fn != nil {
 		// If a closure was returned, it must not panic and must
 		// return ok=false for an unknown key.
-		_, ok := fn("nonexistent", "Foo")
+		_, ok := fn("github.com/nonexistent/package.Foo")
 		if ok {
-			t.Error("expected ok=false for unknown pkg:func key")
+			t.Error("expected ok=false for unknown FunctionID")
 		}
 	}
 }
@@ -1475,7 +1646,7 @@ func TestBuildContractCoverageFunc_WelltestedPackage(t *testing.T) {
 	pattern := "github.com/unbound-force/gaze/internal/quality/testdata/src/welltested"
 
 	var buf bytes.Buffer
-	fn := buildContractCoverageFunc([]string{pattern}, ".", &buf)
+	fn := buildContractCoverageFunc([]string{pattern}, ".", config.DefaultConfig(), &buf)
 
 	if fn == nil {
 		t.Fatal("buildContractCoverageFunc returned nil; expected non-nil closure for well-tested package")
@@ -1483,7 +1654,7 @@ func TestBuildContractCoverageFunc_WelltestedPackage(t *testing.T) {
 
 	// The closure must be callable without panic and return coverage
 	// data for known functions in the welltested fixture.
-	pct, ok := fn("welltested", "Add")
+	pct, ok := fn("github.com/unbound-force/gaze/internal/quality/testdata/src/welltested.Add")
 	t.Logf("welltested:Add contract coverage: %.1f%% (found=%v)", pct, ok)
 	if !ok {
 		t.Fatal("expected ok=true for welltested:Add, got ok=false")
@@ -1492,3 +1663,94 @@ func TestBuildContractCoverageFunc_WelltestedPackage(t *testing.T) {
 		t.Errorf("expected pct > 0 for welltested:Add (well-tested fixture should have non-zero coverage), got %.1f", pct)
 	}
 }
+
+// TestCrapCmd_CoverFlagsKeepCommas verifies that --coverprofile and
+// --covdata take each value as one path, so paths containing commas
+// are not split.
+func TestCrapCmd_CoverFlagsKeepCommas(t *testing.T) {
+	cmd := newCrapCmd()
+	err := cmd.ParseFlags([]string{
+		"--coverprofile=unit,a.out", "--coverprofile=e2e.out", "--covdata=cov,dir",
+	})
+	if err != nil {
+		t.Fatalf("ParseFlags failed: %v", err)
+	}
+	profiles, _ := cmd.Flags().GetStringArray("coverprofile")
+	if fmt.Sprint(profiles) != "[unit,a.out e2e.out]" {
+		t.Errorf("coverprofile = %q, want [unit,a.out e2e.out]", profiles)
+	}
+	dirs, _ := cmd.Flags().GetStringArray("covdata")
+	if fmt.Sprint(dirs) != "[cov,dir]" {
+		t.Errorf("covdata = %q, want [cov,dir]", dirs)
+	}
+}
+
+// ---------------------------------------------------------------------------
+// goTestFlags tests
+// ---------------------------------------------------------------------------
+
+// parseGoTestFlags registers the go test flags on a fresh command,
+// parses args and applies them over goTest.
+func parseGoTestFlags(t *testing.T, goTest *config.GoTestConfig, args ...string) error {
+	t.Helper()
+	var f goTestFlags
+	cmd := &cobra.Command{Use: "crap"}
+	f.register(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v) failed: %v", args, err)
+	}
+	return f.apply(cmd, goTest)
+}
+
+func TestGoTestFlags_OverrideConfig(t *testing.T) {
+	goTest := config.GoTestConfig{
+		Tags:     []string{"integration"},
+		Race:     true,
+		Timeout:  time.Minute,
+		CoverPkg: "./internal/...",
+		Env:      map[string]string{"DB": "sqlite", "MODE": "ci"},
+	}
+	err := parseGoTestFlags(t, &goTest,
+		"--tags=e2e,slow", "--count=1", "--race=false", "--env=MODE=local", "--env=EXTRA=a=b")
+	if err != nil {
+		t.Fatalf("apply failed: %v", err)
+	}
+
+	if strings.Join(goTest.Tags, ",") != "e2e,slow" {
+		t.Errorf("Tags = %v, want [e2e slow]", goTest.Tags)
+	}
+	if goTest.Race {
+		t.Error("Race = true, want false from --race=false")
+	}
+	if goTest.Count != 1 {
+		t.Errorf("Count = %d, want 1", goTest.Count)
+	}
+	// Flags left unset keep the config's values.
+	if goTest.Timeout != time.Minute || goTest.CoverPkg != "./internal/..." {
+		t.Errorf("Timeout, CoverPkg = %v, %q, want config values", goTest.Timeout, goTest.CoverPkg)
+	}
+	want := map[string]string{"DB": "sqlite", "MODE": "local", "EXTRA": "a=b"}
+	if fmt.Sprint(goTest.Env) != fmt.Sprint(want) {
+		t.Errorf("Env = %v, want %v", goTest.Env, want)
+	}
+}
+
+func TestGoTestFlags_Invalid(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{"--count=-1", "--count=-1 is invalid"},
+		{"--env=NOVALUE", `--env="NOVALUE" is invalid`},
+		{"--env==x", `--env="=x" is invalid`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			var goTest config.GoTestConfig
+			err := parseGoTestFlags(t, &goTest, tt.arg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("apply(%s) error = %v, want %q", tt.arg, err, tt.want)
+			}
+		})
+	}
+}
@@ -60,18 +60,28 @@ type pkgCacheEntry struct {
 	ssaPkg *ssa.Package
 }
 
+// pkgCacheSize bounds the number of fixtures the cache holds. Each
+// fixture is type-checked together with its dependencies from
+// source, so keeping every fixture for the whole run exhausts memory.
+// Tests using the same fixture sit next to each other, so keeping the
+// few most recently used ones preserves nearly all of the reuse.
+const pkgCacheSize = 4
+
 var (
-	pkgCacheMu sync.Mutex
-	pkgCache   = make(map[string]*pkgCacheEntry)
+	pkgCacheMu     sync.Mutex
+	pkgCache       = make(map[string]*pkgCacheEntry)
+	pkgCacheRecent []string // cached fixture names, least recently used first
 )
 
 // cachedTestPackage returns a cached package + SSA pair, loading
-// and building SSA only once per fixture package across all tests.
+// and building SSA only when the fixture is not among the most
+// recently used ones.
 func cachedTestPackage(pkgName string) (*pkgCacheEntry, error) {
 	pkgCacheMu.Lock()
 	defer pkgCacheMu.Unlock()
 
 	if entry, ok := pkgCache[pkgName]; ok {
+		touchCachedPackage(pkgName)
 		return entry, nil
 	}
 
@@ -81,11 +91,28 @@ func cachedTestPackage(pkgName string) (*pkgCacheEntry, error) {
 	}
 	ssaPkg := analysis.BuildSSA(pkg)
 
+	if len(pkgCacheRecent) == pkgCacheSize {
+		delete(pkgCache, pkgCacheRecent[0])
+		pkgCacheRecent = pkgCacheRecent[1:]
+	}
 	entry := &pkgCacheEntry{pkg: pkg, ssaPkg: ssaPkg}
 	pkgCache[pkgName] = entry
+	pkgCacheRecent = append(pkgCacheRecent, pkgName)
 	return entry, nil
 }
 
+// touchCachedPackage marks pkgName as the most recently used fixture.
+// The caller holds pkgCacheMu.
+func touchCachedPackage(pkgName string) {
+	for i, name := range pkgCacheRecent {
+		if name == pkgName {
+			pkgCacheRecent = append(pkgCacheRecent[:i], pkgCacheRecent[i+1:]...)
+			break
+		}
+	}
+	pkgCacheRecent = append(pkgCacheRecent, pkgName)
+}
+
 // loadTestPackage loads one of the test fixture packages from testdata.
 func loadTestPackage(t *testing.T, pkgName string) *packages.Package {
 	t.Helper()
@@ -464,6 +491,11 @@ func TestMutation_DeepNestedMutation(t *testing.T) {
 	e := effectWithTarget(result.SideEffects, taxonomy.ReceiverMutation, "Nested")
 	if e == nil {
 		t.Error("expected ReceiverMutation for field 'Nested' (deep nested mutation)")
+		return
+	}
+	// The nested path written is recorded for field coverage.
+	if len(e.Fields) != 1 || e.Fields[0] != "Nested.Value" {
+		t.Errorf("Fields = %v, want [Nested.Value]", e.Fields)
 	}
 }
 
@@ -13,6 +13,7 @@ import (
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 
+	"github.com/unbound-force/gaze/internal/config"
 	"github.com/unbound-force/gaze/internal/loader"
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
@@ -30,6 +31,10 @@ type Options struct {
 	// Version is the Gaze version string to embed in metadata.
 	// If empty, defaults to "dev".
 	Version string
+
+	// Rules are the custom detection rules from .gaze.yaml, applied
+	// in addition to the built-in detectors.
+	Rules []config.EffectRule
 }
 
 // Analyze performs side effect analysis on all functions in the
@@ -44,6 +49,9 @@ func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, er
 	// reconstruction per function.
 	ssaPkg := BuildSSA(pkg)
 
+	// Compile the detection rules once rather than per function.
+	rules := newRuleSet(opts.Rules)
+
 	var results []taxonomy.AnalysisResult
 
 	for _, file := range pkg.Syntax {
@@ -61,7 +69,7 @@ func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, er
 				continue
 			}
 
-			result := analyzeFunction(fset, pkg, ssaPkg, fd)
+			result := analyzeFunction(fset, pkg, ssaPkg, fd, rules)
 			results = append(results, result)
 		}
 
@@ -79,6 +87,7 @@ func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, er
 				fileName := fset.Position(file.Pos()).Filename
 				results = append(results, taxonomy.AnalysisResult{
 					Target: taxonomy.FunctionTarget{
+						ID:       taxonomy.NewFunctionID(pkg.PkgPath, "", "<package>"),
 						Package:  pkg.PkgPath,
 						Function: "<package>",
 						Location: fileName,
@@ -94,6 +103,7 @@ func Analyze(pkg *packages.Package, opts Options) ([]taxonomy.AnalysisResult, er
 	for i := range results {
 		results[i].Metadata = buildMetadata(start, opts.Version)
 	}
+	taxonomy.DisambiguateIDs(results)
 
 	return results, nil
 }
@@ -127,39 +137,48 @@ func AnalyzeFunctionWithSSA(
 		ssaPkg = BuildSSA(pkg)
 	}
 
-	result := analyzeFunction(fset, pkg, ssaPkg, fd)
+	result := analyzeFunction(fset, pkg, ssaPkg, fd, nil)
 	result.Metadata = buildMetadata(start, "")
 	return result
 }
 
-// analyzeFunction runs all analyzers on a single function declaration.
+// analyzeFunction runs all analyzers on a single function declaration,
+// including the custom detection rules.
 func analyzeFunction(
 	fset *token.FileSet,
 	pkg *packages.Package,
 	ssaPkg *ssa.Package,
 	fd *ast.FuncDecl,
+	rules *ruleSet,
 ) taxonomy.AnalysisResult {
-	funcName := fd.Name.Name
 	pkgPath := pkg.PkgPath
 
 	target := taxonomy.FunctionTarget{
+		ID:        taxonomy.NewFunctionID(pkgPath, receiverName(fd), fd.Name.Name),
 		Package:   pkgPath,
-		Function:  funcName,
+		Function:  fd.Name.Name,
 		Receiver:  receiverName(fd),
 		Signature: funcSignature(fset, fd),
 		Location:  fset.Position(fd.Pos()).String(),
 	}
+	// Effect IDs hash the receiver-qualified name, so that methods
+	// of different types with the same name do not share IDs.
+	funcName := target.QualifiedName()
 
 	var effects []taxonomy.SideEffect
 
 	// 1. Return value analysis (AST-based).
 	returnEffects := AnalyzeReturns(fset, pkg.TypesInfo, fd, pkgPath, funcName)
 	effects = append(effects, returnEffects...)
+	effects = append(effects, AnalyzeErrorWrapping(fset, pkg.TypesInfo, fd, pkgPath, funcName)...)
+	effects = append(effects, AnalyzeGRPCStatus(fset, pkg.TypesInfo, fd, pkgPath, funcName)...)
 
 	// 2. Mutation analysis (SSA-based).
 	obj := pkg.TypesInfo.Defs[fd.Name]
 	if obj != nil {
 		if fnObj, ok := obj.(*types.Func); ok {
+			AnalyzeValueDomains(ssaPkg, fd, fnObj, effects)
+			AnalyzeReturnedFields(ssaPkg, fd, fnObj, effects)
 			mutationEffects := AnalyzeMutations(fset, ssaPkg, fd, fnObj, pkgPath, funcName)
 			effects = append(effects, mutationEffects...)
 		}
@@ -168,17 +187,70 @@ func analyzeFunction(
 	// 3. P1-tier effects (AST-based).
 	p1Effects := AnalyzeP1Effects(fset, pkg.TypesInfo, fd, pkgPath, funcName)
 	effects = append(effects, p1Effects...)
+	effects = append(effects, AnalyzeCancellation(fset, pkg.TypesInfo, fd, pkgPath, funcName)...)
+	effects = append(effects, AnalyzeHTTPHandler(fset, pkg.TypesInfo, fd, pkgPath, funcName)...)
 
 	// 4. P2-tier effects (AST-based).
 	p2Effects := AnalyzeP2Effects(fset, pkg.TypesInfo, fd, pkgPath, funcName)
 	effects = append(effects, p2Effects...)
 
+	// 5. Network, messaging, and metric effects (AST-based).
+	networkEffects := AnalyzeNetworkEffects(fset, pkg.TypesInfo, fd, pkgPath, funcName)
+	effects = append(effects, networkEffects...)
+
+	// 6. Custom detection rules (AST-based). A rule that matches a
+	// call a built-in detector already reported (e.g. a rule pack
+	// covering slog) would duplicate its effect, so it is dropped.
+	builtin := make(map[string]bool, len(effects))
+	for _, e := range effects {
+		for _, loc := range sites(e) {
+			builtin[string(e.Type)+"@"+loc] = true
+		}
+	}
+	callEffects := networkEffects
+	for _, e := range analyzeRuleEffects(fset, pkg.TypesInfo, fd, pkgPath, funcName, rules) {
+		if !builtin[string(e.Type)+"@"+e.Location] {
+			effects = append(effects, e)
+			callEffects = append(callEffects, e)
+		}
+	}
+
+	// 7. Occurrence sites and the conditions guarding them (SSA).
+	// Detectors report each site; sites sharing an ID are one effect.
+	effects = mergeOccurrences(supersedeInteractions(effects, callEffects))
+	fnObj, _ := obj.(*types.Func)
+	AnalyzeOccurrences(fset, ssaPkg, fd, fnObj, effects)
+
 	return taxonomy.AnalysisResult{
 		Target:      target,
 		SideEffects: effects,
 	}
 }
 
+// supersedeInteractions drops the InterfaceInteraction effects of
+// calls that a more specific detector or rule also reported (a
+// prometheus.Counter field's Inc is a MetricEmit, a pgx.Tx parameter's
+// Exec is a DatabaseWrite), keeping the more specific effect.
+func supersedeInteractions(effects, specific []taxonomy.SideEffect) []taxonomy.SideEffect {
+	if len(specific) == 0 {
+		return effects
+	}
+	locations := make(map[string]bool, len(specific))
+	for _, e := range specific {
+		for _, loc := range sites(e) {
+			locations[loc] = true
+		}
+	}
+	kept := effects[:0]
+	for _, e := range effects {
+		if e.Type == taxonomy.InterfaceInteraction && locations[e.Location] {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	return kept
+}
+
 // buildMetadata creates analysis metadata with current timing.
 func buildMetadata(start time.Time, version string) taxonomy.Metadata {
 	if version == "" {
@@ -0,0 +1,164 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// derivingContextFuncs are the context package functions whose result
+// is cancelled when their parent is, so that honouring the derived
+// context honours the parameter. context.WithoutCancel is excluded.
+var derivingContextFuncs = map[string]bool{
+	"WithCancel":        true,
+	"WithCancelCause":   true,
+	"WithTimeout":       true,
+	"WithTimeoutCause":  true,
+	"WithDeadline":      true,
+	"WithDeadlineCause": true,
+	"WithValue":         true,
+}
+
+// AnalyzeCancellation detects whether a function that takes a
+// context.Context honours its cancellation: it waits on ctx.Done()
+// (typically in a select), checks ctx.Err(), returns
+// context.Canceled or context.DeadlineExceeded, or passes the context
+// (or one derived from it) on to its callees. A function that does any
+// of these produces a RespectsCancellation effect on the context
+// parameter, listing the behaviors found.
+//
+// Unlike ContextCancellation, which records that a function creates
+// a cancellable context, RespectsCancellation is about the caller's
+// context: cancelling it is expected to stop the function.
+func AnalyzeCancellation(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil || fd.Type.Params == nil {
+		return nil
+	}
+
+	var param *ast.Ident
+	ctxs := make(map[types.Object]bool)
+	for _, field := range fd.Type.Params.List {
+		for _, name := range field.Names {
+			obj := info.Defs[name]
+			if name.Name == "_" || obj == nil || !isContextType(obj.Type()) {
+				continue
+			}
+			ctxs[obj] = true
+			if param == nil {
+				param = name
+			}
+		}
+	}
+	if param == nil {
+		return nil
+	}
+
+	var behaviors []string
+	isCtx := func(expr ast.Expr) bool {
+		ident, ok := ast.Unparen(expr).(*ast.Ident)
+		return ok && ctxs[info.Uses[ident]]
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			// ctx, cancel := context.WithTimeout(ctx, d)
+			if len(node.Rhs) != 1 {
+				return true
+			}
+			call, ok := node.Rhs[0].(*ast.CallExpr)
+			if !ok || len(call.Args) == 0 || !isCtx(call.Args[0]) {
+				return true
+			}
+			if fn := calledFunc(call, info); fn != nil && fn.Pkg() != nil &&
+				fn.Pkg().Path() == "context" && derivingContextFuncs[fn.Name()] {
+				if ident, ok := node.Lhs[0].(*ast.Ident); ok {
+					if obj := info.ObjectOf(ident); obj != nil {
+						ctxs[obj] = true
+					}
+				}
+			}
+
+		case *ast.UnaryExpr:
+			// <-ctx.Done(), in a select case or on its own.
+			if node.Op != token.ARROW {
+				return true
+			}
+			if call, ok := node.X.(*ast.CallExpr); ok && isContextMethodCall(call, "Done", isCtx) {
+				behaviors = appendUnique(behaviors, "waits on "+types.ExprString(call.Fun)+"()")
+			}
+
+		case *ast.CallExpr:
+			if isContextMethodCall(node, "Err", isCtx) {
+				behaviors = appendUnique(behaviors, "checks "+types.ExprString(node.Fun)+"()")
+				return true
+			}
+			if isContextMethodCall(node, "Done", isCtx) {
+				return true
+			}
+			fn := calledFunc(node, info)
+			if fn != nil && fn.Pkg() != nil && fn.Pkg().Path() == "context" {
+				return true
+			}
+			for _, arg := range node.Args {
+				if !isCtx(arg) {
+					continue
+				}
+				callee := types.ExprString(node.Fun)
+				if fn != nil && fn.Pkg() != nil {
+					callee = ruleTarget(fn)
+				}
+				behaviors = appendUnique(behaviors, "passes "+types.ExprString(arg)+" to "+callee)
+				break
+			}
+
+		case *ast.SelectorExpr:
+			if v, ok := info.Uses[node.Sel].(*types.Var); ok && v.Pkg() != nil && v.Pkg().Path() == "context" &&
+				(v.Name() == "Canceled" || v.Name() == "DeadlineExceeded") {
+				behaviors = appendUnique(behaviors, "returns context."+v.Name())
+			}
+		}
+		return true
+	})
+
+	if len(behaviors) == 0 {
+		return nil
+	}
+
+	loc := fset.Position(param.Pos()).String()
+	return []taxonomy.SideEffect{{
+		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.RespectsCancellation), "cancellation:"+param.Name),
+		Type:        taxonomy.RespectsCancellation,
+		Tier:        taxonomy.TierOf(taxonomy.RespectsCancellation),
+		Location:    loc,
+		Description: fmt.Sprintf("respects cancellation of %s: %s", param.Name, strings.Join(behaviors, ", ")),
+		Target:      param.Name,
+	}}
+}
+
+// isContextType reports whether t is context.Context.
+func isContextType(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// isContextMethodCall reports whether call is ctx.<method>() on one
+// of the tracked contexts.
+func isContextMethodCall(call *ast.CallExpr, method string, isCtx func(ast.Expr) bool) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == method && len(call.Args) == 0 && isCtx(sel.X)
+}
@@ -0,0 +1,55 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeCancellation verifies that functions taking a context
+// are reported as respecting its cancellation when they wait on
+// Done, check Err, return context.Canceled, or pass it (or a derived
+// context) on, and that functions that ignore or detach it are not.
+func TestAnalyzeCancellation(t *testing.T) {
+	pkg := loadTestPackage(t, "cancellation")
+
+	tests := []struct {
+		function  string
+		behaviors []string
+	}{
+		{"Wait", []string{"waits on ctx.Done()", "checks ctx.Err()"}},
+		{"Poll", []string{"checks ctx.Err()", "returns context.Canceled"}},
+		{"Fetch", []string{"passes ctx to http.NewRequestWithContext"}},
+		{"Ignore", nil},
+		{"Detach", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in cancellation package", tt.function)
+			}
+			effects := analysis.AnalyzeCancellation(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function)
+			if tt.behaviors == nil {
+				if len(effects) != 0 {
+					t.Errorf("expected no effects, got %v", effects)
+				}
+				return
+			}
+			if len(effects) != 1 {
+				t.Fatalf("got %d effects %v, want 1", len(effects), effects)
+			}
+			e := effects[0]
+			if e.Type != taxonomy.RespectsCancellation || e.Tier != taxonomy.TierP1 || e.Target != "ctx" {
+				t.Errorf("got {%s %s %s}, want {RespectsCancellation P1 ctx}", e.Type, e.Tier, e.Target)
+			}
+			for _, b := range tt.behaviors {
+				if !strings.Contains(e.Description, b) {
+					t.Errorf("description %q missing %q", e.Description, b)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,339 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// Error wrapping modes, as they appear in ErrorWrap descriptions.
+const (
+	wrapModeWrap    = "wraps"
+	wrapModeJoin    = "joins"
+	wrapModeFlatten = "flattens"
+)
+
+// AnalyzeErrorWrapping detects how a function's returned errors relate
+// to the errors it received from its callees. A fmt.Errorf with %w or
+// an errors.Join keeps the upstream errors visible to errors.Is and
+// errors.As; a fmt.Errorf that formats an error with %v or %s flattens
+// it into text. Either choice is part of the function's contract, so
+// each returned fmt.Errorf or errors.Join that takes an error produces
+// an ErrorWrap effect naming the upstream errors and the mode.
+//
+// Only calls whose result is returned, directly or through a local
+// variable, are considered.
+func AnalyzeErrorWrapping(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil || !returnsError(info, fd) {
+		return nil
+	}
+
+	assigns := collectErrorAssignments(fd.Body, info)
+	returned := returnedErrorExprs(fd.Body, info)
+
+	var effects []taxonomy.SideEffect
+	seen := make(map[token.Pos]bool)
+
+	var visit func(expr ast.Expr)
+	visit = func(expr ast.Expr) {
+		expr = ast.Unparen(expr)
+		if ident, ok := expr.(*ast.Ident); ok {
+			// err = fmt.Errorf(...); return err
+			for _, rhs := range assigns[info.Uses[ident]] {
+				if _, ok := rhs.(*ast.CallExpr); ok {
+					visit(rhs)
+				}
+			}
+			return
+		}
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		mode, upstream := classifyErrorWrap(call, info)
+		if mode == "" || len(upstream) == 0 {
+			return
+		}
+
+		var origins, sentinels []string
+		for _, u := range upstream {
+			names, sentinel := errorOrigins(u, info, assigns)
+			for _, o := range names {
+				origins = appendUnique(origins, o)
+				if sentinel {
+					sentinels = appendUnique(sentinels, o)
+				}
+			}
+		}
+		target := strings.Join(origins, ", ")
+		if seen[call.Pos()] {
+			return
+		}
+		seen[call.Pos()] = true
+		key := fmt.Sprintf("%s:%s", mode, target)
+
+		var desc string
+		switch mode {
+		case wrapModeWrap:
+			desc = fmt.Sprintf("wraps errors from %s via %%w", target)
+		case wrapModeJoin:
+			desc = fmt.Sprintf("joins errors from %s via errors.Join", target)
+		default:
+			desc = fmt.Sprintf("flattens errors from %s into text (not matchable with errors.Is/As)", target)
+		}
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ErrorWrap), key),
+			Type:        taxonomy.ErrorWrap,
+			Tier:        taxonomy.TierP1,
+			Location:    fset.Position(call.Pos()).String(),
+			Description: desc,
+			Target:      target,
+			Sentinels:   sentinels,
+		})
+	}
+	for _, expr := range returned {
+		visit(expr)
+	}
+
+	return mergeOccurrences(effects)
+}
+
+// returnsError reports whether any of fd's results is of type error.
+func returnsError(info *types.Info, fd *ast.FuncDecl) bool {
+	if fd.Type.Results == nil {
+		return false
+	}
+	for _, field := range fd.Type.Results.List {
+		if isErrorType(info, field.Type) {
+			return true
+		}
+	}
+	return false
+}
+
+// returnedErrorExprs returns the error-typed expressions of the
+// function's return statements, skipping those of nested function
+// literals.
+func returnedErrorExprs(body *ast.BlockStmt, info *types.Info) []ast.Expr {
+	var exprs []ast.Expr
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			for _, r := range node.Results {
+				if isErrorValue(info, r) {
+					exprs = append(exprs, r)
+				}
+			}
+		}
+		return true
+	})
+	return exprs
+}
+
+// collectErrorAssignments maps each local variable to the expressions
+// assigned to it. For a multi-value assignment from a single call
+// (f, err := os.Open(name)), every variable maps to the call.
+func collectErrorAssignments(body *ast.BlockStmt, info *types.Info) map[types.Object][]ast.Expr {
+	assigns := make(map[types.Object][]ast.Expr)
+	record := func(lhs []ast.Expr, rhs []ast.Expr) {
+		for i, l := range lhs {
+			ident, ok := l.(*ast.Ident)
+			if !ok || ident.Name == "_" {
+				continue
+			}
+			obj := info.ObjectOf(ident)
+			if obj == nil {
+				continue
+			}
+			switch {
+			case len(rhs) == len(lhs):
+				assigns[obj] = append(assigns[obj], rhs[i])
+			case len(rhs) == 1:
+				assigns[obj] = append(assigns[obj], rhs[0])
+			}
+		}
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			record(node.Lhs, node.Rhs)
+		case *ast.ValueSpec:
+			lhs := make([]ast.Expr, len(node.Names))
+			for i, name := range node.Names {
+				lhs[i] = name
+			}
+			record(lhs, node.Values)
+		}
+		return true
+	})
+	return assigns
+}
+
+// classifyErrorWrap returns the wrapping mode of a fmt.Errorf or
+// errors.Join call and the error-valued arguments it wraps or
+// flattens. It returns an empty mode for other calls.
+func classifyErrorWrap(call *ast.CallExpr, info *types.Info) (string, []ast.Expr) {
+	fn := calledFunc(call, info)
+	if fn == nil || fn.Pkg() == nil {
+		return "", nil
+	}
+	switch {
+	case fn.Pkg().Path() == "errors" && fn.Name() == "Join":
+		var errs []ast.Expr
+		for _, arg := range call.Args {
+			if isErrorValue(info, arg) {
+				errs = append(errs, arg)
+			}
+		}
+		return wrapModeJoin, errs
+
+	case fn.Pkg().Path() == "fmt" && fn.Name() == "Errorf":
+		if len(call.Args) == 0 {
+			return "", nil
+		}
+		tv, ok := info.Types[call.Args[0]]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return "", nil
+		}
+		verbs := formatVerbs(constant.StringVal(tv.Value))
+		var wrapped, flattened []ast.Expr
+		for i, arg := range call.Args[1:] {
+			if i >= len(verbs) {
+				break
+			}
+			if verbs[i] == 'w' {
+				wrapped = append(wrapped, arg)
+				continue
+			}
+			if errArg := flattenedError(arg, info); errArg != nil && strings.ContainsRune("vsq", verbs[i]) {
+				flattened = append(flattened, errArg)
+			}
+		}
+		if len(wrapped) > 0 {
+			return wrapModeWrap, wrapped
+		}
+		return wrapModeFlatten, flattened
+	}
+	return "", nil
+}
+
+// flattenedError returns the error an fmt argument formats: the
+// argument itself when it is an error, or the receiver of an
+// err.Error() call. It returns nil for other arguments.
+func flattenedError(arg ast.Expr, info *types.Info) ast.Expr {
+	if isErrorValue(info, arg) {
+		return arg
+	}
+	call, ok := arg.(*ast.CallExpr)
+	if !ok || len(call.Args) != 0 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if ok && sel.Sel.Name == "Error" && isErrorValue(info, sel.X) {
+		return sel.X
+	}
+	return nil
+}
+
+// formatVerbs returns the verb letter consuming each argument of a
+// printf-style format string, in argument order. A "*" width or
+// precision consumes an argument and is reported as '*'.
+func formatVerbs(format string) []rune {
+	var verbs []rune
+	runes := []rune(format)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			continue
+		}
+		for i++; i < len(runes); i++ {
+			c := runes[i]
+			if c == '*' {
+				verbs = append(verbs, '*')
+				continue
+			}
+			if c == '%' {
+				break
+			}
+			if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') {
+				verbs = append(verbs, c)
+				break
+			}
+		}
+	}
+	return verbs
+}
+
+// errorOrigins names where an error expression comes from: the
+// function or method that returned it (os.Open, Store.Get), the
+// sentinel it refers to (ErrNotFound, io.EOF), or the variable name
+// when its origin is not visible in the function (a parameter).
+// sentinel reports whether the error is a package-level variable.
+func errorOrigins(expr ast.Expr, info *types.Info, assigns map[types.Object][]ast.Expr) (names []string, sentinel bool) {
+	expr = ast.Unparen(expr)
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		if fn := calledFunc(e, info); fn != nil && fn.Pkg() != nil {
+			return []string{ruleTarget(fn)}, false
+		}
+	case *ast.SelectorExpr:
+		if v, ok := info.Uses[e.Sel].(*types.Var); ok && isPackageVar(v) {
+			return []string{v.Pkg().Name() + "." + v.Name()}, true
+		}
+	case *ast.Ident:
+		obj := info.Uses[e]
+		if obj == nil {
+			break
+		}
+		if isPackageVar(obj) {
+			return []string{obj.Name()}, true
+		}
+		var origins []string
+		for _, rhs := range assigns[obj] {
+			// Skip err = fmt.Errorf("...: %w", err) re-wrapping the
+			// same variable.
+			if call, ok := ast.Unparen(rhs).(*ast.CallExpr); ok {
+				if mode, _ := classifyErrorWrap(call, info); mode != "" {
+					continue
+				}
+				if fn := calledFunc(call, info); fn != nil && fn.Pkg() != nil {
+					origins = appendUnique(origins, ruleTarget(fn))
+				}
+			}
+		}
+		if len(origins) > 0 {
+			return origins, false
+		}
+		return []string{e.Name}, false
+	}
+	return []string{types.ExprString(expr)}, false
+}
+
+// isErrorValue reports whether expr is a value of type error.
+func isErrorValue(info *types.Info, expr ast.Expr) bool {
+	tv, ok := info.Types[expr]
+	return ok && tv.IsValue() && tv.Type != nil && tv.Type.String() == "error"
+}
+
+// appendUnique appends s to list unless it is already present.
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
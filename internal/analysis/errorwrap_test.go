@@ -0,0 +1,81 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeErrorWrapping verifies that returned fmt.Errorf and
+// errors.Join calls produce ErrorWrap effects naming the upstream
+// errors, which of them are sentinels, and whether they are wrapped,
+// joined, or flattened.
+func TestAnalyzeErrorWrapping(t *testing.T) {
+	pkg := loadTestPackage(t, "errwrap")
+
+	tests := []struct {
+		function  string
+		target    string
+		desc      string
+		sentinels []string
+	}{
+		{"Open", "os.Open", "wraps errors from os.Open via %w", nil},
+		{"Lookup", "ErrNotFound", "wraps errors from ErrNotFound via %w", []string{"ErrNotFound"}},
+		{"Flatten", "os.Remove", "flattens errors from os.Remove", nil},
+		{"CloseAll", "Closer.Close", "joins errors from Closer.Close via errors.Join", nil},
+		{"Rewrap", "os.Stat", "wraps errors from os.Stat via %w", nil},
+		{"Annotate", "err", "wraps errors from err via %w", nil},
+		{"Escaped", "err", "flattens errors from err", nil},
+		{"Plain", "", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in errwrap package", tt.function)
+			}
+			effects := analysis.AnalyzeErrorWrapping(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function)
+			if tt.target == "" {
+				if len(effects) != 0 {
+					t.Errorf("expected no effects, got %v", effects)
+				}
+				return
+			}
+			if len(effects) != 1 {
+				t.Fatalf("got %d effects %v, want 1", len(effects), effects)
+			}
+			e := effects[0]
+			if e.Type != taxonomy.ErrorWrap || e.Tier != taxonomy.TierP1 {
+				t.Errorf("got %s/%s, want ErrorWrap/P1", e.Type, e.Tier)
+			}
+			if e.Target != tt.target {
+				t.Errorf("target = %q, want %q", e.Target, tt.target)
+			}
+			if !strings.HasPrefix(e.Description, tt.desc) {
+				t.Errorf("description = %q, want prefix %q", e.Description, tt.desc)
+			}
+			if strings.Join(e.Sentinels, ",") != strings.Join(tt.sentinels, ",") {
+				t.Errorf("sentinels = %v, want %v", e.Sentinels, tt.sentinels)
+			}
+		})
+	}
+}
+
+// TestAnalyze_ErrorWrapAlongsideErrorReturn verifies that Analyze
+// reports the ErrorWrap effect next to the ErrorReturn it describes.
+func TestAnalyze_ErrorWrapAlongsideErrorReturn(t *testing.T) {
+	pkg := loadTestPackage(t, "errwrap")
+	results, err := analysis.Analyze(pkg, analysis.Options{FunctionFilter: "Open"})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	effects := results[0].SideEffects
+	if !hasEffect(effects, taxonomy.ErrorReturn) || !hasEffect(effects, taxonomy.ErrorWrap) {
+		t.Errorf("expected ErrorReturn and ErrorWrap, got %v", effects)
+	}
+}
@@ -0,0 +1,156 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// AnalyzeReturnedFields records, for each ReturnValue position of
+// struct or pointer-to-struct type, the fields the function sets on
+// the value it returns. Fields are found through the stores SSA
+// emits for composite literals (&Config{Name: n}) and for later
+// assignments to the same allocation (cfg.Port = 8080), so a
+// constructor that fills ten fields has ten entries while a test
+// asserting only cfg.Name covers one of them.
+//
+// effects are matched to result positions as in AnalyzeValueDomains.
+// Returns of values the function did not allocate (parameters,
+// results of calls) contribute no fields.
+func AnalyzeReturnedFields(
+	ssaPkg *ssa.Package,
+	fd *ast.FuncDecl,
+	fnObj *types.Func,
+	effects []taxonomy.SideEffect,
+) {
+	if ssaPkg == nil || fd.Body == nil {
+		return
+	}
+	fn := findSSAFunction(ssaPkg, fnObj, fd)
+	if fn == nil {
+		return
+	}
+	returns := returnInstrs(fn)
+	if len(returns) == 0 {
+		return
+	}
+
+	pos := 0
+	for i := range effects {
+		switch effects[i].Type {
+		case taxonomy.ReturnValue:
+			effects[i].Fields = returnedFields(returns, pos)
+		case taxonomy.ErrorReturn:
+		default:
+			continue
+		}
+		pos++
+	}
+}
+
+// returnInstrs returns the Return instructions of fn.
+func returnInstrs(fn *ssa.Function) []*ssa.Return {
+	var returns []*ssa.Return
+	for _, b := range fn.Blocks {
+		if len(b.Instrs) == 0 {
+			continue
+		}
+		if ret, ok := b.Instrs[len(b.Instrs)-1].(*ssa.Return); ok {
+			returns = append(returns, ret)
+		}
+	}
+	return returns
+}
+
+// returnedFields returns the names of the fields set on the structs
+// allocated by the function and returned at result position pos, in
+// declaration order.
+func returnedFields(returns []*ssa.Return, pos int) []string {
+	set := make(map[int]bool)
+	var st *types.Struct
+	visited := make(map[ssa.Value]bool)
+
+	var collect func(v ssa.Value)
+	collect = func(v ssa.Value) {
+		if visited[v] {
+			return
+		}
+		visited[v] = true
+		switch v := v.(type) {
+		case *ssa.Phi:
+			for _, edge := range v.Edges {
+				collect(edge)
+			}
+		case *ssa.UnOp:
+			// A struct returned by value is loaded from its
+			// allocation.
+			if v.Op == token.MUL {
+				collect(v.X)
+			}
+		case *ssa.Alloc:
+			s, ok := v.Type().(*types.Pointer).Elem().Underlying().(*types.Struct)
+			if !ok {
+				return
+			}
+			st = s
+			for _, ref := range *v.Referrers() {
+				if fa, ok := ref.(*ssa.FieldAddr); ok && fa.X == v && storedThrough(fa) {
+					set[fa.Field] = true
+				}
+			}
+		}
+	}
+	for _, ret := range returns {
+		if pos < len(ret.Results) {
+			collect(ret.Results[pos])
+		}
+	}
+	if st == nil || len(set) == 0 {
+		return nil
+	}
+
+	indices := make([]int, 0, len(set))
+	for i := range set {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	fields := make([]string, 0, len(indices))
+	for _, i := range indices {
+		if i < st.NumFields() {
+			fields = append(fields, st.Field(i).Name())
+		}
+	}
+	return fields
+}
+
+// storedThrough reports whether a value is stored at addr, directly
+// or through a nested field or element address derived from it.
+func storedThrough(addr ssa.Value) bool {
+	refs := addr.Referrers()
+	if refs == nil {
+		return false
+	}
+	for _, ref := range *refs {
+		switch ref := ref.(type) {
+		case *ssa.Store:
+			if ref.Addr == addr {
+				return true
+			}
+		case *ssa.FieldAddr:
+			if storedThrough(ref) {
+				return true
+			}
+		case *ssa.IndexAddr:
+			if storedThrough(ref) {
+				return true
+			}
+		}
+	}
+	return false
+}
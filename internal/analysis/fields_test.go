@@ -0,0 +1,44 @@
+package analysis_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeReturnedFields verifies that struct return positions
+// list the fields the function sets on the value it allocates.
+func TestAnalyzeReturnedFields(t *testing.T) {
+	pkg := loadTestPackage(t, "fields")
+
+	tests := []struct {
+		function string
+		fields   []string
+	}{
+		{"NewConfig", []string{"Name", "Port"}},
+		{"Parse", []string{"Name", "Tags", "Limits"}},
+		{"Default", []string{"Name", "Debug"}},
+		{"Copy", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in fields package", tt.function)
+			}
+			result := analysis.AnalyzeFunctionWithSSA(pkg, fd, nil)
+
+			var fields []string
+			for _, e := range result.SideEffects {
+				if e.Type == taxonomy.ReturnValue {
+					fields = append(fields, e.Fields...)
+				}
+			}
+			if !slices.Equal(fields, tt.fields) {
+				t.Errorf("fields = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// grpcCodeNames are the names of the canonical gRPC status codes,
+// indexed by value, as declared in google.golang.org/grpc/codes.
+var grpcCodeNames = []string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// AnalyzeGRPCStatus detects the gRPC status codes a function can
+// return. Each returned status.Error(codes.X, ...), status.Errorf,
+// or status.New(codes.X, ...).Err() produces a GRPCStatusCode effect
+// targeting the code's name, so a service method that can fail with
+// NotFound and PermissionDenied has one effect for each instead of a
+// single ErrorReturn. Codes are constant-folded; a code that is not a
+// constant is reported under the expression that computes it.
+//
+// As with AnalyzeErrorWrapping, only statuses whose error is
+// returned, directly or through a local variable, are considered.
+func AnalyzeGRPCStatus(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil || !returnsError(info, fd) {
+		return nil
+	}
+
+	assigns := collectErrorAssignments(fd.Body, info)
+
+	var effects []taxonomy.SideEffect
+	seen := make(map[string]bool)
+
+	// visit follows an error or *status.Status expression back to the
+	// status constructor that built it.
+	var visit func(expr ast.Expr, depth int)
+	visit = func(expr ast.Expr, depth int) {
+		if depth > 8 {
+			return
+		}
+		expr = ast.Unparen(expr)
+		if ident, ok := expr.(*ast.Ident); ok {
+			for _, rhs := range assigns[info.Uses[ident]] {
+				visit(rhs, depth+1)
+			}
+			return
+		}
+		call, ok := expr.(*ast.CallExpr)
+		if !ok {
+			return
+		}
+		fn := calledFunc(call, info)
+		if fn == nil || fn.Pkg() == nil || !IsGRPCPackage(fn.Pkg(), "status") {
+			return
+		}
+		switch fn.Name() {
+		case "Error", "Errorf", "New", "Newf":
+			if len(call.Args) == 0 {
+				return
+			}
+		default:
+			// st.Err(), st.WithDetails(...): follow the receiver.
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+				visit(sel.X, depth+1)
+			}
+			return
+		}
+
+		code := grpcCodeName(call.Args[0], info)
+		if seen[code] {
+			return
+		}
+		seen[code] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.GRPCStatusCode), "grpc:"+code),
+			Type:        taxonomy.GRPCStatusCode,
+			Tier:        taxonomy.TierOf(taxonomy.GRPCStatusCode),
+			Location:    fset.Position(call.Pos()).String(),
+			Description: fmt.Sprintf("returns gRPC status %s via status.%s", code, fn.Name()),
+			Target:      code,
+		})
+	}
+	for _, expr := range returnedErrorExprs(fd.Body, info) {
+		visit(expr, 0)
+	}
+
+	return effects
+}
+
+// grpcCodeName returns the name of the gRPC code expr evaluates to
+// (NotFound for codes.NotFound or codes.Code(5)), or the expression
+// itself when it is not a constant.
+func grpcCodeName(expr ast.Expr, info *types.Info) string {
+	if tv, ok := info.Types[expr]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+		if v, ok := constant.Uint64Val(tv.Value); ok && v < uint64(len(grpcCodeNames)) {
+			return grpcCodeNames[v]
+		}
+	}
+	return types.ExprString(expr)
+}
+
+// IsGRPCPackage reports whether pkg is the named grpc subpackage
+// (status, codes). Forks that keep the grpc/<name> layout match too.
+func IsGRPCPackage(pkg *types.Package, name string) bool {
+	path := pkg.Path()
+	return path == "google.golang.org/grpc/"+name || strings.HasSuffix(path, "/grpc/"+name)
+}
+
+// IsGRPCCode reports whether name is one of the canonical gRPC status
+// codes. A GRPCStatusCode target outside this set is a code the
+// analyzer could not fold to a constant.
+func IsGRPCCode(name string) bool {
+	return slices.Contains(grpcCodeNames, name)
+}
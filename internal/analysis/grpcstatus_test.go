@@ -0,0 +1,51 @@
+package analysis_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeGRPCStatus verifies that each gRPC status code a
+// function returns, directly or through a local, produces its own
+// GRPCStatusCode effect named after the code.
+func TestAnalyzeGRPCStatus(t *testing.T) {
+	pkg := loadTestPackage(t, "grpcstatus")
+
+	tests := []struct {
+		function string
+		recv     string
+		codes    []string
+	}{
+		{"GetUser", "*Server", []string{"InvalidArgument", "NotFound", "PermissionDenied"}},
+		{"DeleteUser", "*Server", []string{"NotFound"}},
+		{"Translate", "", []string{"c"}},
+		{"Plain", "", nil},
+		{"Unreturned", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if tt.recv != "" {
+				fd = analysis.FindMethodDecl(pkg, tt.recv, tt.function)
+			}
+			if fd == nil {
+				t.Fatalf("%s not found in grpcstatus package", tt.function)
+			}
+			effects := analysis.AnalyzeGRPCStatus(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function)
+
+			var codes []string
+			for _, e := range effects {
+				if e.Type != taxonomy.GRPCStatusCode || e.Tier != taxonomy.TierP1 {
+					t.Errorf("got {%s %s}, want {GRPCStatusCode P1}", e.Type, e.Tier)
+				}
+				codes = append(codes, e.Target)
+			}
+			if !slices.Equal(codes, tt.codes) {
+				t.Errorf("codes = %v, want %v", codes, tt.codes)
+			}
+		})
+	}
+}
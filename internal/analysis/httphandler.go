@@ -0,0 +1,193 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"net/http"
+	"strconv"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// AnalyzeHTTPHandler enumerates the response a handler can produce:
+// one HTTPStatusCode effect per distinct status code and one
+// HTTPHeader effect per header key it sets. Status codes come from
+//   - w.WriteHeader(code)
+//   - http.Error(w, msg, code) and http.Redirect(w, r, url, code)
+//   - http.NotFound(w, r), which responds with 404
+//   - a same-package helper that receives w and a constant status,
+//     such as writeJSON(w, http.StatusCreated, v)
+//
+// Codes are constant-folded, so http.StatusNotFound and 404 are the
+// same effect. A handler that writes a body but never calls
+// WriteHeader responds with an implicit 200. Header keys come from
+// w.Header().Set and .Add, canonicalized as net/http does, and from
+// the Location header of http.Redirect.
+//
+// HTTPResponseWrite records which ResponseWriter methods a function
+// calls; these effects record which responses it can produce, so that
+// each branch of a handler can be checked for a test.
+func AnalyzeHTTPHandler(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+	seen := make(map[string]bool)
+	writesHeader := false
+	var bodyWrite *ast.CallExpr
+
+	add := func(typ taxonomy.SideEffectType, key string, pos token.Pos, desc, target string) {
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(typ), key),
+			Type:        typ,
+			Tier:        taxonomy.TierOf(typ),
+			Location:    fset.Position(pos).String(),
+			Description: desc,
+			Target:      target,
+		})
+	}
+	addCode := func(pos token.Pos, n int, via string) {
+		add(taxonomy.HTTPStatusCode, "status:"+strconv.Itoa(n), pos,
+			fmt.Sprintf("responds with status %d %s via %s", n, http.StatusText(n), via), strconv.Itoa(n))
+	}
+	addStatus := func(pos token.Pos, code ast.Expr, via string) {
+		if n, ok := constStatusCode(info, code); ok {
+			addCode(pos, n, via)
+			return
+		}
+		name := exprName(code)
+		add(taxonomy.HTTPStatusCode, "status:"+name, pos,
+			fmt.Sprintf("responds with a non-constant status code '%s' via %s", name, via), name)
+	}
+	addHeader := func(pos token.Pos, name, via string) {
+		add(taxonomy.HTTPHeader, "header:"+name, pos,
+			fmt.Sprintf("sets response header '%s' via %s", name, via), name)
+	}
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		// Methods on the ResponseWriter, and on its Header().
+		if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+			if isHTTPResponseWriter(info, sel.X) {
+				switch sel.Sel.Name {
+				case "WriteHeader":
+					writesHeader = true
+					if len(call.Args) == 1 {
+						addStatus(call.Pos(), call.Args[0], exprName(sel.X)+".WriteHeader")
+					}
+				case "Write":
+					if bodyWrite == nil {
+						bodyWrite = call
+					}
+				}
+				return true
+			}
+			if hdr, ok := sel.X.(*ast.CallExpr); ok && (sel.Sel.Name == "Set" || sel.Sel.Name == "Add") && len(call.Args) == 2 {
+				if hsel, ok := hdr.Fun.(*ast.SelectorExpr); ok && hsel.Sel.Name == "Header" && isHTTPResponseWriter(info, hsel.X) {
+					if name, ok := constString(info, call.Args[0]); ok {
+						addHeader(call.Pos(), http.CanonicalHeaderKey(name), exprName(hsel.X)+".Header()."+sel.Sel.Name)
+					}
+				}
+				return true
+			}
+		}
+
+		// Calls that are handed the ResponseWriter.
+		wIdx := -1
+		for i, arg := range call.Args {
+			if isHTTPResponseWriter(info, arg) {
+				wIdx = i
+				break
+			}
+		}
+		if wIdx < 0 {
+			return true
+		}
+		fn := calledFunc(call, info)
+		if fn == nil || fn.Pkg() == nil {
+			return true
+		}
+		switch {
+		case fn.Pkg().Path() == "net/http":
+			switch fn.Name() {
+			case "Error":
+				if len(call.Args) == 3 {
+					addStatus(call.Pos(), call.Args[2], "http.Error")
+				}
+			case "Redirect":
+				if len(call.Args) == 4 {
+					addStatus(call.Pos(), call.Args[3], "http.Redirect")
+					addHeader(call.Pos(), "Location", "http.Redirect")
+				}
+			case "NotFound":
+				addCode(call.Pos(), http.StatusNotFound, "http.NotFound")
+			}
+		case fn.Pkg().Path() == pkg:
+			// A response helper in this package: any constant status
+			// it is given is one this handler can respond with.
+			for i, arg := range call.Args {
+				if i == wIdx {
+					continue
+				}
+				if _, ok := constStatusCode(info, arg); ok {
+					addStatus(call.Pos(), arg, fn.Name())
+				}
+			}
+		default:
+			// fmt.Fprintf(w, ...), json.NewEncoder(w), io.Copy(w, ...)
+			// and the like write the body.
+			if bodyWrite == nil {
+				bodyWrite = call
+			}
+		}
+		return true
+	})
+
+	if bodyWrite != nil && !writesHeader {
+		addCode(bodyWrite.Pos(), http.StatusOK, "an implicit WriteHeader")
+	}
+
+	return effects
+}
+
+// constStatusCode returns the value of expr if it is an integer
+// constant in the HTTP status code range.
+func constStatusCode(info *types.Info, expr ast.Expr) (int, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+	n, ok := constant.Int64Val(tv.Value)
+	if !ok || n < 100 || n > 599 {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// constString returns the value of expr if it is a string constant.
+func constString(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
@@ -0,0 +1,61 @@
+package analysis_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeHTTPHandler verifies that handlers produce one
+// HTTPStatusCode effect per distinct constant-folded status code and
+// one HTTPHeader effect per canonical header key they set.
+func TestAnalyzeHTTPHandler(t *testing.T) {
+	pkg := loadTestPackage(t, "httphandler")
+
+	tests := []struct {
+		function string
+		recv     string
+		statuses []string
+		headers  []string
+	}{
+		{"GetItem", "", []string{"400", "404", "200"}, []string{"Content-Type"}},
+		{"ServeHTTP", "*Server", []string{"405", "500", "201"}, []string{"Allow", "Location"}},
+		{"Legacy", "", []string{"301"}, []string{"Location"}},
+		{"writeJSON", "", []string{"code"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if tt.recv != "" {
+				fd = analysis.FindMethodDecl(pkg, tt.recv, tt.function)
+			}
+			if fd == nil {
+				t.Fatalf("%s not found in httphandler package", tt.function)
+			}
+			effects := analysis.AnalyzeHTTPHandler(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function)
+
+			var statuses, headers []string
+			for _, e := range effects {
+				if e.Tier != taxonomy.TierP1 {
+					t.Errorf("%s effect has tier %s, want P1", e.Type, e.Tier)
+				}
+				switch e.Type {
+				case taxonomy.HTTPStatusCode:
+					statuses = append(statuses, e.Target)
+				case taxonomy.HTTPHeader:
+					headers = append(headers, e.Target)
+				default:
+					t.Errorf("unexpected effect type %s", e.Type)
+				}
+			}
+			if !slices.Equal(statuses, tt.statuses) {
+				t.Errorf("status codes = %v, want %v", statuses, tt.statuses)
+			}
+			if !slices.Equal(headers, tt.headers) {
+				t.Errorf("headers = %v, want %v", headers, tt.headers)
+			}
+		})
+	}
+}
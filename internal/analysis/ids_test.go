@@ -0,0 +1,120 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// effectIDs returns the IDs of the effects in results, keyed by
+// function, type and target.
+func effectIDs(results []taxonomy.AnalysisResult) map[string]string {
+	ids := make(map[string]string)
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			ids[r.Target.QualifiedName()+" "+string(e.Type)+" "+e.Target] = e.ID
+		}
+	}
+	return ids
+}
+
+func TestEffectIDs_DistinctReceivers(t *testing.T) {
+	pkg := loadTestPackage(t, "ids")
+	results, err := analysis.Analyze(pkg, analysis.Options{})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	ids := effectIDs(results)
+
+	a, b := ids["(*A).Reset ReceiverMutation count"], ids["(*B).Reset ReceiverMutation count"]
+	if a == "" || b == "" {
+		t.Fatalf("missing Reset mutations: %v", ids)
+	}
+	if a == b {
+		t.Errorf("(*A).Reset and (*B).Reset share ID %s", a)
+	}
+}
+
+func TestEffectIDs_Unique(t *testing.T) {
+	pkg := loadTestPackage(t, "ids")
+	results, err := analysis.Analyze(pkg, analysis.Options{IncludeUnexported: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	warnings := 0
+	for _, r := range results {
+		warnings += len(r.Metadata.Warnings)
+		for _, e := range r.SideEffects {
+			if seen[e.ID] {
+				t.Errorf("duplicate ID %s (%s %s)", e.ID, r.Target.QualifiedName(), e.Type)
+			}
+			seen[e.ID] = true
+		}
+	}
+	// The second init's mutation of registry hashes like the first's.
+	if warnings != 1 {
+		t.Errorf("expected 1 collision warning, got %d", warnings)
+	}
+}
+
+func TestEffectIDs_SurviveCodeMotion(t *testing.T) {
+	src, err := os.ReadFile(filepath.Join(testdataPath("ids"), "ids.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Move most declarations, and the second write in Save, down.
+	moved := strings.Replace(string(src), "var registry",
+		"// Padding moves the code below.\n\n\nvar registry", 1)
+	moved = strings.Replace(moved, "\tif err := os.WriteFile(path+",
+		"\n\n\tif err := os.WriteFile(path+", 1)
+
+	before := effectIDs(analyzeSource(t, string(src)))
+	after := effectIDs(analyzeSource(t, moved))
+
+	if len(before) == 0 {
+		t.Fatal("no effects detected")
+	}
+	for key, id := range before {
+		if after[key] != id {
+			t.Errorf("%s: ID changed from %s to %s after code motion", key, id, after[key])
+		}
+	}
+}
+
+// analyzeSource analyzes src as the only file of a module in a
+// temporary directory.
+func analyzeSource(t *testing.T, src string) []taxonomy.AnalysisResult {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/ids\n\ngo 1.24\n",
+		"ids.go": src,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports |
+			packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax |
+			packages.NeedTypesInfo | packages.NeedTypesSizes,
+		Dir: dir,
+	}, ".")
+	if err != nil || len(pkgs) != 1 || len(pkgs[0].Errors) > 0 {
+		t.Fatalf("loading %s: %v %v", dir, err, pkgs)
+	}
+	results, err := analysis.Analyze(pkgs[0], analysis.Options{IncludeUnexported: true})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	return results
+}
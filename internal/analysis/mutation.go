@@ -6,6 +6,8 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"slices"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
@@ -158,10 +160,11 @@ func detectMutations(
 	receiverParam := receiverSSAParam(ssaFn, isMethod)
 	ptrParams := pointerParams(ssaFn, isMethod)
 
-	// Track which fields/params have already been reported to avoid
-	// duplicate side effects for the same field mutated multiple times.
-	seenReceiverFields := make(map[string]bool)
-	seenPtrArgs := make(map[string]bool)
+	// Track which fields/params have already been reported, by index
+	// into effects, so a field mutated several times is one effect
+	// with an occurrence per store.
+	seenReceiverFields := make(map[string]int)
+	seenPtrArgs := make(map[string]int)
 
 	var effects []taxonomy.SideEffect
 
@@ -177,8 +180,10 @@ func detectMutations(
 			// Check for receiver field mutation.
 			if isMethod && receiverParam != nil {
 				if fieldName, ok := isReceiverFieldStore(store, receiverParam); ok {
-					if !seenReceiverFields[fieldName] {
-						seenReceiverFields[fieldName] = true
+					idx, seen := seenReceiverFields[fieldName]
+					if !seen {
+						idx = len(effects)
+						seenReceiverFields[fieldName] = idx
 						effects = append(effects, taxonomy.SideEffect{
 							ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.ReceiverMutation), fieldName),
 							Type:        taxonomy.ReceiverMutation,
@@ -188,13 +193,22 @@ func detectMutations(
 							Target:      fieldName,
 						})
 					}
+					addOccurrence(&effects[idx], loc)
+					// Record nested writes (c.Nested.Value = v) by path
+					// so field coverage can tell Nested's fields apart.
+					if path := receiverFieldPath(store); strings.Contains(path, ".") &&
+						!slices.Contains(effects[idx].Fields, path) {
+						effects[idx].Fields = append(effects[idx].Fields, path)
+					}
 				}
 			}
 
 			// Check for pointer argument mutation.
 			if paramName, ok := isPointerArgStore(store, ptrParams); ok {
-				if !seenPtrArgs[paramName] {
-					seenPtrArgs[paramName] = true
+				idx, seen := seenPtrArgs[paramName]
+				if !seen {
+					idx = len(effects)
+					seenPtrArgs[paramName] = idx
 					effects = append(effects, taxonomy.SideEffect{
 						ID:          taxonomy.GenerateID(pkgPath, funcName, string(taxonomy.PointerArgMutation), paramName),
 						Type:        taxonomy.PointerArgMutation,
@@ -204,6 +218,7 @@ func detectMutations(
 						Target:      paramName,
 					})
 				}
+				addOccurrence(&effects[idx], loc)
 			}
 		}
 	}
@@ -271,6 +286,24 @@ func isReceiverFieldStore(store *ssa.Store, receiver *ssa.Parameter) (string, bo
 	return "", false
 }
 
+// receiverFieldPath returns the dotted field path a receiver field
+// store writes, from the receiver down (e.g., "Nested.Value").
+func receiverFieldPath(store *ssa.Store) string {
+	fa, ok := store.Addr.(*ssa.FieldAddr)
+	if !ok {
+		return ""
+	}
+	var path []string
+	for {
+		path = append([]string{fieldNameFromFieldAddr(fa)}, path...)
+		inner, ok := fa.X.(*ssa.FieldAddr)
+		if !ok {
+			return strings.Join(path, ".")
+		}
+		fa = inner
+	}
+}
+
 // isPointerArgStore checks if a Store instruction writes through a
 // pointer parameter. Returns the parameter name if true.
 func isPointerArgStore(store *ssa.Store, ptrParams map[string]*ssa.Parameter) (string, bool) {
@@ -0,0 +1,227 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strings"
+	"sync"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// networkRules are the built-in rules for outbound network I/O,
+// messaging, and metrics. They use the same matching as .gaze.yaml
+// detection rules, so interface methods (prometheus.Counter.Inc) and
+// wildcards (expvar.*.Add) are supported.
+var networkRules = []config.EffectRule{
+	// NetworkDial: opening connections and listeners.
+	{Function: "net.Dial*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "net.Listen*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "net.(*Dialer).Dial*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "net.(*ListenConfig).Listen*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "crypto/tls.Dial*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "crypto/tls.Listen", Effect: string(taxonomy.NetworkDial)},
+	{Function: "crypto/tls.(*Dialer).Dial*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "google.golang.org/grpc.Dial*", Effect: string(taxonomy.NetworkDial)},
+	{Function: "google.golang.org/grpc.NewClient", Effect: string(taxonomy.NetworkDial)},
+
+	// HTTPRequest: sending requests through an http.Client.
+	{Function: "net/http.(*Client).Do", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.(*Client).Get", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.(*Client).Head", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.(*Client).Post*", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.Get", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.Head", Effect: string(taxonomy.HTTPRequest)},
+	{Function: "net/http.Post*", Effect: string(taxonomy.HTTPRequest)},
+
+	// RPCCall: gRPC invocations on a connection. Calls through
+	// generated client stubs are recognized by their signature.
+	{Function: "google.golang.org/grpc.(*ClientConn).Invoke", Effect: string(taxonomy.RPCCall)},
+	{Function: "google.golang.org/grpc.(*ClientConn).NewStream", Effect: string(taxonomy.RPCCall)},
+	{Function: "google.golang.org/grpc.Invoke", Effect: string(taxonomy.RPCCall)},
+
+	// MessagePublish: message brokers.
+	{Function: "github.com/IBM/sarama.SyncProducer.SendMessage*", Effect: string(taxonomy.MessagePublish)},
+	{Function: "github.com/Shopify/sarama.SyncProducer.SendMessage*", Effect: string(taxonomy.MessagePublish)},
+	{Function: "github.com/segmentio/kafka-go.(*Writer).WriteMessages", Effect: string(taxonomy.MessagePublish)},
+	{Function: "github.com/nats-io/nats.go.(*Conn).Publish*", Effect: string(taxonomy.MessagePublish)},
+	{Function: "github.com/rabbitmq/amqp091-go.(*Channel).Publish*", Effect: string(taxonomy.MessagePublish)},
+	{Function: "github.com/streadway/amqp.(*Channel).Publish", Effect: string(taxonomy.MessagePublish)},
+	{Function: "cloud.google.com/go/pubsub.(*Topic).Publish", Effect: string(taxonomy.MessagePublish)},
+
+	// MetricEmit: Prometheus, OpenTelemetry, and expvar.
+	{Function: "github.com/prometheus/client_golang/prometheus.Counter.*", Effect: string(taxonomy.MetricEmit)},
+	{Function: "github.com/prometheus/client_golang/prometheus.Gauge.*", Effect: string(taxonomy.MetricEmit)},
+	{Function: "github.com/prometheus/client_golang/prometheus.Observer.Observe", Effect: string(taxonomy.MetricEmit)},
+	{Function: "github.com/prometheus/client_golang/prometheus.Histogram.Observe", Effect: string(taxonomy.MetricEmit)},
+	{Function: "github.com/prometheus/client_golang/prometheus.Summary.Observe", Effect: string(taxonomy.MetricEmit)},
+	{Function: "go.opentelemetry.io/otel/metric.*Counter.Add", Effect: string(taxonomy.MetricEmit)},
+	{Function: "go.opentelemetry.io/otel/metric.*Histogram.Record", Effect: string(taxonomy.MetricEmit)},
+	{Function: "go.opentelemetry.io/otel/metric.*Gauge.Record", Effect: string(taxonomy.MetricEmit)},
+	{Function: "expvar.*.Add*", Effect: string(taxonomy.MetricEmit)},
+	{Function: "expvar.*.Set", Effect: string(taxonomy.MetricEmit)},
+}
+
+var (
+	networkRuleSetOnce sync.Once
+	networkRuleSet     *ruleSet
+)
+
+// networkDescriptions are the description templates for the effects
+// of networkRules; the placeholder is the called function.
+var networkDescriptions = map[taxonomy.SideEffectType]string{
+	taxonomy.NetworkDial:    "opens a network connection or listener via %s",
+	taxonomy.HTTPRequest:    "sends an HTTP request via %s",
+	taxonomy.RPCCall:        "invokes an RPC via %s",
+	taxonomy.MessagePublish: "publishes a message via %s",
+	taxonomy.MetricEmit:     "emits a metric via %s",
+}
+
+// AnalyzeNetworkEffects detects outbound network I/O in a function
+// body: NetworkDial (net.Dial, net.Listen, grpc.NewClient),
+// HTTPRequest (http.Client.Do/Get/Post and the package-level helpers),
+// RPCCall (gRPC client stubs and ClientConn.Invoke), MessagePublish
+// (Kafka, NATS, AMQP, Pub/Sub producers), and MetricEmit (Prometheus,
+// OpenTelemetry, and expvar instruments). For http.Client.Do, the
+// HTTP method is taken from the http.NewRequest call that built the
+// request when it is a constant.
+func AnalyzeNetworkEffects(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil {
+		return nil
+	}
+	networkRuleSetOnce.Do(func() { networkRuleSet = newRuleSet(networkRules) })
+
+	requestMethods := collectRequestMethods(fd, info)
+	var effects []taxonomy.SideEffect
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(call, info)
+		if fn == nil {
+			return true
+		}
+
+		var effectType taxonomy.SideEffectType
+		if rule, ok := networkRuleSet.match(canonicalFuncName(fn.Origin())); ok {
+			effectType = taxonomy.SideEffectType(rule.Effect)
+		} else if isGRPCStubMethod(fn) {
+			effectType = taxonomy.RPCCall
+		} else {
+			return true
+		}
+
+		target := ruleTarget(fn)
+		key := fmt.Sprintf("%s:%s", effectType, target)
+		desc := fmt.Sprintf(networkDescriptions[effectType], target)
+		if effectType == taxonomy.HTTPRequest {
+			if method := httpMethod(fn, call, info, requestMethods); method != "" {
+				key += ":" + method
+				desc = fmt.Sprintf("sends an HTTP %s request via %s", method, target)
+			}
+		}
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(effectType), key),
+			Type:        effectType,
+			Tier:        taxonomy.TierOf(effectType),
+			Location:    fset.Position(call.Pos()).String(),
+			Description: desc,
+			Target:      target,
+		})
+		return true
+	})
+
+	return mergeOccurrences(effects)
+}
+
+// isGRPCStubMethod reports whether fn is a method of a generated gRPC
+// client stub: its last parameter is ...grpc.CallOption.
+func isGRPCStubMethod(fn *types.Func) bool {
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil || !sig.Variadic() {
+		return false
+	}
+	last := sig.Params().At(sig.Params().Len() - 1).Type()
+	slice, ok := last.(*types.Slice)
+	if !ok {
+		return false
+	}
+	named, ok := slice.Elem().(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == "google.golang.org/grpc" && named.Obj().Name() == "CallOption"
+}
+
+// collectRequestMethods maps the variables assigned the result of
+// http.NewRequest or http.NewRequestWithContext to the request's HTTP
+// method, when the method argument is a constant.
+func collectRequestMethods(fd *ast.FuncDecl, info *types.Info) map[types.Object]string {
+	methods := make(map[types.Object]string)
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || len(assign.Lhs) == 0 {
+			return true
+		}
+		call, ok := assign.Rhs[0].(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(call, info)
+		if fn == nil || fn.Pkg() == nil || fn.Pkg().Path() != "net/http" {
+			return true
+		}
+		arg := -1
+		switch fn.Name() {
+		case "NewRequest":
+			arg = 0
+		case "NewRequestWithContext":
+			arg = 1
+		}
+		if arg < 0 || len(call.Args) <= arg {
+			return true
+		}
+		tv, ok := info.Types[call.Args[arg]]
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+			if obj := info.ObjectOf(ident); obj != nil {
+				methods[obj] = constant.StringVal(tv.Value)
+			}
+		}
+		return true
+	})
+	return methods
+}
+
+// httpMethod returns the HTTP method of a request-sending call: the
+// method named by the helper (Get, Head, Post, PostForm), or for
+// Client.Do the constant method its request was built with.
+func httpMethod(fn *types.Func, call *ast.CallExpr, info *types.Info, requestMethods map[types.Object]string) string {
+	switch fn.Name() {
+	case "Get", "Head", "Post":
+		return strings.ToUpper(fn.Name())
+	case "PostForm":
+		return "POST"
+	case "Do":
+		if len(call.Args) == 1 {
+			if ident, ok := call.Args[0].(*ast.Ident); ok {
+				return requestMethods[info.Uses[ident]]
+			}
+		}
+	}
+	return ""
+}
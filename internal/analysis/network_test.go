@@ -0,0 +1,103 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeNetworkEffects verifies that dials, listens, HTTP
+// requests, and metric updates are detected with their targets, and
+// that building a request without sending it is not an effect.
+func TestAnalyzeNetworkEffects(t *testing.T) {
+	pkg := loadTestPackage(t, "network")
+
+	tests := []struct {
+		function string
+		want     []string
+	}{
+		{"Connect", []string{"NetworkDial:net.Dial"}},
+		{"Serve", []string{"NetworkDial:ListenConfig.Listen"}},
+		{"CreateUser", []string{"HTTPRequest:Client.Do"}},
+		{"Ping", []string{"HTTPRequest:http.Head"}},
+		{"Count", []string{"MetricEmit:Int.Add"}},
+		{"BuildRequest", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in network package", tt.function)
+			}
+			var got []string
+			for _, e := range analysis.AnalyzeNetworkEffects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function) {
+				got = append(got, string(e.Type)+":"+e.Target)
+				if e.Tier != taxonomy.TierP2 {
+					t.Errorf("%s tier: got %s, want P2", e.Type, e.Tier)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAnalyzeNetworkEffects_HTTPMethod verifies that the HTTP method
+// of a Client.Do request is taken from the http.NewRequest call that
+// built it.
+func TestAnalyzeNetworkEffects_HTTPMethod(t *testing.T) {
+	pkg := loadTestPackage(t, "network")
+	for function, want := range map[string]string{
+		"CreateUser": "HTTP POST request",
+		"Ping":       "HTTP HEAD request",
+	} {
+		fd := analysis.FindFuncDecl(pkg, function)
+		if fd == nil {
+			t.Fatalf("%s not found in network package", function)
+		}
+		effects := analysis.AnalyzeNetworkEffects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, function)
+		if len(effects) != 1 || !strings.Contains(effects[0].Description, want) {
+			t.Errorf("%s: got %v, want one effect described as %q", function, effects, want)
+		}
+	}
+}
+
+// TestAnalyze_SpecificEffectSupersedesInteraction verifies that a
+// call on an injected interface matched by a detection rule is
+// reported as the rule's effect only, not also as an
+// InterfaceInteraction.
+func TestAnalyze_SpecificEffectSupersedesInteraction(t *testing.T) {
+	pkg := loadTestPackage(t, "network")
+
+	analyze := func(rules []config.EffectRule) []taxonomy.SideEffect {
+		t.Helper()
+		results, err := analysis.Analyze(pkg, analysis.Options{
+			FunctionFilter: "Dispatch",
+			Rules:          rules,
+		})
+		if err != nil {
+			t.Fatalf("Analyze: %v", err)
+		}
+		if len(results) != 1 {
+			t.Fatalf("got %d results, want 1", len(results))
+		}
+		return results[0].SideEffects
+	}
+
+	if effects := analyze(nil); !hasEffect(effects, taxonomy.InterfaceInteraction) {
+		t.Fatalf("expected InterfaceInteraction without rules, got %v", effects)
+	}
+	effects := analyze([]config.EffectRule{
+		{Function: pkg.PkgPath + ".Queue.Enqueue", Effect: string(taxonomy.MessagePublish)},
+	})
+	if !hasEffect(effects, taxonomy.MessagePublish) {
+		t.Errorf("expected MessagePublish from rule, got %v", effects)
+	}
+	if hasEffect(effects, taxonomy.InterfaceInteraction) {
+		t.Errorf("expected InterfaceInteraction to be superseded, got %v", effects)
+	}
+}
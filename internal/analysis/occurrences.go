@@ -0,0 +1,329 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// mergeOccurrences merges effects that share an ID, such as two
+// writes to the same map, into the first of them. Each merged
+// effect lists every site in Occurrences; effects reported once
+// are left as they are.
+func mergeOccurrences(effects []taxonomy.SideEffect) []taxonomy.SideEffect {
+	index := make(map[string]int, len(effects))
+	merged := effects[:0]
+	for _, e := range effects {
+		if i, ok := index[e.ID]; ok {
+			addOccurrence(&merged[i], e.Location)
+			continue
+		}
+		index[e.ID] = len(merged)
+		merged = append(merged, e)
+	}
+	return merged
+}
+
+// addOccurrence records loc as a further site of e. The first
+// occurrence is e's own Location.
+func addOccurrence(e *taxonomy.SideEffect, loc string) {
+	if len(e.Occurrences) == 0 {
+		e.Occurrences = []taxonomy.Occurrence{{Location: e.Location}}
+	}
+	for _, o := range e.Occurrences {
+		if o.Location == loc {
+			return
+		}
+	}
+	e.Occurrences = append(e.Occurrences, taxonomy.Occurrence{Location: loc})
+}
+
+// sites returns the locations of e: its Location and any further
+// occurrences merged into it.
+func sites(e taxonomy.SideEffect) []string {
+	if len(e.Occurrences) == 0 {
+		return []string{e.Location}
+	}
+	locs := make([]string, len(e.Occurrences))
+	for i, o := range e.Occurrences {
+		locs[i] = o.Location
+	}
+	return locs
+}
+
+// AnalyzeOccurrences completes each effect's Occurrences and records
+// the conditions guarding every site. ReturnValue and ErrorReturn
+// effects occur at each return statement; an ErrorReturn only where
+// the error returned is not the nil literal. Other effects occur at
+// the sites their detectors reported.
+//
+// Conditions come from SSA dominators: a site is guarded by every
+// branch whose outcome all paths to it share. Code after an early
+// `if err != nil { return err }` is guarded by "err == nil", and the
+// return inside it by "err != nil". Branches whose condition has no
+// source expression, such as range loop bounds, are left out.
+func AnalyzeOccurrences(
+	fset *token.FileSet,
+	ssaPkg *ssa.Package,
+	fd *ast.FuncDecl,
+	fnObj *types.Func,
+	effects []taxonomy.SideEffect,
+) {
+	if fd.Body == nil {
+		return
+	}
+	returns := returnSites(fset, fd)
+
+	pos := 0
+	for i := range effects {
+		e := &effects[i]
+		switch e.Type {
+		case taxonomy.ReturnValue, taxonomy.ErrorReturn:
+			var occ []taxonomy.Occurrence
+			for _, r := range returns {
+				if e.Type == taxonomy.ErrorReturn && r.nilAt(pos) {
+					continue
+				}
+				occ = append(occ, taxonomy.Occurrence{Location: r.loc})
+			}
+			e.Occurrences = occ
+			pos++
+		default:
+			if len(e.Occurrences) == 0 {
+				e.Occurrences = []taxonomy.Occurrence{{Location: e.Location}}
+			}
+		}
+	}
+
+	if ssaPkg == nil {
+		return
+	}
+	fn := findSSAFunction(ssaPkg, fnObj, fd)
+	if fn == nil || fn.Blocks == nil {
+		return
+	}
+	g := newGuards(fset, fd, fn)
+	for i := range effects {
+		for j := range effects[i].Occurrences {
+			o := &effects[i].Occurrences[j]
+			o.Conditions = g.conditions(o.Location)
+		}
+	}
+}
+
+// returnSite is a return statement of the analyzed function.
+type returnSite struct {
+	loc     string
+	results []ast.Expr
+}
+
+// nilAt reports whether the return yields the nil literal at result
+// position pos. A bare return or a return of a call is not.
+func (r returnSite) nilAt(pos int) bool {
+	if pos >= len(r.results) {
+		return false
+	}
+	ident, ok := ast.Unparen(r.results[pos]).(*ast.Ident)
+	return ok && ident.Name == "nil"
+}
+
+// returnSites returns the return statements of fd, excluding those
+// of nested function literals.
+func returnSites(fset *token.FileSet, fd *ast.FuncDecl) []returnSite {
+	var sites []returnSite
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			sites = append(sites, returnSite{
+				loc:     fset.Position(n.Pos()).String(),
+				results: n.Results,
+			})
+		}
+		return true
+	})
+	return sites
+}
+
+// guards derives the branch conditions guarding source positions in
+// one function from its SSA dominator tree.
+type guards struct {
+	fset *token.FileSet
+	fn   *ssa.Function
+
+	// conds maps the position SSA gives a branch condition to its
+	// source text: the operator of a comparison or negation, the
+	// parenthesis of a call, or the start of a switch case.
+	conds map[token.Pos]string
+
+	// negated maps a condition's text to its negation where the
+	// negation reads better than "!(...)".
+	negated map[string]string
+}
+
+// newGuards indexes the condition expressions of fd's body.
+func newGuards(fset *token.FileSet, fd *ast.FuncDecl, fn *ssa.Function) *guards {
+	g := &guards{
+		fset:    fset,
+		fn:      fn,
+		conds:   make(map[token.Pos]string),
+		negated: make(map[string]string),
+	}
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.BinaryExpr:
+			text := types.ExprString(n)
+			g.conds[n.OpPos] = text
+			if op, ok := negatedOps[n.Op]; ok {
+				g.negated[text] = types.ExprString(&ast.BinaryExpr{X: n.X, Op: op, Y: n.Y})
+			}
+		case *ast.UnaryExpr:
+			if n.Op == token.NOT {
+				text := types.ExprString(n)
+				g.conds[n.OpPos] = text
+				g.negated[text] = types.ExprString(ast.Unparen(n.X))
+			}
+		case *ast.CallExpr:
+			g.conds[n.Lparen] = types.ExprString(n)
+		case *ast.SwitchStmt:
+			if n.Tag == nil {
+				return true
+			}
+			tag := types.ExprString(n.Tag)
+			for _, stmt := range n.Body.List {
+				for _, e := range stmt.(*ast.CaseClause).List {
+					text := tag + " == " + types.ExprString(e)
+					g.conds[e.Pos()] = text
+					g.negated[text] = tag + " != " + types.ExprString(e)
+				}
+			}
+		}
+		return true
+	})
+	return g
+}
+
+// negatedOps maps comparison operators to their negation.
+var negatedOps = map[token.Token]token.Token{
+	token.EQL: token.NEQ,
+	token.NEQ: token.EQL,
+	token.LSS: token.GEQ,
+	token.GEQ: token.LSS,
+	token.GTR: token.LEQ,
+	token.LEQ: token.GTR,
+}
+
+// conditions returns the conditions guarding the site at loc,
+// outermost first.
+func (g *guards) conditions(loc string) []string {
+	b := g.blockAt(loc)
+	if b == nil {
+		return nil
+	}
+	var conds []string
+	for d := b.Idom(); d != nil; d = d.Idom() {
+		ifInstr, ok := d.Instrs[len(d.Instrs)-1].(*ssa.If)
+		if !ok {
+			continue
+		}
+		text := g.condText(ifInstr.Cond)
+		if text == "" {
+			continue
+		}
+		// A successor guards b only if the branch is its sole
+		// entry: then every path to b took that branch.
+		switch {
+		case edgeDominates(d, d.Succs[0], b):
+			conds = append(conds, text)
+		case edgeDominates(d, d.Succs[1], b):
+			conds = append(conds, g.negate(text))
+		}
+	}
+	slices.Reverse(conds)
+	return slices.Compact(conds)
+}
+
+// condText returns the source text of a branch condition, or ""
+// when it has none. The builder branches on a boolean variable
+// itself (swapping the successors for "!ok"), and such a value
+// carries no position of its use, so it is named by its variable.
+func (g *guards) condText(cond ssa.Value) string {
+	if cond.Pos().IsValid() {
+		if text, ok := g.conds[cond.Pos()]; ok {
+			return text
+		}
+	}
+	switch v := cond.(type) {
+	case *ssa.Parameter:
+		return v.Name()
+	case *ssa.Phi:
+		return v.Comment
+	case *ssa.UnOp:
+		if v.Op != token.MUL {
+			return ""
+		}
+		switch x := v.X.(type) {
+		case *ssa.Alloc:
+			return x.Comment
+		case *ssa.Global:
+			return x.Name()
+		}
+	}
+	return ""
+}
+
+// edgeDominates reports whether every path to b passes through the
+// edge from d to succ.
+func edgeDominates(d, succ, b *ssa.BasicBlock) bool {
+	return len(succ.Preds) == 1 && succ.Preds[0] == d && succ.Dominates(b)
+}
+
+// negate returns the negation of a condition's text.
+func (g *guards) negate(text string) string {
+	if n, ok := g.negated[text]; ok {
+		return n
+	}
+	if expr, err := parser.ParseExpr(text); err == nil {
+		switch expr.(type) {
+		case *ast.Ident, *ast.SelectorExpr, *ast.CallExpr:
+			return "!" + text
+		}
+	}
+	return "!(" + text + ")"
+}
+
+// blockAt returns the basic block of the first instruction at or
+// after loc on the same line, or nil when none is found.
+func (g *guards) blockAt(loc string) *ssa.BasicBlock {
+	file, line, col, ok := taxonomy.ParseLocation(loc)
+	if !ok {
+		return nil
+	}
+	var best *ssa.BasicBlock
+	bestCol := 0
+	for _, b := range g.fn.Blocks {
+		for _, instr := range b.Instrs {
+			if !instr.Pos().IsValid() {
+				continue
+			}
+			p := g.fset.Position(instr.Pos())
+			if p.Filename != file || p.Line != line || p.Column < col {
+				continue
+			}
+			if best == nil || p.Column < bestCol {
+				best, bestCol = b, p.Column
+			}
+		}
+	}
+	return best
+}
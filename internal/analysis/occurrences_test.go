@@ -0,0 +1,87 @@
+package analysis_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// occurrenceConds returns the conditions of each occurrence of the
+// first effect of type typ, joined with " && " ("" when unguarded).
+func occurrenceConds(t *testing.T, fixture, function string, typ taxonomy.SideEffectType) []string {
+	t.Helper()
+	pkg, ssaPkg := loadTestPackageWithSSA(t, fixture)
+	fd := analysis.FindFuncDecl(pkg, function)
+	if fd == nil {
+		t.Fatalf("%s not found in %s package", function, fixture)
+	}
+	result := analysis.AnalyzeFunctionWithSSA(pkg, fd, ssaPkg)
+	for _, e := range result.SideEffects {
+		if e.Type != typ {
+			continue
+		}
+		var conds []string
+		for _, o := range e.Occurrences {
+			conds = append(conds, strings.Join(o.Conditions, " && "))
+		}
+		return conds
+	}
+	t.Fatalf("%s: no %s effect", function, typ)
+	return nil
+}
+
+func TestAnalyzeOccurrences(t *testing.T) {
+	tests := []struct {
+		name     string
+		function string
+		typ      taxonomy.SideEffectType
+		want     []string
+	}{
+		// Every return yields a value; the success return is
+		// guarded by both error checks failing.
+		{"return value at each return", "Parse", taxonomy.ReturnValue,
+			[]string{`s == ""`, `s != "" && err != nil`, `s != "" && err == nil`}},
+		// The success return yields a nil error and is not an
+		// occurrence of the error return.
+		{"error return skips nil returns", "Parse", taxonomy.ErrorReturn,
+			[]string{`s == ""`, `s != "" && err != nil`}},
+		// Repeated writes to one map are one effect with a site
+		// per write.
+		{"merged map writes", "Record", taxonomy.MapMutation,
+			[]string{"!ok", "ok"}},
+		{"default case", "Kind", taxonomy.MapMutation,
+			[]string{"code != 1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := occurrenceConds(t, "occurrences", tt.function, tt.typ)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("conditions = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeOccurrences_FirstSiteIsLocation(t *testing.T) {
+	pkg, ssaPkg := loadTestPackageWithSSA(t, "occurrences")
+	fd := analysis.FindFuncDecl(pkg, "Record")
+	result := analysis.AnalyzeFunctionWithSSA(pkg, fd, ssaPkg)
+
+	var mapEffects int
+	for _, e := range result.SideEffects {
+		if e.Type != taxonomy.MapMutation {
+			continue
+		}
+		mapEffects++
+		if len(e.Occurrences) == 0 || e.Occurrences[0].Location != e.Location {
+			t.Errorf("first occurrence should be the effect's Location %s, got %+v",
+				e.Location, e.Occurrences)
+		}
+	}
+	if mapEffects != 1 {
+		t.Errorf("expected writes to hits to merge into 1 MapMutation, got %d", mapEffects)
+	}
+}
@@ -23,8 +23,9 @@ import (
 //
 // Internally, the function dispatches to per-node-type handlers:
 // detectAssignEffects, detectIncDecEffects, detectSendEffects, and
-// detectP1CallEffects. The shared seen map preserves deduplication
-// across all handlers.
+// detectP1CallEffects. The handlers report every site; repeated
+// effects on the same target are merged into one effect with an
+// occurrence per site.
 func AnalyzeP1Effects(
 	fset *token.FileSet,
 	info *types.Info,
@@ -37,7 +38,6 @@ func AnalyzeP1Effects(
 	}
 
 	var effects []taxonomy.SideEffect
-	seen := make(map[string]bool)
 
 	// Build set of parameter and local names to distinguish globals.
 	locals := collectLocals(fd)
@@ -46,21 +46,21 @@ func AnalyzeP1Effects(
 		switch node := n.(type) {
 		case *ast.AssignStmt:
 			effects = append(effects,
-				detectAssignEffects(fset, info, node, pkg, funcName, seen, locals)...)
+				detectAssignEffects(fset, info, node, pkg, funcName, locals)...)
 		case *ast.IncDecStmt:
 			effects = append(effects,
-				detectIncDecEffects(fset, info, node, pkg, funcName, seen, locals)...)
+				detectIncDecEffects(fset, info, node, pkg, funcName, locals)...)
 		case *ast.SendStmt:
 			effects = append(effects,
-				detectSendEffects(fset, node, pkg, funcName, seen)...)
+				detectSendEffects(fset, node, pkg, funcName)...)
 		case *ast.CallExpr:
 			effects = append(effects,
-				detectP1CallEffects(fset, info, node, pkg, funcName, seen)...)
+				detectP1CallEffects(fset, info, node, pkg, funcName)...)
 		}
 		return true
 	})
 
-	return effects
+	return mergeOccurrences(effects)
 }
 
 // detectAssignEffects handles *ast.AssignStmt nodes, detecting
@@ -72,7 +72,6 @@ func detectAssignEffects(
 	node *ast.AssignStmt,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 	locals map[string]bool,
 ) []taxonomy.SideEffect {
 	var effects []taxonomy.SideEffect
@@ -81,53 +80,41 @@ func detectAssignEffects(
 		// Global mutation: assignment to a package-level var.
 		if ident, ok := lhs.(*ast.Ident); ok {
 			if isGlobalIdent(ident, info, locals) {
-				key := "global:" + ident.Name
-				if !seen[key] {
-					seen[key] = true
-					loc := fset.Position(ident.Pos()).String()
-					effects = append(effects, taxonomy.SideEffect{
-						ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.GlobalMutation), ident.Name),
-						Type:        taxonomy.GlobalMutation,
-						Tier:        taxonomy.TierP1,
-						Location:    loc,
-						Description: fmt.Sprintf("assigns to package-level variable '%s'", ident.Name),
-						Target:      ident.Name,
-					})
-				}
+				loc := fset.Position(ident.Pos()).String()
+				effects = append(effects, taxonomy.SideEffect{
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.GlobalMutation), ident.Name),
+					Type:        taxonomy.GlobalMutation,
+					Tier:        taxonomy.TierP1,
+					Location:    loc,
+					Description: fmt.Sprintf("assigns to package-level variable '%s'", ident.Name),
+					Target:      ident.Name,
+				})
 			}
 		}
 		// Map or slice mutation: m[key] = value or s[i] = value.
 		if idx, ok := lhs.(*ast.IndexExpr); ok {
 			if isMapType(info, idx.X) {
 				name := exprName(idx.X)
-				key := "map:" + name
-				if !seen[key] {
-					seen[key] = true
-					loc := fset.Position(idx.Pos()).String()
-					effects = append(effects, taxonomy.SideEffect{
-						ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.MapMutation), name),
-						Type:        taxonomy.MapMutation,
-						Tier:        taxonomy.TierP1,
-						Location:    loc,
-						Description: fmt.Sprintf("writes to map '%s'", name),
-						Target:      name,
-					})
-				}
+				loc := fset.Position(idx.Pos()).String()
+				effects = append(effects, taxonomy.SideEffect{
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.MapMutation), name),
+					Type:        taxonomy.MapMutation,
+					Tier:        taxonomy.TierP1,
+					Location:    loc,
+					Description: fmt.Sprintf("writes to map '%s'", name),
+					Target:      name,
+				})
 			} else if isSliceType(info, idx.X) {
 				name := exprName(idx.X)
-				key := "slice:" + name
-				if !seen[key] {
-					seen[key] = true
-					loc := fset.Position(idx.Pos()).String()
-					effects = append(effects, taxonomy.SideEffect{
-						ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.SliceMutation), name),
-						Type:        taxonomy.SliceMutation,
-						Tier:        taxonomy.TierP1,
-						Location:    loc,
-						Description: fmt.Sprintf("writes to slice element '%s'", name),
-						Target:      name,
-					})
-				}
+				loc := fset.Position(idx.Pos()).String()
+				effects = append(effects, taxonomy.SideEffect{
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.SliceMutation), name),
+					Type:        taxonomy.SliceMutation,
+					Tier:        taxonomy.TierP1,
+					Location:    loc,
+					Description: fmt.Sprintf("writes to slice element '%s'", name),
+					Target:      name,
+				})
 			}
 		}
 	}
@@ -144,7 +131,6 @@ func detectIncDecEffects(
 	node *ast.IncDecStmt,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 	locals map[string]bool,
 ) []taxonomy.SideEffect {
 	ident, ok := node.X.(*ast.Ident)
@@ -154,11 +140,6 @@ func detectIncDecEffects(
 	if !isGlobalIdent(ident, info, locals) {
 		return nil
 	}
-	key := "global:" + ident.Name
-	if seen[key] {
-		return nil
-	}
-	seen[key] = true
 	loc := fset.Position(ident.Pos()).String()
 	return []taxonomy.SideEffect{{
 		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.GlobalMutation), ident.Name),
@@ -177,14 +158,8 @@ func detectSendEffects(
 	node *ast.SendStmt,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 ) []taxonomy.SideEffect {
 	name := exprName(node.Chan)
-	key := "chsend:" + name
-	if seen[key] {
-		return nil
-	}
-	seen[key] = true
 	loc := fset.Position(node.Pos()).String()
 	return []taxonomy.SideEffect{{
 		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ChannelSend), name),
@@ -206,45 +181,36 @@ func detectP1CallEffects(
 	node *ast.CallExpr,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 ) []taxonomy.SideEffect {
 	var effects []taxonomy.SideEffect
 
 	// Channel close: close(ch).
 	if isCloseCall(node, info) && len(node.Args) == 1 {
 		name := exprName(node.Args[0])
-		key := "chclose:" + name
-		if !seen[key] {
-			seen[key] = true
-			loc := fset.Position(node.Pos()).String()
-			effects = append(effects, taxonomy.SideEffect{
-				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ChannelClose), name),
-				Type:        taxonomy.ChannelClose,
-				Tier:        taxonomy.TierP1,
-				Location:    loc,
-				Description: fmt.Sprintf("closes channel '%s'", name),
-				Target:      name,
-			})
-		}
+		loc := fset.Position(node.Pos()).String()
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ChannelClose), name),
+			Type:        taxonomy.ChannelClose,
+			Tier:        taxonomy.TierP1,
+			Location:    loc,
+			Description: fmt.Sprintf("closes channel '%s'", name),
+			Target:      name,
+		})
 	}
 
 	// Writer output and HTTP response writes via selector expressions.
 	if sel, ok := node.Fun.(*ast.SelectorExpr); ok {
 		if sel.Sel.Name == "Write" && isWriterType(info, sel.X) {
 			name := exprName(sel.X)
-			key := "writer:" + name
-			if !seen[key] {
-				seen[key] = true
-				loc := fset.Position(node.Pos()).String()
-				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.WriterOutput), name),
-					Type:        taxonomy.WriterOutput,
-					Tier:        taxonomy.TierP1,
-					Location:    loc,
-					Description: fmt.Sprintf("writes to io.Writer '%s'", name),
-					Target:      name,
-				})
-			}
+			loc := fset.Position(node.Pos()).String()
+			effects = append(effects, taxonomy.SideEffect{
+				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.WriterOutput), name),
+				Type:        taxonomy.WriterOutput,
+				Tier:        taxonomy.TierP1,
+				Location:    loc,
+				Description: fmt.Sprintf("writes to io.Writer '%s'", name),
+				Target:      name,
+			})
 		}
 
 		// HTTP response writes: calls to
@@ -253,19 +219,15 @@ func detectP1CallEffects(
 			method := sel.Sel.Name
 			if method == "Write" || method == "WriteHeader" || method == "Header" {
 				name := exprName(sel.X)
-				key := "http:" + name + ":" + method
-				if !seen[key] {
-					seen[key] = true
-					loc := fset.Position(node.Pos()).String()
-					effects = append(effects, taxonomy.SideEffect{
-						ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.HTTPResponseWrite), name+"."+method),
-						Type:        taxonomy.HTTPResponseWrite,
-						Tier:        taxonomy.TierP1,
-						Location:    loc,
-						Description: fmt.Sprintf("calls %s.%s()", name, method),
-						Target:      name + "." + method,
-					})
-				}
+				loc := fset.Position(node.Pos()).String()
+				effects = append(effects, taxonomy.SideEffect{
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.HTTPResponseWrite), name+"."+method),
+					Type:        taxonomy.HTTPResponseWrite,
+					Tier:        taxonomy.TierP1,
+					Location:    loc,
+					Description: fmt.Sprintf("calls %s.%s()", name, method),
+					Target:      name + "." + method,
+				})
 			}
 		}
 	}
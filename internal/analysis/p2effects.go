@@ -6,6 +6,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"strings"
 
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
@@ -76,6 +77,8 @@ var p2SelectorEffects = map[string]map[string]taxonomy.SideEffectType{
 //   - CallbackInvocation: calling function-typed parameters
 //   - DatabaseWrite: db.Exec, db.ExecContext on *sql.DB/*sql.Tx/*sql.Stmt
 //   - DatabaseTransaction: db.Begin, db.BeginTx on *sql.DB
+//   - InterfaceInteraction: method calls on interface-typed
+//     parameters and receiver fields (repo.Save, s.notifier.Notify)
 func AnalyzeP2Effects(
 	fset *token.FileSet,
 	info *types.Info,
@@ -88,42 +91,41 @@ func AnalyzeP2Effects(
 	}
 
 	var effects []taxonomy.SideEffect
-	seen := make(map[string]bool)
 
 	// Build set of function-typed parameter names for callback detection.
 	funcParams := collectFuncParams(fd, info)
 
+	// Build set of the function's parameters and receiver, whose
+	// interface-typed values (and fields) are injected dependencies.
+	deps := collectDependencyRoots(fd, info)
+
 	ast.Inspect(fd.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.GoStmt:
 			effects = append(effects,
-				detectGoroutineEffects(fset, node, pkg, funcName, seen)...)
+				detectGoroutineEffects(fset, node, pkg, funcName)...)
 
 		case *ast.CallExpr:
 			effects = append(effects,
-				detectP2CallEffects(fset, info, node, pkg, funcName, seen, funcParams)...)
+				detectP2CallEffects(fset, info, node, pkg, funcName, funcParams, deps)...)
 		}
 		return true
 	})
 
-	return effects
+	// Repeated panics, goroutine spawns, and calls of the same
+	// callee are one effect with an occurrence per site.
+	return mergeOccurrences(effects)
 }
 
 // detectGoroutineEffects handles GoroutineSpawn detection from go
-// statements. It returns any new side effects found, using the shared
-// seen map for deduplication.
+// statements. Spawns of the same function share an ID.
 func detectGoroutineEffects(
 	fset *token.FileSet,
 	node *ast.GoStmt,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 ) []taxonomy.SideEffect {
-	key := fmt.Sprintf("goroutine:%d", fset.Position(node.Pos()).Line)
-	if seen[key] {
-		return nil
-	}
-	seen[key] = true
+	key := "goroutine:" + types.ExprString(node.Call.Fun)
 	loc := fset.Position(node.Pos()).String()
 	return []taxonomy.SideEffect{{
 		ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.GoroutineSpawn), key),
@@ -137,34 +139,31 @@ func detectGoroutineEffects(
 // detectP2CallEffects handles all P2 side effect detection from call
 // expressions: Panic, selector-based effects (FileSystemWrite,
 // FileSystemDelete, FileSystemMeta, LogWrite, ContextCancellation),
-// DatabaseWrite, DatabaseTransaction, and CallbackInvocation. It
-// returns any new side effects found, using the shared seen map for
-// deduplication and funcParams for callback detection.
+// DatabaseWrite, DatabaseTransaction, CallbackInvocation, and
+// InterfaceInteraction. It returns any new side effects found, using
+// funcParams for callback detection and deps for interface
+// interaction detection. Keys name the callee, not the call site.
 func detectP2CallEffects(
 	fset *token.FileSet,
 	info *types.Info,
 	node *ast.CallExpr,
 	pkg string,
 	funcName string,
-	seen map[string]bool,
 	funcParams map[string]bool,
+	deps map[types.Object]bool,
 ) []taxonomy.SideEffect {
 	var effects []taxonomy.SideEffect
 
 	// Panic: builtin panic() call.
 	if isPanicCall(node, info) {
-		key := fmt.Sprintf("panic:%d", fset.Position(node.Pos()).Line)
-		if !seen[key] {
-			seen[key] = true
-			loc := fset.Position(node.Pos()).String()
-			effects = append(effects, taxonomy.SideEffect{
-				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.Panic), key),
-				Type:        taxonomy.Panic,
-				Tier:        taxonomy.TierP2,
-				Location:    loc,
-				Description: "calls panic()",
-			})
-		}
+		loc := fset.Position(node.Pos()).String()
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.Panic), "panic"),
+			Type:        taxonomy.Panic,
+			Tier:        taxonomy.TierP2,
+			Location:    loc,
+			Description: "calls panic()",
+		})
 	}
 
 	// Selector-based detection (pkg.Func pattern).
@@ -179,66 +178,68 @@ func detectP2CallEffects(
 			pkgName := resolveImportPath(ident, info)
 			if funcs, ok := p2SelectorEffects[pkgName]; ok {
 				if effectType, ok := funcs[sel.Sel.Name]; ok {
-					key := fmt.Sprintf("%s:%s.%s:%d",
-						effectType, ident.Name, sel.Sel.Name,
-						fset.Position(node.Pos()).Line)
-					if !seen[key] {
-						seen[key] = true
-						loc := fset.Position(node.Pos()).String()
-						effects = append(effects, taxonomy.SideEffect{
-							ID:          taxonomy.GenerateID(pkg, funcName, string(effectType), key),
-							Type:        effectType,
-							Tier:        taxonomy.TierP2,
-							Location:    loc,
-							Description: fmt.Sprintf("calls %s.%s", ident.Name, sel.Sel.Name),
-							Target:      fmt.Sprintf("%s.%s", ident.Name, sel.Sel.Name),
-						})
-					}
-				}
-			}
-		}
-
-		// Database detection: Exec/ExecContext/Begin/BeginTx on *sql.DB/Tx/Stmt.
-		if isDatabaseMethod(sel, info) {
-			effectType := databaseMethodEffect(sel.Sel.Name)
-			if effectType != "" {
-				key := fmt.Sprintf("%s:%s:%d",
-					effectType, sel.Sel.Name,
-					fset.Position(node.Pos()).Line)
-				if !seen[key] {
-					seen[key] = true
+					key := fmt.Sprintf("%s:%s.%s", effectType, pkgName, sel.Sel.Name)
 					loc := fset.Position(node.Pos()).String()
 					effects = append(effects, taxonomy.SideEffect{
 						ID:          taxonomy.GenerateID(pkg, funcName, string(effectType), key),
 						Type:        effectType,
 						Tier:        taxonomy.TierP2,
 						Location:    loc,
-						Description: fmt.Sprintf("calls %s on database type", sel.Sel.Name),
-						Target:      sel.Sel.Name,
+						Description: fmt.Sprintf("calls %s.%s", ident.Name, sel.Sel.Name),
+						Target:      fmt.Sprintf("%s.%s", ident.Name, sel.Sel.Name),
 					})
 				}
 			}
 		}
-	}
 
-	// Callback invocation: calling a function-typed parameter.
-	if ident, ok := node.Fun.(*ast.Ident); ok {
-		if funcParams[ident.Name] {
-			key := fmt.Sprintf("callback:%s:%d",
-				ident.Name, fset.Position(node.Pos()).Line)
-			if !seen[key] {
-				seen[key] = true
+		// Database detection: Exec/ExecContext/Begin/BeginTx on *sql.DB/Tx/Stmt.
+		if isDatabaseMethod(sel, info) {
+			effectType := databaseMethodEffect(sel.Sel.Name)
+			if effectType != "" {
+				key := fmt.Sprintf("%s:%s", effectType, sel.Sel.Name)
 				loc := fset.Position(node.Pos()).String()
 				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.CallbackInvocation), key),
-					Type:        taxonomy.CallbackInvocation,
+					ID:          taxonomy.GenerateID(pkg, funcName, string(effectType), key),
+					Type:        effectType,
 					Tier:        taxonomy.TierP2,
 					Location:    loc,
-					Description: fmt.Sprintf("invokes callback parameter '%s'", ident.Name),
-					Target:      ident.Name,
+					Description: fmt.Sprintf("calls %s on database type", sel.Sel.Name),
+					Target:      sel.Sel.Name,
 				})
 			}
 		}
+
+		// Interface interaction: a method call on an injected
+		// interface-typed dependency.
+		if dep, iface, ok := interfaceDependency(sel, info, deps); ok {
+			target := iface + "." + sel.Sel.Name
+			key := fmt.Sprintf("iface:%s.%s", dep, sel.Sel.Name)
+			loc := fset.Position(node.Pos()).String()
+			effects = append(effects, taxonomy.SideEffect{
+				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.InterfaceInteraction), key),
+				Type:        taxonomy.InterfaceInteraction,
+				Tier:        taxonomy.TierP2,
+				Location:    loc,
+				Description: fmt.Sprintf("calls %s on interface dependency '%s'", target, dep),
+				Target:      target,
+			})
+		}
+	}
+
+	// Callback invocation: calling a function-typed parameter.
+	if ident, ok := node.Fun.(*ast.Ident); ok {
+		if funcParams[ident.Name] {
+			key := "callback:" + ident.Name
+			loc := fset.Position(node.Pos()).String()
+			effects = append(effects, taxonomy.SideEffect{
+				ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.CallbackInvocation), key),
+				Type:        taxonomy.CallbackInvocation,
+				Tier:        taxonomy.TierP2,
+				Location:    loc,
+				Description: fmt.Sprintf("invokes callback parameter '%s'", ident.Name),
+				Target:      ident.Name,
+			})
+		}
 	}
 
 	return effects
@@ -302,6 +303,104 @@ func collectFuncParams(fd *ast.FuncDecl, info *types.Info) map[string]bool {
 	return params
 }
 
+// collectDependencyRoots returns the objects of a function's
+// parameters and receiver. Interface values reached from them are
+// dependencies injected by the caller.
+func collectDependencyRoots(fd *ast.FuncDecl, info *types.Info) map[types.Object]bool {
+	roots := make(map[types.Object]bool)
+	if info == nil {
+		return roots
+	}
+	var fields []*ast.Field
+	if fd.Recv != nil {
+		fields = append(fields, fd.Recv.List...)
+	}
+	if fd.Type.Params != nil {
+		fields = append(fields, fd.Type.Params.List...)
+	}
+	for _, field := range fields {
+		for _, name := range field.Names {
+			if obj := info.Defs[name]; obj != nil {
+				roots[obj] = true
+			}
+		}
+	}
+	return roots
+}
+
+// interfaceDependency reports whether sel is a method call on an
+// interface-typed value rooted at one of deps: a parameter (repo.Save)
+// or a field of the receiver or a parameter (s.repo.Save). Package-level
+// variables (defaultPublisher.Publish) are dependencies too, since they
+// are typically wired up at program start. It returns
+// the dependency expression and the interface's name. Interfaces
+// declared in the standard library are skipped; their effects (e.g.,
+// io.Writer output) have dedicated types or are incidental plumbing
+// such as context.Context.
+func interfaceDependency(
+	sel *ast.SelectorExpr,
+	info *types.Info,
+	deps map[types.Object]bool,
+) (dep, iface string, ok bool) {
+	if info == nil {
+		return "", "", false
+	}
+	selection, found := info.Selections[sel]
+	if !found || selection.Kind() != types.MethodVal {
+		return "", "", false
+	}
+	recv := selection.Recv()
+	if !types.IsInterface(recv) {
+		return "", "", false
+	}
+	iface = interfaceName(recv)
+	if iface == "" {
+		return "", "", false
+	}
+
+	// Resolve the receiver expression to its root identifier.
+	root := sel.X
+	for {
+		inner, isSel := root.(*ast.SelectorExpr)
+		if !isSel {
+			break
+		}
+		root = inner.X
+	}
+	ident, isIdent := root.(*ast.Ident)
+	if !isIdent {
+		return "", "", false
+	}
+	obj := info.Uses[ident]
+	if !deps[obj] && !isPackageVar(obj) {
+		return "", "", false
+	}
+	return exprName(sel.X), iface, true
+}
+
+// isPackageVar reports whether obj is a variable declared at package
+// scope.
+func isPackageVar(obj types.Object) bool {
+	v, ok := obj.(*types.Var)
+	return ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope()
+}
+
+// interfaceName returns the name of a non-stdlib named interface type,
+// "interface" for an anonymous interface, or "" for interfaces
+// declared in the standard library or the universe scope (error). An
+// alias is resolved to the interface it names.
+func interfaceName(t types.Type) string {
+	named, ok := types.Unalias(t).(*types.Named)
+	if !ok {
+		return "interface"
+	}
+	obj := named.Obj()
+	if obj.Pkg() == nil || !strings.Contains(obj.Pkg().Path(), ".") {
+		return ""
+	}
+	return obj.Name()
+}
+
 // isDatabaseMethod checks if a selector expression's receiver is a
 // database/sql type (*sql.DB, *sql.Tx, *sql.Stmt).
 func isDatabaseMethod(sel *ast.SelectorExpr, info *types.Info) bool {
@@ -217,6 +217,71 @@ func TestAnalyzeP2Effects_Direct_DatabaseWrite(t *testing.T) {
 	}
 }
 
+// TestAnalyzeP2Effects_Direct_InterfaceInteraction verifies that
+// AnalyzeP2Effects detects method calls on interface-typed parameters,
+// receiver fields, and package-level variables, with the interface and
+// method as Target. An interface named through a type alias is
+// reported under the name of the aliased interface.
+func TestAnalyzeP2Effects_Direct_InterfaceInteraction(t *testing.T) {
+	pkg := loadTestPackage(t, "p2effects")
+
+	tests := []struct {
+		name    string
+		fd      *ast.FuncDecl
+		targets []string
+	}{
+		{"SaveUser", analysis.FindFuncDecl(pkg, "SaveUser"), []string{"Repository.Save"}},
+		{"Register", analysis.FindMethodDecl(pkg, "*UserService", "Register"),
+			[]string{"Repository.Save", "Notifier.Notify"}},
+		{"Announce", analysis.FindFuncDecl(pkg, "Announce"), []string{"Notifier.Notify"}},
+		{"PutValue", analysis.FindFuncDecl(pkg, "PutValue"), []string{"Store.Put"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.fd == nil {
+				t.Fatalf("%s not found in p2effects package", tt.name)
+			}
+			effects := analysis.AnalyzeP2Effects(pkg.Fset, pkg.TypesInfo, tt.fd, pkg.PkgPath, tt.name)
+
+			var got []string
+			for _, e := range effects {
+				if e.Type != taxonomy.InterfaceInteraction {
+					continue
+				}
+				if e.Tier != taxonomy.TierP2 {
+					t.Errorf("InterfaceInteraction tier: got %s, want P2", e.Tier)
+				}
+				got = append(got, e.Target)
+			}
+			if len(got) != len(tt.targets) {
+				t.Fatalf("targets: got %v, want %v", got, tt.targets)
+			}
+			for i := range got {
+				if got[i] != tt.targets[i] {
+					t.Errorf("target %d: got %q, want %q", i, got[i], tt.targets[i])
+				}
+			}
+		})
+	}
+}
+
+// TestAnalyzeP2Effects_Direct_NoInterfaceInteraction verifies that
+// calls on builtin interfaces and on locally constructed interface
+// values are not reported as interactions.
+func TestAnalyzeP2Effects_Direct_NoInterfaceInteraction(t *testing.T) {
+	pkg := loadTestPackage(t, "p2effects")
+	for _, name := range []string{"ErrorMessage", "LocalRepository"} {
+		fd := analysis.FindFuncDecl(pkg, name)
+		if fd == nil {
+			t.Fatalf("%s not found in p2effects package", name)
+		}
+		effects := analysis.AnalyzeP2Effects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, name)
+		if hasEffect(effects, taxonomy.InterfaceInteraction) {
+			t.Errorf("%s: unexpected InterfaceInteraction effect: %v", name, effects)
+		}
+	}
+}
+
 // TestAnalyzeP2Effects_Direct_PureFunction verifies that AnalyzeP2Effects
 // returns an empty slice for a function with no P2 side effects.
 func TestAnalyzeP2Effects_Direct_PureFunction(t *testing.T) {
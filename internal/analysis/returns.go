@@ -53,11 +53,12 @@ func AnalyzeReturns(
 			}
 
 			loc := fset.Position(field.Pos()).String()
+			key := fmt.Sprintf("result:%d", pos)
 			desc := formatReturnDesc(typeStr, pos, name)
 
 			if isError {
 				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ErrorReturn), loc),
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ErrorReturn), key),
 					Type:        taxonomy.ErrorReturn,
 					Tier:        taxonomy.TierP0,
 					Location:    loc,
@@ -66,7 +67,7 @@ func AnalyzeReturns(
 				})
 			} else {
 				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ReturnValue), loc),
+					ID:          taxonomy.GenerateID(pkg, funcName, string(taxonomy.ReturnValue), key),
 					Type:        taxonomy.ReturnValue,
 					Tier:        taxonomy.TierP0,
 					Location:    loc,
@@ -177,7 +178,7 @@ func receiverName(fd *ast.FuncDecl) string {
 	if fd.Recv == nil || len(fd.Recv.List) == 0 {
 		return ""
 	}
-	return typeExprString(fd.Recv.List[0].Type)
+	return types.ExprString(fd.Recv.List[0].Type)
 }
 
 // typeExprString converts a type expression to a string like "*T" or "T".
@@ -191,6 +192,12 @@ func typeExprString(expr ast.Expr) string {
 		return typeExprString(t.X) + "." + t.Sel.Name
 	case *ast.IndexExpr:
 		return typeExprString(t.X) + "[" + typeExprString(t.Index) + "]"
+	case *ast.IndexListExpr:
+		indices := make([]string, len(t.Indices))
+		for i, index := range t.Indices {
+			indices[i] = typeExprString(index)
+		}
+		return typeExprString(t.X) + "[" + strings.Join(indices, ", ") + "]"
 	default:
 		return fmt.Sprintf("%T", expr)
 	}
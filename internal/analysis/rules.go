@@ -0,0 +1,216 @@
+package analysis
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// ruleSet indexes detection rules by the canonical name of the
+// function they match. Rules with a "*" wildcard are kept as patterns
+// and tried in order after the exact names.
+type ruleSet struct {
+	exact    map[string]config.EffectRule
+	patterns []rulePattern
+}
+
+// rulePattern is a compiled wildcard rule.
+type rulePattern struct {
+	re   *regexp.Regexp
+	rule config.EffectRule
+}
+
+// newRuleSet indexes rules by canonical function name. A later rule
+// for the same function replaces an earlier one, and later patterns
+// are tried first, so that custom rules override rule packs.
+func newRuleSet(rules []config.EffectRule) *ruleSet {
+	if len(rules) == 0 {
+		return nil
+	}
+	set := &ruleSet{exact: make(map[string]config.EffectRule, len(rules))}
+	for _, r := range rules {
+		name := canonicalRuleName(r.Function)
+		if !strings.Contains(name, "*") {
+			set.exact[name] = r
+			continue
+		}
+		set.patterns = append([]rulePattern{{re: compileRulePattern(name), rule: r}}, set.patterns...)
+	}
+	return set
+}
+
+// match returns the rule for the function with the given canonical
+// name.
+func (s *ruleSet) match(name string) (config.EffectRule, bool) {
+	if name == "" {
+		return config.EffectRule{}, false
+	}
+	if r, ok := s.exact[name]; ok {
+		return r, true
+	}
+	for _, p := range s.patterns {
+		if p.re.MatchString(name) {
+			return p.rule, true
+		}
+	}
+	return config.EffectRule{}, false
+}
+
+// compileRulePattern compiles a canonical wildcard rule name. A "*"
+// in the package path matches any characters; a "*" in the function
+// or method name does not cross a ".", so that "log/slog.Log*" does
+// not match the methods of slog.Logger.
+func compileRulePattern(name string) *regexp.Regexp {
+	pkgPath, funcName := splitRuleName(name)
+	quote := func(s, star string) string {
+		return strings.ReplaceAll(regexp.QuoteMeta(s), `\*`, star)
+	}
+	return regexp.MustCompile("^" + quote(pkgPath, ".*") + `\.` + quote(funcName, "[^.]*") + "$")
+}
+
+// splitRuleName splits a canonical rule name into its package path
+// and its function name, which may be qualified by a receiver type
+// ("Producer.Send"). The package path ends at the first "." after its
+// last "/".
+func splitRuleName(name string) (pkgPath, funcName string) {
+	slash := strings.LastIndex(name, "/")
+	dot := strings.Index(name[slash+1:], ".")
+	if dot < 0 {
+		return name, ""
+	}
+	return name[:slash+1+dot], name[slash+1+dot+1:]
+}
+
+// canonicalRuleName normalizes a rule's function name by dropping the
+// receiver punctuation, so that "example.com/kafka.(*Producer).Send",
+// "example.com/kafka.(Producer).Send" and
+// "example.com/kafka.Producer.Send" all match the same method,
+// regardless of whether it is declared on a pointer receiver.
+func canonicalRuleName(name string) string {
+	return strings.NewReplacer("(*", "", "(", "", ")", "").Replace(name)
+}
+
+// canonicalFuncName returns the canonical rule name of fn: its
+// package path, receiver type name (for methods), and name.
+func canonicalFuncName(fn *types.Func) string {
+	if fn.Pkg() == nil {
+		return ""
+	}
+	sig, ok := fn.Type().(*types.Signature)
+	if !ok || sig.Recv() == nil {
+		return fn.Pkg().Path() + "." + fn.Name()
+	}
+	recv := sig.Recv().Type()
+	if ptr, ok := recv.(*types.Pointer); ok {
+		recv = ptr.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return ""
+	}
+	return fn.Pkg().Path() + "." + named.Obj().Name() + "." + fn.Name()
+}
+
+// AnalyzeRuleEffects detects calls matched by the detection rules
+// configured in .gaze.yaml. Each matched call produces an effect of
+// the rule's type, with the tier the taxonomy assigns to built-in
+// types or the tier declared for a custom type. The rules are
+// compiled on every call; Analyze compiles them once per package.
+func AnalyzeRuleEffects(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	rules []config.EffectRule,
+) []taxonomy.SideEffect {
+	return analyzeRuleEffects(fset, info, fd, pkg, funcName, newRuleSet(rules))
+}
+
+// analyzeRuleEffects is AnalyzeRuleEffects with the rules already
+// compiled into set, which may be nil when there are none.
+func analyzeRuleEffects(
+	fset *token.FileSet,
+	info *types.Info,
+	fd *ast.FuncDecl,
+	pkg string,
+	funcName string,
+	set *ruleSet,
+) []taxonomy.SideEffect {
+	if fd.Body == nil || info == nil || set == nil {
+		return nil
+	}
+
+	var effects []taxonomy.SideEffect
+
+	ast.Inspect(fd.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		fn := calledFunc(call, info)
+		if fn == nil {
+			return true
+		}
+		rule, ok := set.match(canonicalFuncName(fn.Origin()))
+		if !ok {
+			return true
+		}
+
+		effectType := taxonomy.SideEffectType(rule.Effect)
+		tier := taxonomy.TierOf(effectType)
+		if rule.Tier != "" {
+			tier = taxonomy.Tier(rule.Tier)
+		}
+		target := ruleTarget(fn)
+		key := fmt.Sprintf("rule:%s:%s", effectType, target)
+		effects = append(effects, taxonomy.SideEffect{
+			ID:          taxonomy.GenerateID(pkg, funcName, string(effectType), key),
+			Type:        effectType,
+			Tier:        tier,
+			Location:    fset.Position(call.Pos()).String(),
+			Description: fmt.Sprintf("calls %s (detection rule)", target),
+			Target:      target,
+		})
+		return true
+	})
+
+	return mergeOccurrences(effects)
+}
+
+// calledFunc returns the function or method a call statically
+// invokes, or nil for calls of function values and builtins.
+func calledFunc(call *ast.CallExpr, info *types.Info) *types.Func {
+	var ident *ast.Ident
+	switch fun := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	case *ast.IndexExpr:
+		return calledFunc(&ast.CallExpr{Fun: fun.X}, info)
+	case *ast.IndexListExpr:
+		return calledFunc(&ast.CallExpr{Fun: fun.X}, info)
+	default:
+		return nil
+	}
+	fn, _ := info.Uses[ident].(*types.Func)
+	return fn
+}
+
+// ruleTarget returns the short display name of a rule-matched
+// function: "kafka.Record" for functions, "Producer.Send" for methods.
+func ruleTarget(fn *types.Func) string {
+	full := canonicalFuncName(fn.Origin())
+	if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+		// Drop the package path, keeping "Type.Method".
+		return strings.TrimPrefix(full, fn.Pkg().Path()+".")
+	}
+	return fn.Pkg().Name() + "." + fn.Name()
+}
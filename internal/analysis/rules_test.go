@@ -0,0 +1,181 @@
+package analysis_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// fixtureRules returns detection rules for the rules fixture: a
+// custom type on a method of a fixture type, a built-in type on a
+// package function, and a custom type on a stdlib method.
+func fixtureRules(pkgPath string) []config.EffectRule {
+	return []config.EffectRule{
+		{Function: pkgPath + ".(*Producer).Send", Effect: "EventPublish", Tier: "P1"},
+		{Function: pkgPath + ".Record", Effect: string(taxonomy.LogWrite)},
+		{Function: "net/http.(*Client).Get", Effect: "OutboundHTTP", Tier: "P2"},
+	}
+}
+
+// TestAnalyzeRuleEffects verifies that calls matched by detection
+// rules produce effects of the rule's type, with the taxonomy tier
+// for built-in types and the declared tier for custom types.
+func TestAnalyzeRuleEffects(t *testing.T) {
+	pkg := loadTestPackage(t, "rules")
+	rules := fixtureRules(pkg.PkgPath)
+
+	tests := []struct {
+		function string
+		want     []taxonomy.SideEffect
+	}{
+		{"PlaceOrder", []taxonomy.SideEffect{
+			{Type: taxonomy.LogWrite, Tier: taxonomy.TierP2, Target: "rules.Record"},
+			{Type: "EventPublish", Tier: taxonomy.TierP1, Target: "Producer.Send"},
+		}},
+		{"Fetch", []taxonomy.SideEffect{
+			{Type: "OutboundHTTP", Tier: taxonomy.TierP2, Target: "Client.Get"},
+		}},
+		{"Unmatched", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in rules package", tt.function)
+			}
+			effects := analysis.AnalyzeRuleEffects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function, rules)
+			if len(effects) != len(tt.want) {
+				t.Fatalf("got %d effects %v, want %d", len(effects), effects, len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := effects[i]
+				if got.Type != want.Type || got.Tier != want.Tier || got.Target != want.Target {
+					t.Errorf("effect %d: got {%s %s %s}, want {%s %s %s}", i,
+						got.Type, got.Tier, got.Target, want.Type, want.Tier, want.Target)
+				}
+				if got.ID == "" || got.Location == "" {
+					t.Errorf("effect %d: missing ID or location: %+v", i, got)
+				}
+			}
+		})
+	}
+}
+
+// TestAnalyzeRuleEffects_AcceptsValueReceiverSpelling verifies that a
+// rule written without the pointer receiver matches a method declared
+// on a pointer receiver.
+func TestAnalyzeRuleEffects_AcceptsValueReceiverSpelling(t *testing.T) {
+	pkg := loadTestPackage(t, "rules")
+	fd := analysis.FindFuncDecl(pkg, "PlaceOrder")
+	if fd == nil {
+		t.Fatal("PlaceOrder not found in rules package")
+	}
+	rules := []config.EffectRule{
+		{Function: pkg.PkgPath + ".Producer.Send", Effect: "EventPublish", Tier: "P1"},
+	}
+	effects := analysis.AnalyzeRuleEffects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, "PlaceOrder", rules)
+	if !hasEffect(effects, "EventPublish") {
+		t.Errorf("expected EventPublish effect, got %v", effects)
+	}
+}
+
+// TestAnalyze_WithRules verifies that Analyze applies the rules in
+// Options alongside the built-in detectors.
+func TestAnalyze_WithRules(t *testing.T) {
+	pkg := loadTestPackage(t, "rules")
+	results, err := analysis.Analyze(pkg, analysis.Options{
+		FunctionFilter: "PlaceOrder",
+		Rules:          fixtureRules(pkg.PkgPath),
+	})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	effects := results[0].SideEffects
+	if !hasEffect(effects, taxonomy.ErrorReturn) {
+		t.Errorf("expected built-in ErrorReturn effect, got %v", effects)
+	}
+	if !hasEffect(effects, "EventPublish") {
+		t.Errorf("expected EventPublish effect from rule, got %v", effects)
+	}
+}
+
+// packRules returns the rules of the named built-in rule packs.
+func packRules(t *testing.T, names ...string) []config.EffectRule {
+	t.Helper()
+	var rules []config.EffectRule
+	for _, name := range names {
+		pack, err := config.RulePack(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, pack...)
+	}
+	return rules
+}
+
+// TestAnalyzeRuleEffects_Packs verifies that the wildcard rules of
+// built-in packs match method calls by name: *slog.Logger logging
+// methods but not Logger.With, and generated ent builders' Save but
+// not queries.
+func TestAnalyzeRuleEffects_Packs(t *testing.T) {
+	pkg := loadTestPackage(t, "rules")
+	rules := packRules(t, "slog", "ent")
+
+	tests := []struct {
+		function string
+		want     []string
+	}{
+		// slog.Info is matched by the pack too; Analyze drops the
+		// duplicate of the built-in detection.
+		{"AuditLogin", []string{"LogWrite:slog.Info", "LogWrite:Logger.Info"}},
+		{"CreateUser", []string{"DatabaseWrite:UserCreate.Save"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in rules package", tt.function)
+			}
+			var got []string
+			for _, e := range analysis.AnalyzeRuleEffects(pkg.Fset, pkg.TypesInfo, fd, pkg.PkgPath, tt.function, rules) {
+				got = append(got, string(e.Type)+":"+e.Target)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestAnalyze_RulesDoNotDuplicateBuiltins verifies that a rule
+// matching a call the built-in detectors already report does not
+// produce a second effect.
+func TestAnalyze_RulesDoNotDuplicateBuiltins(t *testing.T) {
+	pkg := loadTestPackage(t, "rules")
+	results, err := analysis.Analyze(pkg, analysis.Options{
+		FunctionFilter: "AuditLogin",
+		Rules:          packRules(t, "slog"),
+	})
+	if err != nil {
+		t.Fatalf("Analyze: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("got %d results, want 1", len(results))
+	}
+	logWrites := 0
+	for _, e := range results[0].SideEffects {
+		if e.Type == taxonomy.LogWrite {
+			logWrites++
+		}
+	}
+	if logWrites != 2 {
+		t.Errorf("got %d LogWrite effects, want 2 (slog.Info and Logger.Info): %v",
+			logWrites, results[0].SideEffects)
+	}
+}
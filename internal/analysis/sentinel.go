@@ -4,6 +4,8 @@ package analysis
 import (
 	"go/ast"
 	"go/token"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/unbound-force/gaze/internal/taxonomy"
@@ -67,7 +69,7 @@ func AnalyzeSentinels(
 				}
 
 				effects = append(effects, taxonomy.SideEffect{
-					ID:          taxonomy.GenerateID(pkg, "", string(taxonomy.SentinelError), loc),
+					ID:          taxonomy.GenerateID(pkg, "", string(taxonomy.SentinelError), name.Name),
 					Type:        taxonomy.SentinelError,
 					Tier:        taxonomy.TierP0,
 					Location:    loc,
@@ -120,8 +122,12 @@ func hasWrapVerb(call *ast.CallExpr) bool {
 		return false
 	}
 	lit, ok := call.Args[0].(*ast.BasicLit)
-	if !ok {
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	format, err := strconv.Unquote(lit.Value)
+	if err != nil {
 		return false
 	}
-	return strings.Contains(lit.Value, "%w")
+	return slices.Contains(formatVerbs(format), 'w')
 }
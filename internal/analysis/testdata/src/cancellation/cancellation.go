@@ -0,0 +1,50 @@
+// Package cancellation is a test fixture for functions that honour,
+// or ignore, the cancellation of a context parameter.
+package cancellation
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Wait blocks until ctx is done or d elapses.
+func Wait(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// Poll checks for cancellation between steps.
+func Poll(ctx context.Context, steps int) error {
+	for i := 0; i < steps; i++ {
+		if ctx.Err() != nil {
+			return context.Canceled
+		}
+	}
+	return nil
+}
+
+// Fetch passes a derived context to the request.
+func Fetch(ctx context.Context, c *http.Client, url string) (*http.Response, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Ignore takes a context but never consults it.
+func Ignore(ctx context.Context, n int) int {
+	return n * 2
+}
+
+// Detach deliberately drops cancellation.
+func Detach(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
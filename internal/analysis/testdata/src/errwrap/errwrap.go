@@ -0,0 +1,65 @@
+// Package errwrap is a test fixture for error-wrapping contracts.
+package errwrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// ErrNotFound is returned when a key is missing.
+var ErrNotFound = errors.New("not found")
+
+// Open wraps the error from os.Open.
+func Open(name string) (*os.File, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", name, err)
+	}
+	return f, nil
+}
+
+// Lookup wraps a sentinel.
+func Lookup(key string) error {
+	return fmt.Errorf("lookup %q: %w", key, ErrNotFound)
+}
+
+// Flatten formats the error from os.Remove as text.
+func Flatten(name string) error {
+	if err := os.Remove(name); err != nil {
+		return fmt.Errorf("removing %s: %v", name, err)
+	}
+	return nil
+}
+
+// CloseAll joins the errors of closing both files.
+func CloseAll(a, b io.Closer) error {
+	errA := a.Close()
+	errB := b.Close()
+	return errors.Join(errA, errB)
+}
+
+// Rewrap wraps through a variable before returning it.
+func Rewrap(name string) error {
+	_, err := os.Stat(name)
+	if err != nil {
+		err = fmt.Errorf("stat: %w", err)
+	}
+	return err
+}
+
+// Annotate wraps its parameter.
+func Annotate(err error) error {
+	return fmt.Errorf("annotated: %w", err)
+}
+
+// Plain creates a new error without wrapping.
+func Plain(name string) error {
+	return fmt.Errorf("bad name %q", name)
+}
+
+// Escaped does not wrap: the %% escapes the verb.
+func Escaped(err error) error {
+	return fmt.Errorf("100%%w done: %v", err)
+}
@@ -0,0 +1,46 @@
+// Package fields is a test fixture for per-field contract detection
+// on returned structs.
+package fields
+
+import "errors"
+
+// Config is a struct returned by constructors.
+type Config struct {
+	Name    string
+	Port    int
+	Debug   bool
+	Tags    []string
+	Limits  struct{ Max int }
+	comment string
+}
+
+// NewConfig sets fields in a composite literal.
+func NewConfig(name string) *Config {
+	return &Config{Name: name, Port: 8080}
+}
+
+// Parse sets fields after allocation and returns an error too.
+func Parse(s string) (*Config, error) {
+	if s == "" {
+		return nil, errors.New("empty")
+	}
+	cfg := &Config{}
+	cfg.Name = s
+	cfg.Limits.Max = 10
+	cfg.Tags = append(cfg.Tags, "parsed")
+	return cfg, nil
+}
+
+// Default returns a struct by value.
+func Default(debug bool) Config {
+	c := Config{Name: "default"}
+	if debug {
+		c.Debug = true
+	}
+	return c
+}
+
+// Copy returns a value it did not allocate.
+func Copy(c *Config) *Config {
+	return c
+}
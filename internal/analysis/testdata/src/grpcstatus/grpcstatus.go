@@ -0,0 +1,64 @@
+// Package grpcstatus is a test fixture for service methods that
+// return gRPC status errors.
+package grpcstatus
+
+import (
+	"context"
+	"errors"
+
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/codes"
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/status"
+)
+
+// User is a stored user.
+type User struct {
+	ID    string
+	Owner string
+}
+
+// Server implements the user service.
+type Server struct {
+	users map[string]User
+}
+
+// GetUser fails with InvalidArgument, NotFound, or PermissionDenied.
+func (s *Server) GetUser(ctx context.Context, id, caller string) (*User, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	u, ok := s.users[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", id)
+	}
+	if u.Owner != caller {
+		st := status.New(codes.PermissionDenied, "not the owner")
+		st, _ = st.WithDetails(caller)
+		return nil, st.Err()
+	}
+	return &u, nil
+}
+
+// DeleteUser reports an internal error through a local variable.
+func (s *Server) DeleteUser(ctx context.Context, id string) error {
+	var err error
+	if _, ok := s.users[id]; !ok {
+		err = status.Error(codes.Code(5), "no such user")
+	}
+	return err
+}
+
+// Translate maps a code computed at run time.
+func Translate(c codes.Code) error {
+	return status.Error(c, "translated")
+}
+
+// Plain returns an ordinary error.
+func Plain() error {
+	return errors.New("plain")
+}
+
+// Unreturned builds a status it never returns.
+func Unreturned() error {
+	_ = status.Error(codes.Internal, "dropped")
+	return nil
+}
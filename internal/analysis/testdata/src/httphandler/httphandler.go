@@ -0,0 +1,61 @@
+// Package httphandler is a test fixture for the status codes and
+// headers an HTTP handler can respond with.
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Item is the resource served by the handlers.
+type Item struct {
+	ID string `json:"id"`
+}
+
+// GetItem responds 400, 404, or an implicit 200 with a JSON body.
+func GetItem(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "missing id", http.StatusBadRequest)
+		return
+	}
+	if id == "missing" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("content-type", "application/json")
+	json.NewEncoder(w).Encode(Item{ID: id})
+}
+
+// Server serves items.
+type Server struct {
+	items map[string]Item
+}
+
+// ServeHTTP responds 201 with a Location header, 405, or 500.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Add("Allow", http.MethodPost)
+		w.WriteHeader(405)
+		return
+	}
+	var item Item
+	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+		writeJSON(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	s.items[item.ID] = item
+	w.Header().Set("Location", "/items/"+item.ID)
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Legacy redirects to the new path.
+func Legacy(w http.ResponseWriter, r *http.Request) {
+	http.Redirect(w, r, "/items", http.StatusMovedPermanently)
+}
+
+// writeJSON writes v with the given status.
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,46 @@
+// Package ids exercises side effect ID generation.
+package ids
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrEmpty is a sentinel error.
+var ErrEmpty = errors.New("empty")
+
+var registry = map[string]int{}
+
+func init() {
+	registry["a"] = 1
+}
+
+func init() {
+	registry["b"] = 2
+}
+
+// A and B have a field of the same name.
+type A struct{ count int }
+
+// B has a field of the same name as A's.
+type B struct{ count int }
+
+// Reset clears A's count.
+func (a *A) Reset() { a.count = 0 }
+
+// Reset clears B's count.
+func (b *B) Reset() { b.count = 0 }
+
+// Save writes data to path, logging to stderr when it fails.
+func Save(path string, data []byte) (int, error) {
+	if len(data) == 0 {
+		return 0, ErrEmpty
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile(path+".bak", data, 0o600); err != nil {
+		panic(err)
+	}
+	return len(data), nil
+}
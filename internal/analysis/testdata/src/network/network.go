@@ -0,0 +1,71 @@
+// Package network is a test fixture for network, messaging, and
+// metric side effects.
+package network
+
+import (
+	"context"
+	"expvar"
+	"net"
+	"net/http"
+	"strings"
+)
+
+var requests = expvar.NewInt("requests")
+
+// Connect dials the given address.
+func Connect(addr string) (net.Conn, error) {
+	return net.Dial("tcp", addr)
+}
+
+// Serve listens on the given address.
+func Serve(ctx context.Context, addr string) (net.Listener, error) {
+	var lc net.ListenConfig
+	return lc.Listen(ctx, "tcp", addr)
+}
+
+// CreateUser posts a user to the API.
+func CreateUser(ctx context.Context, c *http.Client, url, body string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Ping sends a HEAD request with the default client.
+func Ping(url string) error {
+	resp, err := http.Head(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Count increments the request counter.
+func Count() {
+	requests.Add(1)
+}
+
+// BuildRequest builds a request without sending it.
+func BuildRequest(url string) (*http.Request, error) {
+	return http.NewRequest(http.MethodGet, url, nil)
+}
+
+// Queue is a message queue port.
+type Queue interface {
+	Enqueue(msg string) error
+}
+
+// Dispatcher sends jobs to a queue.
+type Dispatcher struct {
+	queue Queue
+}
+
+// Dispatch enqueues a job.
+func (d *Dispatcher) Dispatch(job string) error {
+	return d.queue.Enqueue(job)
+}
@@ -0,0 +1,40 @@
+// Package occurrences is a test fixture for per-site effect
+// occurrences and the conditions that guard them.
+package occurrences
+
+import (
+	"errors"
+	"strconv"
+)
+
+// Parse returns an error only for empty or malformed input.
+func Parse(s string) (int, error) {
+	if s == "" {
+		return 0, errors.New("empty")
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Record writes to hits on two paths.
+func Record(hits map[string]bool, ok bool) {
+	if !ok {
+		hits["miss"] = true
+		return
+	}
+	hits["hit"] = true
+}
+
+// Kind classifies a code, recording unknown codes.
+func Kind(code int, unknown map[int]bool) string {
+	switch code {
+	case 1:
+		return "one"
+	default:
+		unknown[code] = true
+	}
+	return "other"
+}
@@ -8,6 +8,8 @@ import (
 	"log"
 	"log/slog"
 	"os"
+
+	"github.com/unbound-force/gaze/internal/analysis/testdata/src/p2effects/store"
 )
 
 // --- Goroutine Spawn ---
@@ -185,3 +187,71 @@ func BeginTx(db *sql.DB) (*sql.Tx, error) {
 func PureP2(x, y int) int {
 	return x + y
 }
+
+// --- Interface Interaction ---
+
+// Repository persists users.
+type Repository interface {
+	Save(name string) error
+	Find(name string) (string, error)
+}
+
+// Notifier announces events.
+type Notifier interface {
+	Notify(event string)
+}
+
+// SaveUser stores name through the injected repository.
+func SaveUser(repo Repository, name string) error {
+	return repo.Save(name)
+}
+
+// Store is an alias of an interface declared in another package.
+type Store = store.Store
+
+// PutValue stores value through an injected store whose parameter
+// type is spelled with the alias.
+func PutValue(s Store, key, value string) error {
+	return s.Put(key, value)
+}
+
+// UserService coordinates a repository and a notifier.
+type UserService struct {
+	repo     Repository
+	notifier Notifier
+}
+
+// Register saves a user and announces it.
+func (s *UserService) Register(name string) error {
+	if err := s.repo.Save(name); err != nil {
+		return err
+	}
+	s.notifier.Notify("registered " + name)
+	return nil
+}
+
+// defaultNotifier is wired up at program start.
+var defaultNotifier Notifier
+
+// Announce publishes event through the package-level notifier.
+func Announce(event string) {
+	defaultNotifier.Notify(event)
+}
+
+// ErrorMessage calls a method on a builtin error, which is not an
+// injected dependency.
+func ErrorMessage(err error) string {
+	return err.Error()
+}
+
+// LocalRepository calls a method on a locally constructed interface
+// value, which is not an injected dependency.
+func LocalRepository(name string) error {
+	var repo Repository = memRepo{}
+	return repo.Save(name)
+}
+
+type memRepo struct{}
+
+func (memRepo) Save(string) error           { return nil }
+func (memRepo) Find(string) (string, error) { return "", nil }
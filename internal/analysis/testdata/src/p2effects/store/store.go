@@ -0,0 +1,8 @@
+// Package store provides an interface that the p2effects fixture
+// re-exports through a type alias.
+package store
+
+// Store persists key-value pairs.
+type Store interface {
+	Put(key, value string) error
+}
@@ -0,0 +1,27 @@
+// Package ent stands in for code generated by entgo.io/ent.
+package ent
+
+import "context"
+
+// User is an entity.
+type User struct{ Name string }
+
+// UserCreate is the builder for creating a User.
+type UserCreate struct{ name string }
+
+// SetName sets the name field.
+func (uc *UserCreate) SetName(name string) *UserCreate {
+	uc.name = name
+	return uc
+}
+
+// Save creates the User in the database.
+func (uc *UserCreate) Save(ctx context.Context) (*User, error) {
+	return &User{Name: uc.name}, nil
+}
+
+// UserQuery is the builder for querying Users.
+type UserQuery struct{}
+
+// First returns the first User.
+func (uq *UserQuery) First(ctx context.Context) (*User, error) { return &User{}, nil }
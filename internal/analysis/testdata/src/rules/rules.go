@@ -0,0 +1,58 @@
+// Package rules is a test fixture for custom detection rules
+// configured in .gaze.yaml.
+package rules
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/unbound-force/gaze/internal/analysis/testdata/src/rules/ent"
+)
+
+// Producer stands in for a message broker client from a platform
+// library.
+type Producer struct{}
+
+// Send publishes a message.
+func (p *Producer) Send(topic string, msg []byte) error { return nil }
+
+// Record appends an entry to the audit trail.
+func Record(entry string) {}
+
+// PlaceOrder publishes an order event and audits it.
+func PlaceOrder(p *Producer, id string) error {
+	Record("order " + id)
+	return p.Send("orders", []byte(id))
+}
+
+// Fetch issues an HTTP request through the given client.
+func Fetch(c *http.Client, url string) error {
+	resp, err := c.Get(url)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Unmatched calls nothing covered by a rule.
+func Unmatched(id string) string {
+	return "order " + id
+}
+
+// AuditLogin logs through a package-level slog function, which the
+// built-in detector covers, and through a *slog.Logger method, which
+// it does not.
+func AuditLogin(logger *slog.Logger, user string) {
+	slog.Info("login", "user", user)
+	logger.Info("login", "user", user)
+	_ = logger.With("user", user)
+}
+
+// CreateUser saves a user through the generated ent builder.
+func CreateUser(ctx context.Context, uc *ent.UserCreate, uq *ent.UserQuery) (*ent.User, error) {
+	if _, err := uq.First(ctx); err == nil {
+		return nil, nil
+	}
+	return uc.SetName("gopher").Save(ctx)
+}
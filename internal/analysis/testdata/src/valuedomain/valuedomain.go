@@ -0,0 +1,67 @@
+// Package valuedomain is a test fixture for value-domain
+// enumeration of constant return values.
+package valuedomain
+
+import "errors"
+
+// Label is an enumeration with a duplicate alias.
+type Label int
+
+const (
+	Contractual Label = iota
+	Incidental
+	Ambiguous
+
+	// Unknown aliases Ambiguous; the first declared name wins.
+	Unknown = Ambiguous
+)
+
+// Classify returns one of three labels.
+func Classify(score int) Label {
+	if score >= 80 {
+		return Contractual
+	}
+	if score < 50 {
+		return Incidental
+	}
+	return Ambiguous
+}
+
+// Phase selects a label through a local, which SSA turns into a phi.
+func Phase(done bool) Label {
+	l := Incidental
+	if done {
+		l = Contractual
+	}
+	return l
+}
+
+// IsEven returns a bool computed from its argument; its values
+// cannot be enumerated.
+func IsEven(n int) bool {
+	return n%2 == 0
+}
+
+// Valid returns literal booleans.
+func Valid(s string) bool {
+	if s == "" {
+		return false
+	}
+	return true
+}
+
+// State returns string literals alongside an error.
+func State(open bool) (string, error) {
+	if open {
+		return "open", nil
+	}
+	if !open {
+		return "closed", nil
+	}
+	return "", errors.New("unreachable")
+}
+
+// Always returns a single constant.
+func Always() Label {
+	return Contractual
+}
@@ -0,0 +1,142 @@
+// Package analysis provides the core side effect detection engine.
+package analysis
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// maxDomainValues bounds the domain recorded for a return position.
+// A function returning more distinct constants is treated as
+// computing its result rather than choosing from an enumeration.
+const maxDomainValues = 16
+
+// AnalyzeValueDomains enumerates the constants that can reach each
+// ReturnValue position of fd and records them as the effect's
+// Domain. A position qualifies only when every return statement
+// yields a constant there, directly or through SSA phi nodes, so
+// the domain is exact: Classify returning Contractual, Incidental,
+// or Ambiguous gets a domain of those three names.
+//
+// effects are the function's effects in detection order; the
+// ReturnValue and ErrorReturn entries are matched to result
+// positions by their order. Positions with fewer than two values
+// are left alone, as any assertion on them covers the whole domain.
+func AnalyzeValueDomains(
+	ssaPkg *ssa.Package,
+	fd *ast.FuncDecl,
+	fnObj *types.Func,
+	effects []taxonomy.SideEffect,
+) {
+	if ssaPkg == nil || fd.Body == nil {
+		return
+	}
+	fn := findSSAFunction(ssaPkg, fnObj, fd)
+	if fn == nil {
+		return
+	}
+
+	returns := returnInstrs(fn)
+	if len(returns) == 0 {
+		return
+	}
+
+	pos := 0
+	for i := range effects {
+		switch effects[i].Type {
+		case taxonomy.ReturnValue:
+			effects[i].Domain = returnDomain(returns, pos)
+		case taxonomy.ErrorReturn:
+		default:
+			continue
+		}
+		pos++
+	}
+}
+
+// returnDomain returns the constants reaching result position pos of
+// every return, or nil if any of them is not a constant.
+func returnDomain(returns []*ssa.Return, pos int) []taxonomy.DomainValue {
+	var consts []*ssa.Const
+	seen := make(map[string]bool)
+	visited := make(map[*ssa.Phi]bool)
+
+	var collect func(v ssa.Value) bool
+	collect = func(v ssa.Value) bool {
+		switch v := v.(type) {
+		case *ssa.Const:
+			if v.Value == nil {
+				return false
+			}
+			if _, ok := v.Type().Underlying().(*types.Basic); !ok {
+				return false
+			}
+			if key := v.Value.ExactString(); !seen[key] {
+				seen[key] = true
+				consts = append(consts, v)
+			}
+			return len(consts) <= maxDomainValues
+		case *ssa.Phi:
+			if visited[v] {
+				return true
+			}
+			visited[v] = true
+			for _, edge := range v.Edges {
+				if !collect(edge) {
+					return false
+				}
+			}
+			return true
+		}
+		return false
+	}
+
+	for _, ret := range returns {
+		if pos >= len(ret.Results) || !collect(ret.Results[pos]) {
+			return nil
+		}
+	}
+	if len(consts) < 2 {
+		return nil
+	}
+
+	domain := make([]taxonomy.DomainValue, len(consts))
+	for i, c := range consts {
+		domain[i] = taxonomy.DomainValue{
+			Value: c.Value.ExactString(),
+			Name:  constName(c.Type(), c.Value),
+		}
+	}
+	return domain
+}
+
+// constName returns the name of the constant of type t with value v
+// declared in t's package, preferring the first declared when
+// several share the value. Values of unnamed types have no name.
+func constName(t types.Type, v constant.Value) string {
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return ""
+	}
+	scope := named.Obj().Pkg().Scope()
+	var best *types.Const
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) || !constant.Compare(c.Val(), token.EQL, v) {
+			continue
+		}
+		if best == nil || c.Pos() < best.Pos() {
+			best = c
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Name()
+}
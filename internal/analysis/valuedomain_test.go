@@ -0,0 +1,52 @@
+package analysis_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestAnalyzeValueDomains verifies that return positions yielding
+// only constants carry their value domain, named after the declared
+// constants where possible.
+func TestAnalyzeValueDomains(t *testing.T) {
+	pkg := loadTestPackage(t, "valuedomain")
+
+	tests := []struct {
+		function string
+		domain   []string
+	}{
+		{"Classify", []string{"Contractual", "Incidental", "Ambiguous"}},
+		{"Phase", []string{"Incidental", "Contractual"}},
+		{"IsEven", nil},
+		{"Valid", []string{"false", "true"}},
+		{"State", []string{`"open"`, `"closed"`, `""`}},
+		{"Always", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.function, func(t *testing.T) {
+			fd := analysis.FindFuncDecl(pkg, tt.function)
+			if fd == nil {
+				t.Fatalf("%s not found in valuedomain package", tt.function)
+			}
+			result := analysis.AnalyzeFunctionWithSSA(pkg, fd, nil)
+
+			var domain []string
+			for _, e := range result.SideEffects {
+				if len(e.Domain) > 0 && e.Type != taxonomy.ReturnValue {
+					t.Errorf("%s effect has a domain, want only ReturnValue", e.Type)
+				}
+				if e.Type == taxonomy.ReturnValue {
+					for _, v := range e.Domain {
+						domain = append(domain, v.String())
+					}
+				}
+			}
+			if !slices.Equal(domain, tt.domain) {
+				t.Errorf("domain = %v, want %v", domain, tt.domain)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ package classify
 import (
 	"go/ast"
 	"go/types"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 
@@ -78,6 +79,14 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 			if se.Type == taxonomy.SentinelError && se.Target != "" {
 				namingName = se.Target
 			}
+			// For interface interactions whose enclosing function
+			// name is neutral, the called method (Save, Publish,
+			// Debug) says whether the interaction is the point of
+			// the call or plumbing such as logging.
+			if se.Type == taxonomy.InterfaceInteraction && se.Target != "" &&
+				AnalyzeNamingSignal(funcName, se.Type).Source == "" {
+				namingName = se.Target[strings.LastIndex(se.Target, ".")+1:]
+			}
 
 			signals := classifySideEffect(
 				funcName, funcDecl, funcObj,
@@ -103,7 +112,7 @@ func Classify(results []taxonomy.AnalysisResult, opts Options) []taxonomy.Analys
 	return results
 }
 
-// classifySideEffect runs all five mechanical signal analyzers
+// classifySideEffect runs all six mechanical signal analyzers
 // for a single side effect and returns the collected signals.
 // ifaces is the pre-computed interface list from collectInterfaces.
 // namingName is the name used for naming-convention analysis; for
@@ -146,6 +155,11 @@ func classifySideEffect(
 		signals = append(signals, s)
 	}
 
+	// 6. Effect type default.
+	if s := AnalyzeEffectTypeSignal(effectType); s.Source != "" {
+		signals = append(signals, s)
+	}
+
 	return signals
 }
 
@@ -90,6 +90,9 @@ func TestNamingSignal_ContractualPrefixes(t *testing.T) {
 		{"FetchConfig returns", "FetchConfig", taxonomy.ReturnValue, 10},
 		{"DeleteItem error", "DeleteItem", taxonomy.ErrorReturn, 10},
 		{"HandleRequest any", "HandleRequest", taxonomy.ReceiverMutation, 10},
+		{"SaveUser interaction", "SaveUser", taxonomy.InterfaceInteraction, 10},
+		{"PublishEvent interaction", "PublishEvent", taxonomy.InterfaceInteraction, 10},
+		{"Notify interaction", "Notify", taxonomy.InterfaceInteraction, 10},
 	}
 
 	for _, tt := range tests {
@@ -138,6 +141,42 @@ func TestNamingSignal_NoMatch(t *testing.T) {
 	}
 }
 
+// TestEffectTypeSignal tests the default weights of effect types:
+// outbound requests lean contractual, metric updates lean incidental,
+// and other types carry no default.
+func TestEffectTypeSignal(t *testing.T) {
+	tests := []struct {
+		effectType taxonomy.SideEffectType
+		wantSign   int
+	}{
+		{taxonomy.RespectsCancellation, 1},
+		{taxonomy.GRPCStatusCode, 1},
+		{taxonomy.HTTPStatusCode, 1},
+		{taxonomy.HTTPHeader, 1},
+		{taxonomy.ErrorWrap, 1},
+		{taxonomy.HTTPRequest, 1},
+		{taxonomy.RPCCall, 1},
+		{taxonomy.MessagePublish, 1},
+		{taxonomy.NetworkDial, 1},
+		{taxonomy.MetricEmit, -1},
+		{taxonomy.ReturnValue, 0},
+		{taxonomy.LogWrite, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.effectType), func(t *testing.T) {
+			s := classify.AnalyzeEffectTypeSignal(tt.effectType)
+			switch {
+			case tt.wantSign == 0 && s.Source != "":
+				t.Errorf("expected no signal, got source=%q weight=%d", s.Source, s.Weight)
+			case tt.wantSign > 0 && s.Weight <= 0, tt.wantSign < 0 && s.Weight >= 0:
+				t.Errorf("weight = %d, want sign %d", s.Weight, tt.wantSign)
+			case tt.wantSign != 0 && s.Source != "effect_type":
+				t.Errorf("source = %q, want %q", s.Source, "effect_type")
+			}
+		})
+	}
+}
+
 // TestScoreComputation_BaseConfidence tests that zero signals
 // produce a score of 50 (the base confidence).
 func TestScoreComputation_BaseConfidence(t *testing.T) {
@@ -458,6 +497,59 @@ func TestClassify_ContractsPackage(t *testing.T) {
 	}
 }
 
+// TestClassify_InterfaceInteractions tests that interactions with
+// injected dependencies are classified by the called method when the
+// enclosing function's name is neutral: persisting and publishing
+// through a port is contractual, tracing through one is not.
+func TestClassify_InterfaceInteractions(t *testing.T) {
+	allPkgs := loadTestPackages(t)
+	contractsPkg := findPackage(allPkgs, "contracts")
+	if contractsPkg == nil {
+		t.Fatal("contracts package not found")
+	}
+
+	results, err := analysis.Analyze(contractsPkg, analysis.Options{})
+	if err != nil {
+		t.Fatalf("analysis failed: %v", err)
+	}
+	classified := classify.Classify(results, classify.Options{
+		Config:         config.DefaultConfig(),
+		ModulePackages: allPkgs,
+		TargetPkg:      contractsPkg,
+		Verbose:        true,
+	})
+
+	confidence := make(map[string]int)
+	labels := make(map[string]taxonomy.ClassificationLabel)
+	for _, result := range classified {
+		if result.Target.Function != "PlaceOrder" {
+			continue
+		}
+		for _, se := range result.SideEffects {
+			if se.Type != taxonomy.InterfaceInteraction || se.Classification == nil {
+				continue
+			}
+			confidence[se.Target] = se.Classification.Confidence
+			labels[se.Target] = se.Classification.Label
+		}
+	}
+
+	for _, target := range []string{"Store.Save", "EventPublisher.Publish", "Tracer.Debugf"} {
+		if _, ok := confidence[target]; !ok {
+			t.Fatalf("PlaceOrder: no InterfaceInteraction on %s, got %v", target, confidence)
+		}
+	}
+	for _, target := range []string{"Store.Save", "EventPublisher.Publish"} {
+		if confidence[target] <= confidence["Tracer.Debugf"] {
+			t.Errorf("%s confidence %d, want above Tracer.Debugf (%d)",
+				target, confidence[target], confidence["Tracer.Debugf"])
+		}
+	}
+	if labels["Tracer.Debugf"] == taxonomy.Contractual {
+		t.Errorf("Tracer.Debugf: label = %s, want not contractual", labels["Tracer.Debugf"])
+	}
+}
+
 // TestClassify_IncidentalPackage tests that incidental effects
 // are classified with low confidence.
 func TestClassify_IncidentalPackage(t *testing.T) {
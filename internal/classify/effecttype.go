@@ -0,0 +1,42 @@
+// Package classify implements the contractual classification engine.
+package classify
+
+import "github.com/unbound-force/gaze/internal/taxonomy"
+
+// effectTypeDefaults are the prior weights of side effect types whose
+// nature makes them usually contractual or usually incidental,
+// regardless of the function that produces them. A request to another
+// service is usually what the caller asked for; a metric update almost
+// never is.
+var effectTypeDefaults = map[taxonomy.SideEffectType]struct {
+	weight    int
+	reasoning string
+}{
+	// Accepting a context is a promise to stop when it is cancelled;
+	// like a sentinel's Err prefix, the signature alone makes the
+	// behavior contractual (base 50 + 30 = 80).
+	taxonomy.RespectsCancellation: {30, "a function that accepts a context.Context promises to stop when it is cancelled"},
+	taxonomy.GRPCStatusCode:       {20, "the gRPC status codes a service method returns are its error contract"},
+	taxonomy.HTTPStatusCode:       {20, "the status codes a handler responds with are its HTTP contract"},
+	taxonomy.HTTPHeader:           {10, "response headers are usually part of a handler's HTTP contract"},
+	taxonomy.ErrorWrap:            {10, "whether returned errors wrap their cause is part of the error contract"},
+	taxonomy.HTTPRequest:          {10, "outbound HTTP requests are usually part of the function's contract"},
+	taxonomy.RPCCall:              {10, "outbound RPCs are usually part of the function's contract"},
+	taxonomy.MessagePublish:       {10, "published messages are usually part of the function's contract"},
+	taxonomy.NetworkDial:          {5, "opened connections are often part of the function's contract"},
+	taxonomy.MetricEmit:           {-15, "metric updates are usually incidental instrumentation"},
+}
+
+// AnalyzeEffectTypeSignal returns the default signal for the side
+// effect type, or an empty signal for types without a default.
+func AnalyzeEffectTypeSignal(effectType taxonomy.SideEffectType) taxonomy.Signal {
+	d, ok := effectTypeDefaults[effectType]
+	if !ok {
+		return taxonomy.Signal{}
+	}
+	return taxonomy.Signal{
+		Source:    "effect_type",
+		Weight:    d.weight,
+		Reasoning: d.reasoning,
+	}
+}
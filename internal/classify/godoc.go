@@ -14,15 +14,20 @@ var contractualKeywords = []struct {
 	keyword    string
 	impliesFor []taxonomy.SideEffectType
 }{
-	{"returns", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"writes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
+	{"returns", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn, taxonomy.ErrorWrap}},
+	{"wraps", []taxonomy.SideEffectType{taxonomy.ErrorWrap}},
+	{"writes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.InterfaceInteraction}},
 	{"modifies", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"updates", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
+	{"updates", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.InterfaceInteraction}},
 	{"sets", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"persists", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"stores", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"deletes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation}},
-	{"removes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation}},
+	{"persists", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.InterfaceInteraction}},
+	{"stores", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.InterfaceInteraction}},
+	{"deletes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.InterfaceInteraction}},
+	{"removes", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.InterfaceInteraction}},
+	{"saves", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"publishes", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"sends", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"notifies", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
 }
 
 // incidentalKeywords are words in godoc that suggest incidental
@@ -155,14 +155,36 @@ func TestAnalyzeGodocSignal_ContractualKeywords(t *testing.T) {
 			wantWeight:  0,
 			wantNonZero: false,
 		},
-		// "sends" is NOT in the contractual keywords list.
+		// "sends" declares an interaction with a dependency, not a
+		// mutation.
 		{
-			name:        "sends + ReceiverMutation (not a keyword)",
+			name:        "sends + InterfaceInteraction",
+			doc:         "Dispatch sends the event.",
+			effectType:  taxonomy.InterfaceInteraction,
+			wantWeight:  15,
+			wantNonZero: true,
+		},
+		{
+			name:        "sends + ReceiverMutation (no match)",
 			doc:         "Dispatch sends the event.",
 			effectType:  taxonomy.ReceiverMutation,
 			wantWeight:  0,
 			wantNonZero: false,
 		},
+		{
+			name:        "wraps + ErrorWrap",
+			doc:         "Open wraps the underlying os.PathError.",
+			effectType:  taxonomy.ErrorWrap,
+			wantWeight:  15,
+			wantNonZero: true,
+		},
+		{
+			name:        "persists + InterfaceInteraction",
+			doc:         "Register persists the user.",
+			effectType:  taxonomy.InterfaceInteraction,
+			wantWeight:  15,
+			wantNonZero: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -17,12 +17,20 @@ var contractualPrefixes = []struct {
 	{"Fetch", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
 	{"Load", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
 	{"Read", []taxonomy.SideEffectType{taxonomy.ReturnValue, taxonomy.ErrorReturn}},
-	{"Save", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
-	{"Write", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
-	{"Update", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn}},
+	{"Save", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn, taxonomy.InterfaceInteraction}},
+	{"Write", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn, taxonomy.InterfaceInteraction}},
+	{"Update", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation, taxonomy.ErrorReturn, taxonomy.InterfaceInteraction}},
 	{"Set", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.PointerArgMutation}},
-	{"Delete", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn}},
-	{"Remove", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn}},
+	{"Delete", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn, taxonomy.InterfaceInteraction}},
+	{"Remove", []taxonomy.SideEffectType{taxonomy.ReceiverMutation, taxonomy.ErrorReturn, taxonomy.InterfaceInteraction}},
+	// Orchestration functions in hexagonal code exist to drive their
+	// injected dependencies (ports): the calls are the contract.
+	{"Publish", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"Send", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"Notify", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"Register", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"Create", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
+	{"Store", []taxonomy.SideEffectType{taxonomy.InterfaceInteraction}},
 	{"Handle", nil},  // nil means all side effects
 	{"Process", nil}, // nil means all side effects
 	// Computational / analytical functions: return value is the primary
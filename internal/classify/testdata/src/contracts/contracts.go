@@ -225,6 +225,34 @@ func ApplyTransform(data []byte, key string) (ExportedResult, error) {
 	return ExportedResult{Value: string(data) + key, OK: true}, nil
 }
 
+// ---- Injected dependencies (interface interactions) -----------------------
+
+// EventPublisher is an outbound port for domain events.
+type EventPublisher interface {
+	Publish(event string) error
+}
+
+// Tracer records diagnostics for operators.
+type Tracer interface {
+	Debugf(format string, args ...any)
+}
+
+// OrderService places orders through its injected ports.
+type OrderService struct {
+	Orders Store
+	Events EventPublisher
+	Trace  Tracer
+}
+
+// PlaceOrder accepts an order.
+func (s *OrderService) PlaceOrder(order []byte) error {
+	s.Trace.Debugf("placing order of %d bytes", len(order))
+	if err := s.Orders.Save(order); err != nil {
+		return err
+	}
+	return s.Events.Publish("OrderPlaced")
+}
+
 // ---- Sentinel errors (contractual by naming + type) ----------------------
 
 // ErrNotFound is a sentinel error. Its existence as a named error
@@ -5,9 +5,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 // Thresholds defines the confidence score boundaries for
@@ -49,10 +52,184 @@ type ClassificationConfig struct {
 	DocScan DocScan `yaml:"doc_scan"`
 }
 
+// EffectRule maps a function or method outside the built-in detectors
+// to the side effect its calls produce.
+type EffectRule struct {
+	// Function is the fully qualified function or method, e.g.
+	// "github.com/acme/kafka.(*Producer).Send" or
+	// "github.com/acme/audit.Record". A "*" matches any run of
+	// characters: within the package path it may span path elements
+	// ("*/ent"), within the function or method name it stays within
+	// one name ("(*SugaredLogger).Info*").
+	Function string `yaml:"function"`
+
+	// Effect is a built-in SideEffectType (e.g. "DatabaseWrite") or
+	// the name of a custom type.
+	Effect string `yaml:"effect"`
+
+	// Tier is the priority tier ("P0" through "P4") of a custom
+	// type. It must be omitted for built-in types, whose tier is
+	// fixed by the taxonomy.
+	Tier string `yaml:"tier"`
+}
+
+// DetectionConfig groups side effect detection settings.
+type DetectionConfig struct {
+	// Packs selects built-in rule packs by name (e.g. "pgx", "zap",
+	// "redis"). See RulePackNames for the available packs.
+	Packs []string `yaml:"packs"`
+
+	// Rules are the custom effect rules, applied in addition to
+	// the built-in detectors and the selected packs.
+	Rules []EffectRule `yaml:"rules"`
+}
+
+// EffectiveRules returns the rules of the selected packs followed by
+// the custom rules, so that a custom rule for the same function takes
+// precedence over a pack's. Unknown packs are skipped; Load rejects
+// them.
+func (d DetectionConfig) EffectiveRules() []EffectRule {
+	var rules []EffectRule
+	for _, name := range d.Packs {
+		pack, err := RulePack(name)
+		if err != nil {
+			continue
+		}
+		rules = append(rules, pack...)
+	}
+	return append(rules, d.Rules...)
+}
+
+// CustomTypes returns the custom effect types declared by the
+// effective rules, in order of first appearance, with their tiers.
+func (d DetectionConfig) CustomTypes() []taxonomy.CustomType {
+	var types []taxonomy.CustomType
+	seen := make(map[string]bool)
+	for _, r := range d.EffectiveRules() {
+		if taxonomy.IsBuiltin(taxonomy.SideEffectType(r.Effect)) || seen[r.Effect] {
+			continue
+		}
+		seen[r.Effect] = true
+		types = append(types, taxonomy.CustomType{
+			Name: taxonomy.SideEffectType(r.Effect),
+			Tier: taxonomy.Tier(r.Tier),
+		})
+	}
+	return types
+}
+
+// validate checks that every selected pack exists and that every
+// effective rule names a function, an effect, and for custom types a
+// valid tier used consistently across rules and packs.
+func (d DetectionConfig) validate() error {
+	type labeled struct {
+		label string
+		rule  EffectRule
+	}
+	var rules []labeled
+	for _, name := range d.Packs {
+		pack, err := RulePack(name)
+		if err != nil {
+			return fmt.Errorf("detection.packs: %w", err)
+		}
+		for _, r := range pack {
+			rules = append(rules, labeled{fmt.Sprintf("rule pack %s", name), r})
+		}
+	}
+	for i, r := range d.Rules {
+		rules = append(rules, labeled{fmt.Sprintf("detection.rules[%d]", i), r})
+	}
+
+	tiers := make(map[string]string)
+	for _, lr := range rules {
+		label, r := lr.label, lr.rule
+		name := r.Function[strings.LastIndex(r.Function, "/")+1:]
+		if !strings.Contains(name, ".") {
+			return fmt.Errorf("%s: function %q must be fully qualified (e.g. example.com/pkg.(*Type).Method)", label, r.Function)
+		}
+		if r.Effect == "" {
+			return fmt.Errorf("%s (%s): effect is required", label, r.Function)
+		}
+		if taxonomy.IsBuiltin(taxonomy.SideEffectType(r.Effect)) {
+			if r.Tier != "" {
+				return fmt.Errorf("%s (%s): tier cannot be set for built-in effect %s", label, r.Function, r.Effect)
+			}
+			continue
+		}
+		switch taxonomy.Tier(r.Tier) {
+		case taxonomy.TierP0, taxonomy.TierP1, taxonomy.TierP2, taxonomy.TierP3, taxonomy.TierP4:
+		default:
+			return fmt.Errorf("%s (%s): custom effect %s needs a tier of P0-P4, got %q", label, r.Function, r.Effect, r.Tier)
+		}
+		if prev, ok := tiers[r.Effect]; ok && prev != r.Tier {
+			return fmt.Errorf("%s (%s): custom effect %s declared with tier %s and %s", label, r.Function, r.Effect, prev, r.Tier)
+		}
+		tiers[r.Effect] = r.Tier
+	}
+	return nil
+}
+
+// GoTestConfig configures the go test run that generates a coverage
+// profile when none is supplied.
+type GoTestConfig struct {
+	// Tags are the build tags passed to -tags.
+	Tags []string `yaml:"tags"`
+
+	// Race enables the race detector (-race).
+	Race bool `yaml:"race"`
+
+	// Timeout is passed to -timeout. Zero keeps go test's default.
+	Timeout time.Duration `yaml:"-"`
+
+	// TimeoutStr is the string representation for YAML parsing.
+	TimeoutStr string `yaml:"timeout"`
+
+	// Count is passed to -count (e.g. 1 to bypass the test cache).
+	// Zero keeps go test's default.
+	Count int `yaml:"count"`
+
+	// CoverPkg is passed to -coverpkg, e.g. "./..." to record the
+	// coverage that each package's tests give other packages.
+	CoverPkg string `yaml:"coverpkg"`
+
+	// Env holds environment variables set for the go test process,
+	// in addition to gaze's own environment.
+	Env map[string]string `yaml:"env"`
+
+	// Run is passed to -run to select the tests to execute.
+	Run string `yaml:"run"`
+}
+
+// validate checks the values that go test would reject only after
+// building every package.
+func (g GoTestConfig) validate() error {
+	if g.Count < 0 {
+		return fmt.Errorf("coverage.go_test.count must not be negative, got %d", g.Count)
+	}
+	for k := range g.Env {
+		if k == "" || strings.Contains(k, "=") {
+			return fmt.Errorf("coverage.go_test.env: invalid variable name %q", k)
+		}
+	}
+	return nil
+}
+
+// CoverageConfig groups settings for coverage generation.
+type CoverageConfig struct {
+	// GoTest configures the go test invocation.
+	GoTest GoTestConfig `yaml:"go_test"`
+}
+
 // GazeConfig is the top-level configuration loaded from .gaze.yaml.
 type GazeConfig struct {
 	// Classification holds classification-related settings.
 	Classification ClassificationConfig `yaml:"classification"`
+
+	// Detection holds side effect detection settings.
+	Detection DetectionConfig `yaml:"detection"`
+
+	// Coverage holds coverage generation settings.
+	Coverage CoverageConfig `yaml:"coverage"`
 }
 
 // DefaultConfig returns a GazeConfig with sensible defaults.
@@ -111,5 +288,21 @@ func Load(path string) (*GazeConfig, error) {
 		cfg.Classification.DocScan.Timeout = d
 	}
 
+	if cfg.Coverage.GoTest.TimeoutStr != "" {
+		d, err := time.ParseDuration(cfg.Coverage.GoTest.TimeoutStr)
+		if err != nil {
+			return nil, fmt.Errorf("parsing coverage.go_test.timeout %q: %w",
+				cfg.Coverage.GoTest.TimeoutStr, err)
+		}
+		cfg.Coverage.GoTest.Timeout = d
+	}
+
+	if err := cfg.Detection.validate(); err != nil {
+		return nil, fmt.Errorf("parsing config %q: %w", path, err)
+	}
+	if err := cfg.Coverage.GoTest.validate(); err != nil {
+		return nil, fmt.Errorf("parsing config %q: %w", path, err)
+	}
+
 	return cfg, nil
 }
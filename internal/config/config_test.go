@@ -1,7 +1,9 @@
 package config
 
 import (
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 )
@@ -133,3 +135,189 @@ func TestLoad_IncludeOverride(t *testing.T) {
 		t.Errorf("include[1] = %q, want %q", includes[1], "README.md")
 	}
 }
+
+func TestLoad_DetectionRules(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "detection-rules.yaml"))
+	if err != nil {
+		t.Fatalf("Load(detection-rules) error: %v", err)
+	}
+
+	rules := cfg.Detection.Rules
+	if len(rules) != 3 {
+		t.Fatalf("rule count = %d, want 3", len(rules))
+	}
+	if rules[0].Function != "github.com/acme/kafka.(*Producer).Send" ||
+		rules[0].Effect != "EventPublish" || rules[0].Tier != "P2" {
+		t.Errorf("rule[0] = %+v", rules[0])
+	}
+	if rules[2].Effect != "DatabaseWrite" || rules[2].Tier != "" {
+		t.Errorf("rule[2] = %+v", rules[2])
+	}
+
+	custom := cfg.Detection.CustomTypes()
+	if len(custom) != 1 {
+		t.Fatalf("custom types = %v, want only EventPublish", custom)
+	}
+	if custom[0].Name != "EventPublish" || custom[0].Tier != "P2" {
+		t.Errorf("custom type = %+v, want EventPublish at P2", custom[0])
+	}
+}
+
+func TestDetectionConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []EffectRule
+		wantErr string
+	}{
+		{"builtin without tier", []EffectRule{
+			{Function: "example.com/db.(*Conn).Exec", Effect: "DatabaseWrite"},
+		}, ""},
+		{"custom with tier", []EffectRule{
+			{Function: "example.com/mq.Publish", Effect: "EventPublish", Tier: "P1"},
+		}, ""},
+		{"unqualified function", []EffectRule{
+			{Function: "Publish", Effect: "EventPublish", Tier: "P1"},
+		}, "fully qualified"},
+		{"missing effect", []EffectRule{
+			{Function: "example.com/mq.Publish"},
+		}, "effect is required"},
+		{"custom without tier", []EffectRule{
+			{Function: "example.com/mq.Publish", Effect: "EventPublish"},
+		}, "needs a tier"},
+		{"builtin with tier", []EffectRule{
+			{Function: "example.com/db.(*Conn).Exec", Effect: "DatabaseWrite", Tier: "P0"},
+		}, "cannot be set for built-in"},
+		{"inconsistent custom tiers", []EffectRule{
+			{Function: "example.com/mq.Publish", Effect: "EventPublish", Tier: "P1"},
+			{Function: "example.com/mq.PublishBatch", Effect: "EventPublish", Tier: "P2"},
+		}, "declared with tier P1 and P2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := DetectionConfig{Rules: tt.rules}.validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("validate() error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("validate() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRulePackNames(t *testing.T) {
+	names := RulePackNames()
+	for _, want := range []string{
+		"aws-s3", "aws-sqs", "ent", "gorm", "logrus",
+		"pgx", "redis", "slog", "sqlx", "zap", "zerolog",
+	} {
+		found := false
+		for _, n := range names {
+			found = found || n == want
+		}
+		if !found {
+			t.Errorf("RulePackNames() = %v, missing %q", names, want)
+		}
+	}
+}
+
+// TestRulePacks_Valid verifies that every built-in pack parses, and
+// that all packs can be selected together without conflicting custom
+// type tiers.
+func TestRulePacks_Valid(t *testing.T) {
+	for _, name := range RulePackNames() {
+		rules, err := RulePack(name)
+		if err != nil {
+			t.Errorf("RulePack(%q) error: %v", name, err)
+			continue
+		}
+		if len(rules) == 0 {
+			t.Errorf("RulePack(%q) has no rules", name)
+		}
+	}
+	if err := (DetectionConfig{Packs: RulePackNames()}).validate(); err != nil {
+		t.Errorf("validate(all packs) error: %v", err)
+	}
+}
+
+func TestLoad_DetectionPacks(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "detection-packs.yaml"))
+	if err != nil {
+		t.Fatalf("Load(detection-packs) error: %v", err)
+	}
+
+	rules := cfg.Detection.EffectiveRules()
+	pgx, _ := RulePack("pgx")
+	s3, _ := RulePack("aws-s3")
+	if len(rules) != len(pgx)+len(s3)+2 {
+		t.Fatalf("effective rule count = %d, want %d", len(rules), len(pgx)+len(s3)+2)
+	}
+	// Custom rules come last so they take precedence over packs.
+	if last := rules[len(rules)-1]; last.Function != "github.com/acme/kafka.(*Producer).Send" {
+		t.Errorf("last effective rule = %+v, want the custom rule", last)
+	}
+
+	custom := cfg.Detection.CustomTypes()
+	if len(custom) != 1 || custom[0].Name != "ObjectStoreWrite" || custom[0].Tier != "P2" {
+		t.Errorf("custom types = %v, want [ObjectStoreWrite at P2]", custom)
+	}
+}
+
+func TestLoad_UnknownPack(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".gaze.yaml")
+	if err := os.WriteFile(path, []byte("detection:\n  packs: [kafka]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, err := Load(path)
+	if err == nil || !strings.Contains(err.Error(), `unknown rule pack "kafka"`) {
+		t.Errorf("Load() error = %v, want unknown rule pack", err)
+	}
+}
+
+func TestLoad_CoverageGoTest(t *testing.T) {
+	cfg, err := Load(filepath.Join("testdata", "coverage-go-test.yaml"))
+	if err != nil {
+		t.Fatalf("Load(coverage-go-test) error: %v", err)
+	}
+
+	g := cfg.Coverage.GoTest
+	if strings.Join(g.Tags, ",") != "integration,sqlite" {
+		t.Errorf("tags = %v, want [integration sqlite]", g.Tags)
+	}
+	if !g.Race || g.Count != 1 || g.CoverPkg != "./..." || g.Run != "^TestStore" {
+		t.Errorf("go_test = %+v", g)
+	}
+	if g.Timeout != 5*time.Minute {
+		t.Errorf("timeout = %v, want 5m", g.Timeout)
+	}
+	if g.Env["DATABASE_URL"] != "postgres://localhost/test" {
+		t.Errorf("env = %v, want DATABASE_URL set", g.Env)
+	}
+}
+
+func TestLoad_InvalidCoverageGoTest(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"bad timeout", "coverage:\n  go_test:\n    timeout: soon\n", "coverage.go_test.timeout"},
+		{"negative count", "coverage:\n  go_test:\n    count: -1\n", "count must not be negative"},
+		{"bad env name", "coverage:\n  go_test:\n    env:\n      \"A=B\": x\n", "invalid variable name"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".gaze.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
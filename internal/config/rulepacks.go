@@ -0,0 +1,53 @@
+package config
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// rulePacks holds the built-in detection rule packs, one YAML file
+// per pack, shipped in the binary.
+//
+//go:embed rulepacks
+var rulePacks embed.FS
+
+// rulePackFile is the YAML layout of a rule pack.
+type rulePackFile struct {
+	Rules []EffectRule `yaml:"rules"`
+}
+
+// RulePackNames returns the names of the built-in rule packs, sorted.
+func RulePackNames() []string {
+	entries, err := fs.ReadDir(rulePacks, "rulepacks")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".yaml"); ok && !e.IsDir() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RulePack returns the rules of the built-in rule pack with the given
+// name.
+func RulePack(name string) ([]EffectRule, error) {
+	data, err := rulePacks.ReadFile("rulepacks/" + name + ".yaml")
+	if err != nil {
+		return nil, fmt.Errorf("unknown rule pack %q (available: %s)",
+			name, strings.Join(RulePackNames(), ", "))
+	}
+	var pack rulePackFile
+	if err := yaml.Unmarshal(data, &pack); err != nil {
+		return nil, fmt.Errorf("parsing rule pack %q: %w", name, err)
+	}
+	return pack.Rules, nil
+}
@@ -2,24 +2,38 @@
 package crap
 
 import (
-	"bufio"
+	"bytes"
 	"fmt"
+	"go/ast"
+	"go/types"
 	"io"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 
-	"github.com/fzipp/gocyclo"
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/loader"
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 // Options configures CRAP analysis.
 type Options struct {
-	// CoverProfile is the path to a coverage profile file.
-	// If empty, Gaze will generate one automatically.
-	CoverProfile string
+	// CoverProfiles are the paths to coverage profile files, merged
+	// into one coverage view. If empty and CoverDataDirs is empty,
+	// Gaze will generate a profile automatically.
+	CoverProfiles []string
+
+	// CoverDataDirs are directories of binary coverage data, as
+	// written to GOCOVERDIR by binaries built with -cover. They are
+	// converted with go tool covdata and merged with CoverProfiles.
+	CoverDataDirs []string
 
 	// CRAPThreshold is the threshold for flagging a function as
 	// "crappy". Default: 15.
@@ -29,20 +43,29 @@ type Options struct {
 	// Used only when contract coverage is available.
 	GazeCRAPThreshold float64
 
+	// Complexity selects the complexity metric of the CRAP formula.
+	// Default: Cyclomatic.
+	Complexity ComplexityMetric
+
 	// IgnoreGenerated excludes functions in files with
 	// "// Code generated" headers. Default: true.
 	IgnoreGenerated bool
 
-	// Stderr receives warnings about files that could not be parsed
-	// during coverage analysis. If nil, warnings are suppressed.
+	// Stderr receives warnings emitted during analysis. If nil,
+	// warnings are suppressed.
 	Stderr io.Writer
 
+	// GoTest configures the go test run that generates a coverage
+	// profile when none is provided.
+	GoTest config.GoTestConfig
+
 	// ContractCoverageFunc is an optional function that returns the
-	// contract coverage percentage (0-100) for a given function.
+	// contract coverage percentage (0-100) for the function with the
+	// given FunctionID.
 	// When provided, GazeCRAP scores, contract coverage, and
 	// quadrant classifications are computed for each function.
 	// If nil, GazeCRAP fields remain unavailable (FR-015).
-	ContractCoverageFunc func(pkg, function string) (float64, bool)
+	ContractCoverageFunc func(id taxonomy.FunctionID) (float64, bool)
 }
 
 // DefaultOptions returns options with sensible defaults.
@@ -50,6 +73,7 @@ func DefaultOptions() Options {
 	return Options{
 		CRAPThreshold:     15,
 		GazeCRAPThreshold: 15,
+		Complexity:        Cyclomatic,
 		IgnoreGenerated:   true,
 	}
 }
@@ -62,116 +86,134 @@ func Analyze(patterns []string, moduleDir string, opts Options) (*Report, error)
 	if opts.CRAPThreshold <= 0 {
 		opts.CRAPThreshold = 15
 	}
+	metric, err := ParseComplexityMetric(string(opts.Complexity))
+	if err != nil {
+		return nil, err
+	}
+	opts.Complexity = metric
+	if abs, err := filepath.Abs(moduleDir); err == nil {
+		moduleDir = abs
+	}
 
-	// Step 1: Generate coverage profile if not provided.
-	coverProfile := opts.CoverProfile
-	if coverProfile == "" {
-		var err error
-		coverProfile, err = generateCoverProfile(moduleDir, patterns)
+	// Step 1: Validate the coverage inputs, converting binary
+	// coverage data, or generate a profile if none was provided.
+	coverProfiles := make([]string, 0, len(opts.CoverProfiles)+1)
+	for _, path := range opts.CoverProfiles {
+		path = filepath.Clean(path)
+		info, err := os.Stat(path)
 		if err != nil {
-			return nil, fmt.Errorf("generating coverage: %w", err)
+			return nil, fmt.Errorf("cover profile %q: %w", path, err)
 		}
-		defer func() { _ = os.Remove(coverProfile) }()
-	} else {
-		// Validate user-supplied cover profile path.
-		coverProfile = filepath.Clean(coverProfile)
-		info, err := os.Stat(coverProfile)
+		if info.IsDir() {
+			return nil, fmt.Errorf("cover profile %q is a directory, not a file", path)
+		}
+		coverProfiles = append(coverProfiles, path)
+	}
+	for _, dir := range opts.CoverDataDirs {
+		// go tool covdata takes its input directories as a
+		// comma-separated list, so a comma in a path cannot be passed.
+		if strings.Contains(dir, ",") {
+			return nil, fmt.Errorf("coverage data %q: directory paths containing a comma are not supported", dir)
+		}
+		info, err := os.Stat(dir)
 		if err != nil {
-			return nil, fmt.Errorf("cover profile %q: %w", coverProfile, err)
+			return nil, fmt.Errorf("coverage data %q: %w", dir, err)
 		}
-		if info.IsDir() {
-			return nil, fmt.Errorf("cover profile %q is a directory, not a file", coverProfile)
+		if !info.IsDir() {
+			return nil, fmt.Errorf("coverage data %q is not a directory", dir)
 		}
 	}
+	if len(opts.CoverDataDirs) > 0 {
+		path, err := convertCoverData(moduleDir, opts.CoverDataDirs)
+		if err != nil {
+			return nil, fmt.Errorf("converting coverage data: %w", err)
+		}
+		defer func() { _ = os.Remove(path) }()
+		coverProfiles = append(coverProfiles, path)
+	}
+	var failures []TestFailure
+	if len(coverProfiles) == 0 {
+		path, failed, err := generateCoverProfile(moduleDir, patterns, opts.GoTest)
+		if err != nil {
+			return nil, fmt.Errorf("generating coverage: %w", err)
+		}
+		defer func() { _ = os.Remove(path) }()
+		coverProfiles = append(coverProfiles, path)
+		failures = failed
+	}
+	if len(failures) > 0 && opts.Stderr != nil {
+		_, _ = fmt.Fprintf(opts.Stderr,
+			"warning: tests failed in %d package(s); their functions are not scored\n", len(failures))
+	}
 
-	// Step 2: Compute cyclomatic complexity for all functions.
-	absPaths, err := resolvePatterns(patterns, moduleDir)
+	// Step 2: Load the packages with the analysis loader, so that
+	// build constraints and function identities match those of the
+	// analyze and quality commands.
+	mod, err := loader.LoadPatterns(moduleDir, patterns...)
 	if err != nil {
-		return nil, fmt.Errorf("resolving patterns: %w", err)
+		return nil, fmt.Errorf("loading packages: %w", err)
+	}
+	if opts.Stderr != nil {
+		for _, pkg := range mod.Failed {
+			_, _ = fmt.Fprintf(opts.Stderr,
+				"warning: package %s has errors; its functions are not scored: %v\n",
+				pkg.PkgPath, pkg.Errors[0])
+		}
 	}
 
-	complexityStats := gocyclo.Analyze(absPaths, testFileRegexp)
-
-	// Step 3: Parse coverage profile for per-function coverage.
-	funcCoverages, err := ParseCoverProfile(coverProfile, moduleDir, opts.Stderr)
+	// Step 3: Merge the coverage profiles into per-file blocks.
+	coverage, err := ParseLineCoverage(coverProfiles, moduleDir)
 	if err != nil {
 		return nil, fmt.Errorf("parsing coverage profile: %w", err)
 	}
 
-	// Step 4: Build coverage lookup map (file:line → coverage).
-	coverMap := buildCoverMap(funcCoverages)
-
-	// Step 5: Join complexity with coverage and compute CRAP.
-	// Cache generated-file checks to avoid re-reading files.
-	generatedCache := make(map[string]bool)
-
+	// Step 4: Score every function declared in the loaded syntax,
+	// except in packages whose tests failed: their coverage stops
+	// at the failure.
+	failed := make(map[string]bool, len(failures))
+	for _, f := range failures {
+		failed[f.Package] = true
+	}
 	var scores []Score
-	for _, stat := range complexityStats {
-		// Skip test files (already excluded by ignore pattern but
-		// belt-and-suspenders).
-		if strings.HasSuffix(stat.Pos.Filename, "_test.go") {
+	for _, pkg := range mod.Packages {
+		if failed[pkg.PkgPath] {
 			continue
 		}
-
-		// Skip generated files when configured.
-		if opts.IgnoreGenerated {
-			gen, ok := generatedCache[stat.Pos.Filename]
-			if !ok {
-				gen = isGeneratedFile(stat.Pos.Filename)
-				generatedCache[stat.Pos.Filename] = gen
-			}
-			if gen {
+		for _, file := range pkg.Syntax {
+			if opts.IgnoreGenerated && ast.IsGenerated(file) {
 				continue
 			}
-		}
-
-		covPct := lookupCoverage(stat, coverMap)
-		crapScore := Formula(stat.Complexity, covPct)
-
-		score := Score{
-			Package:      stat.PkgName,
-			Function:     stat.FuncName,
-			File:         stat.Pos.Filename,
-			Line:         stat.Pos.Line,
-			Complexity:   stat.Complexity,
-			LineCoverage: covPct,
-			CRAP:         crapScore,
-		}
-
-		// Compute GazeCRAP if contract coverage is available.
-		if opts.ContractCoverageFunc != nil {
-			ccPct, ok := opts.ContractCoverageFunc(stat.PkgName, stat.FuncName)
-			if ok {
-				gazeCRAP := Formula(stat.Complexity, ccPct)
-				quadrant := ClassifyQuadrant(
-					crapScore, gazeCRAP,
-					opts.CRAPThreshold, opts.GazeCRAPThreshold,
-				)
-				score.ContractCoverage = &ccPct
-				score.GazeCRAP = &gazeCRAP
-				score.Quadrant = &quadrant
+			for _, decl := range file.Decls {
+				fd, ok := decl.(*ast.FuncDecl)
+				if !ok || fd.Body == nil {
+					continue
+				}
+				scores = append(scores, scoreFunc(pkg, fd, coverage, opts))
 			}
 		}
-
-		scores = append(scores, score)
 	}
 
-	// Step 6: Build summary.
+	// Step 5: Build summary.
 	summary := buildSummary(scores, opts)
 
 	return &Report{
-		Scores:  scores,
-		Summary: summary,
+		Scores:       scores,
+		Summary:      summary,
+		TestFailures: failures,
 	}, nil
 }
 
 // generateCoverProfile runs go test to produce a coverage profile.
 // The profile is written to a temporary file to avoid clobbering
 // any existing cover.out in the user's working directory.
-func generateCoverProfile(moduleDir string, patterns []string) (string, error) {
+//
+// go test runs with -json so that failing tests can be told apart
+// from a run that produced no coverage: when some packages fail,
+// the profile of the others is returned along with the failures.
+func generateCoverProfile(moduleDir string, patterns []string, goTest config.GoTestConfig) (string, []TestFailure, error) {
 	tmpFile, err := os.CreateTemp("", "gaze-cover-*.out")
 	if err != nil {
-		return "", fmt.Errorf("creating temp cover profile: %w", err)
+		return "", nil, fmt.Errorf("creating temp cover profile: %w", err)
 	}
 	profilePath := tmpFile.Name()
 	_ = tmpFile.Close()
@@ -186,121 +228,108 @@ func generateCoverProfile(moduleDir string, patterns []string) (string, error) {
 	// that would re-invoke go test, causing recursive subprocess
 	// chains. Coverage data from unit + integration tests is
 	// sufficient for CRAP score computation.
-	args := []string{"test", "-short", "-coverprofile=" + profilePath}
+	args := []string{"test", "-short", "-json", "-coverprofile=" + profilePath}
+	args = append(args, goTestArgs(goTest)...)
 	args = append(args, patterns...)
 
 	cmd := exec.Command("go", args...)
 	cmd.Dir = moduleDir
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		_ = os.Remove(profilePath)
-		return "", fmt.Errorf("go test failed: %s\n%s", err, string(output))
-	}
-
-	return profilePath, nil
-}
-
-// resolvePatterns converts Go package patterns (./...) to filesystem
-// paths that gocyclo can walk.
-func resolvePatterns(patterns []string, moduleDir string) ([]string, error) {
-	var paths []string
-	for _, p := range patterns {
-		if p == "./..." {
-			paths = append(paths, moduleDir)
-			continue
+	if len(goTest.Env) > 0 {
+		cmd.Env = os.Environ()
+		for _, k := range slices.Sorted(maps.Keys(goTest.Env)) {
+			cmd.Env = append(cmd.Env, k+"="+goTest.Env[k])
 		}
-		if strings.HasPrefix(p, "./") {
-			abs := filepath.Join(moduleDir, p)
-			paths = append(paths, abs)
-			continue
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	runErr := cmd.Run()
+
+	failures := parseTestFailures(&stdout)
+	if runErr != nil {
+		// Without failing packages to blame, or without a profile,
+		// go test itself failed (bad flag, no packages matched).
+		if info, err := os.Stat(profilePath); len(failures) == 0 || err != nil || info.Size() == 0 {
+			_ = os.Remove(profilePath)
+			return "", nil, fmt.Errorf("go test failed: %s\n%s", runErr, stderr.String())
 		}
-		paths = append(paths, p)
 	}
-	return paths, nil
-}
 
-// coverKey creates a lookup key from file path and line number.
-type coverKey struct {
-	file string
-	line int
+	return profilePath, failures, nil
 }
 
-// coverMaps holds both exact-path and basename-based coverage
-// lookup maps for O(1) access in both cases.
-type coverMaps struct {
-	exact    map[coverKey]float64
-	basename map[coverKey]float64
-}
-
-// buildCoverMap creates lookup maps from (file, startLine) to
-// coverage percentage. A secondary basename-keyed index enables
-// fast fallback matching when paths differ.
-func buildCoverMap(coverages []FuncCoverage) coverMaps {
-	exact := make(map[coverKey]float64, len(coverages))
-	base := make(map[coverKey]float64, len(coverages))
-	for _, fc := range coverages {
-		exact[coverKey{file: fc.File, line: fc.StartLine}] = fc.Percentage
-		base[coverKey{file: filepath.Base(fc.File), line: fc.StartLine}] = fc.Percentage
+// goTestArgs returns the go test flags that goTest configures.
+func goTestArgs(goTest config.GoTestConfig) []string {
+	var args []string
+	if len(goTest.Tags) > 0 {
+		args = append(args, "-tags="+strings.Join(goTest.Tags, ","))
 	}
-	return coverMaps{exact: exact, basename: base}
-}
-
-// lookupCoverage finds the coverage for a gocyclo Stat by matching
-// on file path and line number.
-func lookupCoverage(stat gocyclo.Stat, maps coverMaps) float64 {
-	// Try exact match on absolute path + line.
-	key := coverKey{file: stat.Pos.Filename, line: stat.Pos.Line}
-	if pct, ok := maps.exact[key]; ok {
-		return pct
+	if goTest.Race {
+		args = append(args, "-race")
 	}
-
-	// Try matching by filename basename + line (handles path differences).
-	baseKey := coverKey{file: filepath.Base(stat.Pos.Filename), line: stat.Pos.Line}
-	if pct, ok := maps.basename[baseKey]; ok {
-		return pct
+	if goTest.Timeout > 0 {
+		args = append(args, "-timeout="+goTest.Timeout.String())
 	}
-
-	// No coverage data — function was never executed.
-	return 0
+	if goTest.Count > 0 {
+		args = append(args, "-count="+strconv.Itoa(goTest.Count))
+	}
+	if goTest.CoverPkg != "" {
+		args = append(args, "-coverpkg="+goTest.CoverPkg)
+	}
+	if goTest.Run != "" {
+		args = append(args, "-run="+goTest.Run)
+	}
+	return args
 }
 
-// testFileRegexp matches Go test files by suffix.
-var testFileRegexp = regexp.MustCompile(`_test\.go$`)
-
-// generatedRegexp matches the Go convention for generated file headers:
-// "^// Code generated .* DO NOT EDIT\.$"
-var generatedRegexp = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
-
-// isGeneratedFile checks whether a Go source file was auto-generated
-// by looking for a "// Code generated ... DO NOT EDIT." comment line
-// before the package clause, per the Go convention.
-func isGeneratedFile(path string) bool {
-	f, err := os.Open(path)
-	if err != nil {
-		return false
+// scoreFunc computes the CRAP score of fd and, when contract
+// coverage is available, its GazeCRAP score and quadrant.
+func scoreFunc(pkg *packages.Package, fd *ast.FuncDecl, coverage *LineCoverage, opts Options) Score {
+	recv := ""
+	name := fd.Name.Name
+	if fd.Recv != nil && len(fd.Recv.List) > 0 {
+		recv = types.ExprString(fd.Recv.List[0].Type)
+		name = "(" + recv + ")." + name
 	}
-	defer func() { _ = f.Close() }()
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-		// Stop scanning once we reach the package clause.
-		if strings.HasPrefix(trimmed, "package ") {
-			return false
-		}
-		if generatedRegexp.MatchString(trimmed) {
-			return true
+	pos := pkg.Fset.Position(fd.Pos())
+	comp := complexity(opts.Complexity, fd, pkg.TypesInfo)
+	covPct := coverage.funcCoverage(pos.Filename, pkg.Fset, fd)
+	crapScore := Formula(comp, covPct)
+
+	score := Score{
+		FunctionID:   taxonomy.NewFunctionID(pkg.PkgPath, recv, fd.Name.Name),
+		Package:      pkg.Name,
+		Function:     name,
+		File:         pos.Filename,
+		Line:         pos.Line,
+		Complexity:   comp,
+		LineCoverage: covPct,
+		CRAP:         crapScore,
+	}
+
+	// Compute GazeCRAP if contract coverage is available.
+	if opts.ContractCoverageFunc != nil {
+		ccPct, ok := opts.ContractCoverageFunc(score.FunctionID)
+		if ok {
+			gazeCRAP := Formula(comp, ccPct)
+			quadrant := ClassifyQuadrant(
+				crapScore, gazeCRAP,
+				opts.CRAPThreshold, opts.GazeCRAPThreshold,
+			)
+			score.ContractCoverage = &ccPct
+			score.GazeCRAP = &gazeCRAP
+			score.Quadrant = &quadrant
 		}
 	}
-	return false
+	return score
 }
 
 // buildSummary computes aggregate statistics from the scores.
 func buildSummary(scores []Score, opts Options) Summary {
 	if len(scores) == 0 {
 		return Summary{
-			CRAPThreshold: opts.CRAPThreshold,
+			CRAPThreshold:    opts.CRAPThreshold,
+			ComplexityMetric: opts.Complexity,
 		}
 	}
 
@@ -351,13 +380,14 @@ func buildSummary(scores []Score, opts Options) Summary {
 	}
 
 	summary := Summary{
-		TotalFunctions:  len(scores),
-		AvgComplexity:   totalComp / n,
-		AvgLineCoverage: totalCov / n,
-		AvgCRAP:         totalCRAP / n,
-		CRAPload:        crapload,
-		CRAPThreshold:   opts.CRAPThreshold,
-		WorstCRAP:       worst,
+		TotalFunctions:   len(scores),
+		AvgComplexity:    totalComp / n,
+		AvgLineCoverage:  totalCov / n,
+		AvgCRAP:          totalCRAP / n,
+		CRAPload:         crapload,
+		CRAPThreshold:    opts.CRAPThreshold,
+		ComplexityMetric: opts.Complexity,
+		WorstCRAP:        worst,
 	}
 
 	if hasGazeCRAP {
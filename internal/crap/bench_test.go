@@ -1,6 +1,9 @@
 package crap
 
 import (
+	"go/ast"
+	"go/parser"
+	"go/token"
 	"testing"
 )
 
@@ -34,26 +37,19 @@ func BenchmarkBuildSummary(b *testing.B) {
 	}
 }
 
-func BenchmarkBuildCoverMap(b *testing.B) {
-	coverages := make([]FuncCoverage, 200)
-	for i := range coverages {
-		coverages[i] = FuncCoverage{
-			File:       "file.go",
-			StartLine:  i + 1,
-			Percentage: float64(i) / 2.0,
-		}
+func BenchmarkCognitive(b *testing.B) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "report.go", nil, 0)
+	if err != nil {
+		b.Fatal(err)
 	}
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		buildCoverMap(coverages)
-	}
-}
-
-func BenchmarkIsGeneratedFile_NotGenerated(b *testing.B) {
-	// Use this test file itself as a non-generated file.
-	b.ResetTimer()
-	for i := 0; i < b.N; i++ {
-		isGeneratedFile("bench_test.go")
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok {
+				cognitive(fd, nil)
+			}
+		}
 	}
 }
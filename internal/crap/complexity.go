@@ -0,0 +1,249 @@
+// Package crap computes CRAP scores for Go functions.
+package crap
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// ComplexityMetric selects how the complexity term of the CRAP
+// formula is measured.
+type ComplexityMetric string
+
+// Complexity metric constants.
+const (
+	// Cyclomatic counts the independent paths through a function,
+	// matching gocyclo: 1, plus one per if, for, range, non-default
+	// case and && or || operator.
+	Cyclomatic ComplexityMetric = "cyclomatic"
+
+	// Cognitive measures how hard a function is to read, following
+	// G. Ann Campbell's Cognitive Complexity: control flow costs more
+	// the deeper it is nested, and a switch costs the same however
+	// many cases it has.
+	Cognitive ComplexityMetric = "cognitive"
+)
+
+// ParseComplexityMetric returns the metric named s. An empty name
+// selects Cyclomatic.
+func ParseComplexityMetric(s string) (ComplexityMetric, error) {
+	switch m := ComplexityMetric(s); m {
+	case "":
+		return Cyclomatic, nil
+	case Cyclomatic, Cognitive:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid complexity metric %q: must be 'cyclomatic' or 'cognitive'", s)
+	}
+}
+
+// complexity returns the complexity of fd under metric. info
+// resolves recursive calls for cognitive complexity and may be nil.
+func complexity(metric ComplexityMetric, fd *ast.FuncDecl, info *types.Info) int {
+	if metric == Cognitive {
+		return cognitive(fd, info)
+	}
+	return cyclomatic(fd)
+}
+
+// cyclomatic returns the cyclomatic complexity of fd, including the
+// function literals it contains, as gocyclo computes it.
+func cyclomatic(fd *ast.FuncDecl) int {
+	comp := 1
+	ast.Inspect(fd, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.IfStmt, *ast.ForStmt, *ast.RangeStmt:
+			comp++
+		case *ast.CaseClause:
+			if n.List != nil { // default case
+				comp++
+			}
+		case *ast.CommClause:
+			if n.Comm != nil { // default case
+				comp++
+			}
+		case *ast.BinaryExpr:
+			if n.Op == token.LAND || n.Op == token.LOR {
+				comp++
+			}
+		}
+		return true
+	})
+	return comp
+}
+
+// cognitive returns the cognitive complexity of fd.
+func cognitive(fd *ast.FuncDecl, info *types.Info) int {
+	if fd.Body == nil {
+		return 0
+	}
+	v := &cognitiveVisitor{
+		elseIfs: make(map[*ast.IfStmt]bool),
+		counted: make(map[*ast.BinaryExpr]bool),
+	}
+	if info != nil {
+		v.self = info.Defs[fd.Name]
+		v.uses = info.Uses
+	}
+	ast.Walk(v, fd.Body)
+	return v.comp
+}
+
+// cognitiveVisitor accumulates cognitive complexity while tracking
+// the nesting level of the node being visited.
+type cognitiveVisitor struct {
+	comp    int
+	nesting int
+
+	// elseIfs marks if statements in else position, which cost a
+	// flat increment instead of a nested one.
+	elseIfs map[*ast.IfStmt]bool
+
+	// counted marks the operands of a logical expression already
+	// scored as part of the outermost expression.
+	counted map[*ast.BinaryExpr]bool
+
+	// self and uses identify recursive calls.
+	self types.Object
+	uses map[*ast.Ident]types.Object
+}
+
+// nested walks nodes one nesting level deeper.
+func (v *cognitiveVisitor) nested(nodes ...ast.Node) {
+	v.nesting++
+	for _, n := range nodes {
+		if n != nil {
+			ast.Walk(v, n)
+		}
+	}
+	v.nesting--
+}
+
+// walk walks nodes at the current nesting level.
+func (v *cognitiveVisitor) walk(nodes ...ast.Node) {
+	for _, n := range nodes {
+		if n != nil {
+			ast.Walk(v, n)
+		}
+	}
+}
+
+func (v *cognitiveVisitor) Visit(n ast.Node) ast.Visitor {
+	switch n := n.(type) {
+	case *ast.IfStmt:
+		if v.elseIfs[n] {
+			v.comp++
+		} else {
+			v.comp += 1 + v.nesting
+		}
+		v.walk(n.Init, n.Cond)
+		v.nested(n.Body)
+		switch e := n.Else.(type) {
+		case *ast.IfStmt:
+			v.elseIfs[e] = true
+			v.walk(e)
+		case *ast.BlockStmt:
+			v.comp++
+			v.nested(e)
+		}
+		return nil
+	case *ast.SwitchStmt:
+		v.comp += 1 + v.nesting
+		v.walk(n.Init, n.Tag)
+		v.nested(n.Body)
+		return nil
+	case *ast.TypeSwitchStmt:
+		v.comp += 1 + v.nesting
+		v.walk(n.Init, n.Assign)
+		v.nested(n.Body)
+		return nil
+	case *ast.SelectStmt:
+		v.comp += 1 + v.nesting
+		v.nested(n.Body)
+		return nil
+	case *ast.ForStmt:
+		v.comp += 1 + v.nesting
+		v.walk(n.Init, n.Cond, n.Post)
+		v.nested(n.Body)
+		return nil
+	case *ast.RangeStmt:
+		v.comp += 1 + v.nesting
+		v.walk(n.Key, n.Value, n.X)
+		v.nested(n.Body)
+		return nil
+	case *ast.FuncLit:
+		v.nested(n.Body)
+		return nil
+	case *ast.BranchStmt:
+		if n.Tok == token.GOTO || n.Label != nil {
+			v.comp++
+		}
+	case *ast.BinaryExpr:
+		if isLogical(n.Op) && !v.counted[n] {
+			var last token.Token
+			for _, op := range v.logicalOps(n) {
+				if op != last {
+					v.comp++
+					last = op
+				}
+			}
+		}
+	case *ast.CallExpr:
+		if v.isRecursive(n) {
+			v.comp++
+		}
+	}
+	return v
+}
+
+// logicalOps returns the sequence of && and || operators in x, read
+// left to right through parentheses, and marks the binary
+// expressions visited so that they are not scored again.
+func (v *cognitiveVisitor) logicalOps(x ast.Expr) []token.Token {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return v.logicalOps(x.X)
+	case *ast.BinaryExpr:
+		v.counted[x] = true
+		ops := v.logicalOps(x.X)
+		if isLogical(x.Op) {
+			ops = append(ops, x.Op)
+		}
+		return append(ops, v.logicalOps(x.Y)...)
+	}
+	return nil
+}
+
+// isRecursive reports whether call invokes the function being
+// measured.
+func (v *cognitiveVisitor) isRecursive(call *ast.CallExpr) bool {
+	if v.self == nil {
+		return false
+	}
+	var id *ast.Ident
+	switch fn := ast.Unparen(call.Fun).(type) {
+	case *ast.Ident:
+		id = fn
+	case *ast.SelectorExpr:
+		id = fn.Sel
+	case *ast.IndexExpr:
+		id, _ = fn.X.(*ast.Ident)
+	case *ast.IndexListExpr:
+		id, _ = fn.X.(*ast.Ident)
+	}
+	if id == nil {
+		return false
+	}
+	obj := v.uses[id]
+	if fn, ok := obj.(*types.Func); ok {
+		obj = fn.Origin()
+	}
+	return obj == v.self
+}
+
+// isLogical reports whether op is a short-circuit logical operator.
+func isLogical(op token.Token) bool {
+	return op == token.LAND || op == token.LOR
+}
@@ -4,187 +4,184 @@ package crap
 import (
 	"fmt"
 	"go/ast"
-	"go/parser"
 	"go/token"
-	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"golang.org/x/tools/cover"
 )
 
-// FuncCoverage holds the coverage percentage for a single function.
-type FuncCoverage struct {
-	// File is the absolute filesystem path.
-	File string `json:"file"`
-
-	// FuncName is the function name (e.g., "Save" or "(*Store).Save").
-	FuncName string `json:"func_name"`
-
-	// StartLine is the function declaration start line.
-	StartLine int `json:"start_line"`
-
-	// EndLine is the function body end line.
-	EndLine int `json:"end_line"`
-
-	// CoveredStmts is the number of statements covered by tests.
-	CoveredStmts int64 `json:"covered_stmts"`
-
-	// TotalStmts is the total number of statements in the function.
-	TotalStmts int64 `json:"total_stmts"`
-
-	// Percentage is the coverage percentage (0-100).
-	Percentage float64 `json:"percentage"`
+// LineCoverage records which source lines a coverage profile saw
+// executed, keyed by absolute file path.
+type LineCoverage struct {
+	blocks map[string][]cover.ProfileBlock
 }
 
-// ParseCoverProfile reads a Go coverage profile and computes
-// per-function coverage percentages.
-//
-// The moduleDir is the root directory of the Go module, used to
-// resolve import paths to filesystem paths. If empty, the current
-// directory is used.
-//
-// Warnings about files that cannot be parsed are written to stderr.
-// If stderr is nil, warnings are suppressed.
-func ParseCoverProfile(profilePath string, moduleDir string, stderr io.Writer) ([]FuncCoverage, error) {
-	profiles, err := cover.ParseProfiles(profilePath)
+// ParseLineCoverage reads and merges Go coverage profiles for
+// line-level queries. File names are resolved against moduleDir, as
+// import paths relative to its module or as absolute paths; blocks
+// of files that cannot be resolved are dropped.
+func ParseLineCoverage(profilePaths []string, moduleDir string) (*LineCoverage, error) {
+	profiles, err := ReadProfiles(profilePaths...)
 	if err != nil {
 		return nil, err
 	}
-
 	if moduleDir == "" {
 		moduleDir, _ = os.Getwd()
 	}
 
-	var results []FuncCoverage
-
+	lc := &LineCoverage{blocks: make(map[string][]cover.ProfileBlock, len(profiles))}
 	for _, profile := range profiles {
-		// Resolve the import path to a filesystem path.
-		filePath := resolveFilePath(profile.FileName, moduleDir)
-		if filePath == "" {
-			continue
+		if filePath := resolveFilePath(profile.FileName, moduleDir); filePath != "" {
+			lc.blocks[filePath] = append(lc.blocks[filePath], profile.Blocks...)
 		}
+	}
+	return lc, nil
+}
 
-		// Find all functions in this source file.
-		funcs, err := findFunctions(filePath)
+// ReadProfiles parses the coverage profiles at paths and merges them
+// into one profile per source file. A block reported by several
+// profiles, as when unit tests and -coverpkg integration tests both
+// cover a package, is counted once: its counts are summed, or in set
+// mode combined, as go tool cover does for a single profile.
+func ReadProfiles(paths ...string) ([]*cover.Profile, error) {
+	var merged []*cover.Profile
+	byFile := make(map[string]*cover.Profile)
+	for _, path := range paths {
+		profiles, err := cover.ParseProfiles(path)
 		if err != nil {
-			if stderr != nil {
-				_, _ = fmt.Fprintf(stderr, "warning: skipping %s: %v\n", filePath, err)
-			}
-			continue
+			return nil, fmt.Errorf("%s: %w", path, err)
 		}
-
-		// Compute coverage per function.
-		for _, fn := range funcs {
-			covered, total := funcCoverage(fn, profile)
-			pct := 0.0
-			if total > 0 {
-				pct = 100.0 * float64(covered) / float64(total)
+		for _, p := range profiles {
+			if dst, ok := byFile[p.FileName]; ok {
+				mergeProfile(dst, p)
+				continue
 			}
-			results = append(results, FuncCoverage{
-				File:         filePath,
-				FuncName:     fn.name,
-				StartLine:    fn.startLine,
-				EndLine:      fn.endLine,
-				CoveredStmts: covered,
-				TotalStmts:   total,
-				Percentage:   pct,
-			})
+			byFile[p.FileName] = p
+			merged = append(merged, p)
 		}
 	}
-
-	return results, nil
+	return merged, nil
 }
 
-// funcExtent describes a function's source position.
-type funcExtent struct {
-	name      string
-	startLine int
-	startCol  int
-	endLine   int
-	endCol    int
+// blockExtent identifies a profile block by its source range.
+type blockExtent struct {
+	startLine, startCol, endLine, endCol int
 }
 
-// findFunctions parses a Go source file and returns the extent of
-// each function declaration.
-func findFunctions(filePath string) ([]funcExtent, error) {
-	fset := token.NewFileSet()
-	f, err := parser.ParseFile(fset, filePath, nil, 0)
-	if err != nil {
-		return nil, err
+// mergeProfile adds the blocks of src to dst, which profile the same
+// file. When the modes differ, the merged profile counts executions
+// rather than recording only whether a block ran.
+func mergeProfile(dst, src *cover.Profile) {
+	if dst.Mode == "set" && src.Mode != "set" {
+		dst.Mode = src.Mode
 	}
-
-	var funcs []funcExtent
-	ast.Inspect(f, func(n ast.Node) bool {
-		fn, ok := n.(*ast.FuncDecl)
-		if !ok || fn.Body == nil {
-			return true
-		}
-		start := fset.Position(fn.Pos())
-		end := fset.Position(fn.End())
-
-		name := fn.Name.Name
-		if fn.Recv != nil && fn.Recv.NumFields() > 0 {
-			recvType := recvTypeString(fn.Recv.List[0].Type)
-			name = "(" + recvType + ")." + fn.Name.Name
+	index := make(map[blockExtent]int, len(dst.Blocks))
+	for i, b := range dst.Blocks {
+		index[blockExtent{b.StartLine, b.StartCol, b.EndLine, b.EndCol}] = i
+	}
+	for _, b := range src.Blocks {
+		i, ok := index[blockExtent{b.StartLine, b.StartCol, b.EndLine, b.EndCol}]
+		switch {
+		case !ok:
+			index[blockExtent{b.StartLine, b.StartCol, b.EndLine, b.EndCol}] = len(dst.Blocks)
+			dst.Blocks = append(dst.Blocks, b)
+		case dst.Mode == "set":
+			if b.Count > 0 {
+				dst.Blocks[i].Count = 1
+			}
+		default:
+			dst.Blocks[i].Count += b.Count
 		}
-
-		funcs = append(funcs, funcExtent{
-			name:      name,
-			startLine: start.Line,
-			startCol:  start.Column,
-			endLine:   end.Line,
-			endCol:    end.Column,
-		})
-		return true
+	}
+	sort.Slice(dst.Blocks, func(i, j int) bool {
+		bi, bj := dst.Blocks[i], dst.Blocks[j]
+		return bi.StartLine < bj.StartLine || (bi.StartLine == bj.StartLine && bi.StartCol < bj.StartCol)
 	})
-	return funcs, nil
 }
 
-// recvTypeString extracts the receiver type as a string.
-func recvTypeString(expr ast.Expr) string {
-	switch t := expr.(type) {
-	case *ast.StarExpr:
-		return "*" + recvTypeString(t.X)
-	case *ast.Ident:
-		return t.Name
-	case *ast.IndexExpr:
-		return recvTypeString(t.X) + "[" + recvTypeString(t.Index) + "]"
-	default:
-		return "?"
+// convertCoverData converts the binary coverage data in dirs, as
+// written to GOCOVERDIR by binaries built with -cover, to a text
+// profile in a temporary file using go tool covdata.
+func convertCoverData(moduleDir string, dirs []string) (string, error) {
+	tmpFile, err := os.CreateTemp("", "gaze-covdata-*.out")
+	if err != nil {
+		return "", fmt.Errorf("creating temp cover profile: %w", err)
 	}
+	profilePath := tmpFile.Name()
+	_ = tmpFile.Close()
+
+	cmd := exec.Command("go", "tool", "covdata", "textfmt",
+		"-i="+strings.Join(dirs, ","), "-o="+profilePath)
+	cmd.Dir = moduleDir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		_ = os.Remove(profilePath)
+		return "", fmt.Errorf("go tool covdata failed: %s\n%s", err, string(output))
+	}
+
+	return profilePath, nil
 }
 
-// funcCoverage computes the covered and total statement counts for a
-// function within a coverage profile.
-//
-// Assumes profile.Blocks are sorted by StartLine, which is guaranteed
-// by cover.ParseProfiles (mirrors go tool cover behavior). The early
-// break optimization depends on this ordering.
-func funcCoverage(fn funcExtent, profile *cover.Profile) (covered, total int64) {
-	for _, b := range profile.Blocks {
-		// Block entirely after the function — stop.
-		if b.StartLine > fn.endLine {
-			break
+// Executed reports whether the statement at file:line ran. A line
+// outside every block, such as a function signature, is judged by
+// the first block after it, so a signature line counts as executed
+// when the function body's entry block ran. known is false when the
+// profile has no block at or after the line in file.
+func (lc *LineCoverage) Executed(file string, line int) (executed, known bool) {
+	if lc == nil {
+		return false, false
+	}
+	blocks := lc.blocks[file]
+	var next *cover.ProfileBlock
+	for i, b := range blocks {
+		if b.StartLine <= line && line <= b.EndLine {
+			known = true
+			if b.Count > 0 {
+				return true, true
+			}
+			continue
 		}
-		if b.StartLine == fn.endLine && b.StartCol >= fn.endCol {
-			break
+		if b.StartLine > line && (next == nil || b.StartLine < next.StartLine ||
+			(b.StartLine == next.StartLine && b.StartCol < next.StartCol)) {
+			next = &blocks[i]
 		}
-		// Block entirely before the function — skip.
-		if b.EndLine < fn.startLine {
+	}
+	if known {
+		return false, true
+	}
+	if next != nil {
+		return next.Count > 0, true
+	}
+	return false, false
+}
+
+// funcCoverage returns the percentage (0-100) of the statements of
+// fd, declared in file, that the profile saw executed. A function
+// with no blocks in the profile was never executed.
+func (lc *LineCoverage) funcCoverage(file string, fset *token.FileSet, fd *ast.FuncDecl) float64 {
+	start, end := fset.Position(fd.Pos()), fset.Position(fd.End())
+	var covered, total int64
+	for _, b := range lc.blocks[file] {
+		// Skip blocks entirely outside the function.
+		if b.StartLine > end.Line || (b.StartLine == end.Line && b.StartCol >= end.Column) {
 			continue
 		}
-		if b.EndLine == fn.startLine && b.EndCol <= fn.startCol {
+		if b.EndLine < start.Line || (b.EndLine == start.Line && b.EndCol <= start.Column) {
 			continue
 		}
-		// Block overlaps the function.
 		total += int64(b.NumStmt)
 		if b.Count > 0 {
 			covered += int64(b.NumStmt)
 		}
 	}
-	return
+	if total == 0 {
+		return 0
+	}
+	return 100.0 * float64(covered) / float64(total)
 }
 
 // resolveFilePath maps a coverage profile filename (import path
@@ -1,8 +1,9 @@
 // Package crap computes CRAP (Change Risk Anti-Patterns) scores for
-// Go functions by combining cyclomatic complexity with test coverage.
+// Go functions by combining complexity with test coverage.
 //
 // The CRAP formula: CRAP(m) = comp^2 * (1 - cov/100)^3 + comp
-// where comp = cyclomatic complexity and cov = coverage percentage.
+// where comp = cyclomatic (or, optionally, cognitive) complexity and
+// cov = coverage percentage.
 //
 // A CRAPload is the count of functions with a CRAP score at or above
 // a given threshold (default 15).
@@ -10,10 +11,17 @@ package crap
 
 import (
 	"math"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 // Score holds the CRAP score for a single function.
 type Score struct {
+	// FunctionID is the canonical identity of the function, shared
+	// with analyze and quality output. Empty for files outside the
+	// module.
+	FunctionID taxonomy.FunctionID `json:"function_id,omitempty"`
+
 	// Package is the Go package name.
 	Package string `json:"package"`
 
@@ -27,7 +35,8 @@ type Score struct {
 	// Line is the line number of the function declaration.
 	Line int `json:"line"`
 
-	// Complexity is the cyclomatic complexity.
+	// Complexity is the function's complexity under the report's
+	// ComplexityMetric.
 	Complexity int `json:"complexity"`
 
 	// LineCoverage is the line coverage percentage (0-100).
@@ -68,6 +77,7 @@ type Summary struct {
 	AvgCRAP             float64          `json:"avg_crap"`
 	CRAPload            int              `json:"crapload"`
 	CRAPThreshold       float64          `json:"crap_threshold"`
+	ComplexityMetric    ComplexityMetric `json:"complexity_metric"`
 	GazeCRAPload        *int             `json:"gaze_crapload,omitempty"`
 	GazeCRAPThreshold   *float64         `json:"gaze_crap_threshold,omitempty"`
 	AvgGazeCRAP         *float64         `json:"avg_gaze_crap,omitempty"`
@@ -77,14 +87,32 @@ type Summary struct {
 	WorstGazeCRAP       []Score          `json:"worst_gaze_crap,omitempty"`
 }
 
+// TestFailure records a package whose tests failed, or did not
+// build, while Gaze generated coverage. Its functions are not
+// scored.
+type TestFailure struct {
+	// Package is the import path of the package.
+	Package string `json:"package"`
+
+	// Tests are the failing tests, innermost subtests only. Empty
+	// when the package failed outside any test, e.g. to build.
+	Tests []string `json:"tests,omitempty"`
+
+	// Output is the output of the failing tests, or of the build
+	// or test binary when no test failed.
+	Output string `json:"output,omitempty"`
+}
+
 // Report is the complete CRAP analysis output.
 type Report struct {
-	Scores  []Score `json:"scores"`
-	Summary Summary `json:"summary"`
+	Scores       []Score       `json:"scores"`
+	Summary      Summary       `json:"summary"`
+	TestFailures []TestFailure `json:"test_failures,omitempty"`
 }
 
 // Formula computes CRAP(m) = comp^2 * (1 - cov/100)^3 + comp.
-// comp is cyclomatic complexity (>= 1).
+// comp is the function's complexity: cyclomatic (>= 1) or cognitive
+// (>= 0).
 // coveragePct is line coverage as a percentage (0-100).
 // Returns the CRAP score as a float64; higher scores indicate higher risk.
 func Formula(complexity int, coveragePct float64) float64 {
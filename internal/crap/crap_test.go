@@ -4,17 +4,24 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"go/ast"
+	"go/parser"
 	"go/token"
+	"go/types"
 	"math"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"regexp"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
-	"github.com/fzipp/gocyclo"
 	"golang.org/x/tools/cover"
+
+	"github.com/unbound-force/gaze/internal/config"
+	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
 func TestFormula_ZeroCoverage(t *testing.T) {
@@ -420,297 +427,139 @@ func TestWriteText_MarksAboveThreshold(t *testing.T) {
 	}
 }
 
-// --- isGeneratedFile tests ---
-
-func TestIsGeneratedFile_Generated(t *testing.T) {
-	dir := t.TempDir()
-	path := filepath.Join(dir, "gen.go")
-	content := `// Code generated by protoc-gen-go. DO NOT EDIT.
-
-package pb
-
-func Foo() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	if !isGeneratedFile(path) {
-		t.Error("expected file to be detected as generated")
-	}
-}
-
-func TestIsGeneratedFile_NotGenerated(t *testing.T) {
-	dir := t.TempDir()
-	path := filepath.Join(dir, "normal.go")
-	content := `// Package foo provides functionality.
-package foo
+// --- complexity tests ---
 
-func Bar() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	if isGeneratedFile(path) {
-		t.Error("expected file NOT to be detected as generated")
-	}
-}
-
-func TestIsGeneratedFile_GeneratedAfterPackage(t *testing.T) {
-	// A "Code generated" comment AFTER the package clause should
-	// NOT count as generated (per Go convention).
-	dir := t.TempDir()
-	path := filepath.Join(dir, "late.go")
-	content := `package foo
-
-// Code generated by something. DO NOT EDIT.
-func Baz() {}
-`
-	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	if isGeneratedFile(path) {
-		t.Error("comment after package clause should not be detected as generated")
-	}
-}
-
-func TestIsGeneratedFile_NonexistentFile(t *testing.T) {
-	if isGeneratedFile("/nonexistent/path/file.go") {
-		t.Error("nonexistent file should return false")
-	}
-}
-
-// --- resolvePatterns tests ---
-
-func TestResolvePatterns_DotSlashDotDotDot(t *testing.T) {
-	paths, err := resolvePatterns([]string{"./..."}, "/module/dir")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(paths) != 1 || paths[0] != "/module/dir" {
-		t.Errorf("expected [/module/dir], got %v", paths)
-	}
-}
-
-func TestResolvePatterns_DotSlashPrefix(t *testing.T) {
-	paths, err := resolvePatterns([]string{"./cmd/gaze"}, "/module/dir")
-	if err != nil {
-		t.Fatal(err)
-	}
-	want := filepath.Join("/module/dir", "./cmd/gaze")
-	if len(paths) != 1 || paths[0] != want {
-		t.Errorf("expected [%s], got %v", want, paths)
-	}
-}
-
-func TestResolvePatterns_BarePattern(t *testing.T) {
-	paths, err := resolvePatterns([]string{"some/path"}, "/module/dir")
-	if err != nil {
-		t.Fatal(err)
-	}
-	if len(paths) != 1 || paths[0] != "some/path" {
-		t.Errorf("expected [some/path], got %v", paths)
-	}
-}
-
-func TestResolvePatterns_MultiplePatterns(t *testing.T) {
-	patterns := []string{"./...", "./internal/crap", "bare"}
-	paths, err := resolvePatterns(patterns, "/mod")
+// parseFunc type-checks a function f whose body is body and returns
+// its declaration and type information.
+func parseFunc(t *testing.T, body string) (*ast.FuncDecl, *types.Info) {
+	t.Helper()
+	src := "package p\n\nfunc f(x int, ok bool, ch chan int) {\n" + body + "\n}\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
 	if err != nil {
-		t.Fatal(err)
-	}
-	if len(paths) != 3 {
-		t.Fatalf("expected 3 paths, got %d", len(paths))
-	}
-	if paths[0] != "/mod" {
-		t.Errorf("paths[0] = %q, want /mod", paths[0])
-	}
-	if paths[1] != filepath.Join("/mod", "./internal/crap") {
-		t.Errorf("paths[1] = %q, want %s", paths[1],
-			filepath.Join("/mod", "./internal/crap"))
-	}
-	if paths[2] != "bare" {
-		t.Errorf("paths[2] = %q, want bare", paths[2])
-	}
-}
-
-// --- buildCoverMap tests ---
-
-func TestBuildCoverMap_Basic(t *testing.T) {
-	coverages := []FuncCoverage{
-		{File: "foo.go", StartLine: 10, Percentage: 85.0},
-		{File: "bar.go", StartLine: 20, Percentage: 50.0},
-	}
-	m := buildCoverMap(coverages)
-
-	if pct, ok := m.exact[coverKey{file: "foo.go", line: 10}]; !ok || pct != 85.0 {
-		t.Errorf("expected 85.0 for foo.go:10, got %v (ok=%v)", pct, ok)
-	}
-	if pct, ok := m.exact[coverKey{file: "bar.go", line: 20}]; !ok || pct != 50.0 {
-		t.Errorf("expected 50.0 for bar.go:20, got %v (ok=%v)", pct, ok)
-	}
-}
-
-func TestBuildCoverMap_Empty(t *testing.T) {
-	m := buildCoverMap(nil)
-	if len(m.exact) != 0 {
-		t.Errorf("expected empty map, got %d entries", len(m.exact))
+		t.Fatalf("parsing %q: %v", body, err)
 	}
-}
-
-// --- lookupCoverage tests ---
-
-func TestLookupCoverage_ExactMatch(t *testing.T) {
-	m := buildCoverMap([]FuncCoverage{
-		{File: "/abs/path/foo.go", StartLine: 10, Percentage: 75.0},
-	})
-	stat := gocyclo.Stat{
-		Pos: token.Position{Filename: "/abs/path/foo.go", Line: 10},
-	}
-	got := lookupCoverage(stat, m)
-	if got != 75.0 {
-		t.Errorf("expected 75.0, got %f", got)
+	info := &types.Info{
+		Defs: make(map[*ast.Ident]types.Object),
+		Uses: make(map[*ast.Ident]types.Object),
 	}
+	// Unused variables in the snippets are reported but do not stop
+	// type checking.
+	conf := types.Config{Error: func(error) {}}
+	_, _ = conf.Check("p", fset, []*ast.File{f}, info)
+	return f.Decls[0].(*ast.FuncDecl), info
 }
 
-func TestLookupCoverage_BasenameFallback(t *testing.T) {
-	m := buildCoverMap([]FuncCoverage{
-		{File: "/other/path/foo.go", StartLine: 10, Percentage: 60.0},
-	})
-	stat := gocyclo.Stat{
-		Pos: token.Position{Filename: "/different/path/foo.go", Line: 10},
-	}
-	got := lookupCoverage(stat, m)
-	if got != 60.0 {
-		t.Errorf("expected 60.0 via basename fallback, got %f", got)
+func TestCyclomatic(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"straight line", "x++", 1},
+		{"if else", "if x > 0 { x++ } else { x-- }", 2},
+		{"else if", "if x > 0 {} else if x < 0 {}", 3},
+		{"switch cases but not default", "switch x { case 1: case 2, 3: default: }", 3},
+		{"select cases but not default", "select { case <-ch: default: }", 2},
+		{"loops", "for i := 0; i < x; i++ {}\nfor range ch {}", 3},
+		{"logical operators", "if x > 0 && ok || !ok {}", 4},
+		{"closure", "g := func() { if ok {} }\ng()", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd, _ := parseFunc(t, tt.body)
+			if got := cyclomatic(fd); got != tt.want {
+				t.Errorf("cyclomatic = %d, want %d", got, tt.want)
+			}
+		})
 	}
 }
 
-func TestLookupCoverage_NoMatch(t *testing.T) {
-	m := buildCoverMap([]FuncCoverage{
-		{File: "foo.go", StartLine: 10, Percentage: 50.0},
-	})
-	stat := gocyclo.Stat{
-		Pos: token.Position{Filename: "bar.go", Line: 20},
-	}
-	got := lookupCoverage(stat, m)
-	if got != 0 {
-		t.Errorf("expected 0 for no match, got %f", got)
+func TestCognitive(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"straight line", "x++", 0},
+		{"if else", "if x > 0 { x++ } else { x-- }", 2},
+		{"else if chain", "if x > 0 {} else if x < 0 {} else {}", 3},
+		{"nesting", "for i := 0; i < x; i++ { if ok { if x > 1 {} } }", 6},
+		{"switch counts once", "switch x { case 1: case 2: case 3: default: }", 1},
+		{"logical sequences", "if x > 0 && ok && x < 9 || !ok {}", 3},
+		{"parenthesized operators", "_ = ok && (x > 0 || x < -1)", 2},
+		{"closure nests", "g := func() { if ok {} }\ng()", 2},
+		{"labeled break", "outer:\nfor {\nfor { break outer }\n}", 4},
+		{"recursion", "if x > 0 { f(x-1, ok, ch) }", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd, info := parseFunc(t, tt.body)
+			if got := cognitive(fd, info); got != tt.want {
+				t.Errorf("cognitive = %d, want %d", got, tt.want)
+			}
+		})
 	}
 }
 
-// --- coverage.go tests ---
-
-func TestRecvTypeString_Ident(t *testing.T) {
-	// Test is implicit through findFunctions, but we test the
-	// helper directly here. We use AST construction.
-	// For simplicity, parse a file and check results.
-	dir := t.TempDir()
-	src := filepath.Join(dir, "recv.go")
-	content := `package recv
-
-type Foo struct{}
-type Bar struct{}
-
-func (f *Foo) Method1() {}
-func (b Bar) Method2() int { return 0 }
-`
-	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	funcs, err := findFunctions(src)
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	names := make(map[string]bool)
-	for _, fn := range funcs {
-		names[fn.name] = true
-	}
-
-	if !names["(*Foo).Method1"] {
-		t.Error("expected (*Foo).Method1")
-	}
-	if !names["(Bar).Method2"] {
-		t.Error("expected (Bar).Method2")
+func TestParseComplexityMetric(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ComplexityMetric
+		wantErr bool
+	}{
+		{"", Cyclomatic, false},
+		{"cyclomatic", Cyclomatic, false},
+		{"cognitive", Cognitive, false},
+		{"halstead", "", true},
+	}
+	for _, tt := range tests {
+		got, err := ParseComplexityMetric(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseComplexityMetric(%q) = (%q, %v), want (%q, error=%v)",
+				tt.in, got, err, tt.want, tt.wantErr)
+		}
 	}
 }
 
-func TestFindFunctions_NoBody(t *testing.T) {
-	// Interface methods and external function declarations have no body
-	// and should be skipped.
-	dir := t.TempDir()
-	src := filepath.Join(dir, "iface.go")
-	content := `package iface
+// --- funcCoverage tests ---
 
-type Service interface {
-	DoWork()
-}
-
-func Concrete() int { return 1 }
-`
-	if err := os.WriteFile(src, []byte(content), 0644); err != nil {
-		t.Fatal(err)
-	}
-	funcs, err := findFunctions(src)
+// funcAtLines10To20 returns a function declaration starting at line
+// 10, column 1 and ending at line 20, column 2.
+func funcAtLines10To20(t *testing.T) (*token.FileSet, *ast.FuncDecl) {
+	t.Helper()
+	src := "package p\n" + strings.Repeat("\n", 8) + "func Foo() {\n" + strings.Repeat("\n", 9) + "}\n"
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "foo.go", src, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
-
-	// Only Concrete should be found (DoWork has no body).
-	if len(funcs) != 1 {
-		t.Fatalf("expected 1 function, got %d", len(funcs))
-	}
-	if funcs[0].name != "Concrete" {
-		t.Errorf("expected 'Concrete', got %q", funcs[0].name)
-	}
-}
-
-func TestFindFunctions_InvalidFile(t *testing.T) {
-	_, err := findFunctions("/nonexistent/path.go")
-	if err == nil {
-		t.Error("expected error for nonexistent file")
-	}
+	return fset, f.Decls[0].(*ast.FuncDecl)
 }
 
 func TestFuncCoverage_OverlappingBlocks(t *testing.T) {
-	fn := funcExtent{
-		name:      "Foo",
-		startLine: 10,
-		startCol:  1,
-		endLine:   20,
-		endCol:    2,
-	}
-	profile := &cover.Profile{
-		Blocks: []cover.ProfileBlock{
+	fset, fd := funcAtLines10To20(t)
+	lc := &LineCoverage{blocks: map[string][]cover.ProfileBlock{
+		"foo.go": {
 			{StartLine: 5, StartCol: 1, EndLine: 8, EndCol: 1, NumStmt: 3, Count: 1},   // before function
 			{StartLine: 11, StartCol: 1, EndLine: 15, EndCol: 1, NumStmt: 5, Count: 1}, // inside, covered
 			{StartLine: 16, StartCol: 1, EndLine: 18, EndCol: 1, NumStmt: 2, Count: 0}, // inside, not covered
 			{StartLine: 21, StartCol: 1, EndLine: 25, EndCol: 1, NumStmt: 4, Count: 1}, // after function
 		},
-	}
+	}}
 
-	covered, total := funcCoverage(fn, profile)
-	if total != 7 {
-		t.Errorf("expected total=7 (5+2), got %d", total)
-	}
-	if covered != 5 {
-		t.Errorf("expected covered=5, got %d", covered)
+	got := lc.funcCoverage("foo.go", fset, fd)
+	if want := 100.0 * 5 / 7; math.Abs(got-want) > 1e-9 {
+		t.Errorf("funcCoverage = %.2f, want %.2f (5 of 7 statements)", got, want)
 	}
 }
 
 func TestFuncCoverage_EmptyProfile(t *testing.T) {
-	fn := funcExtent{
-		name:      "Foo",
-		startLine: 10,
-		startCol:  1,
-		endLine:   20,
-		endCol:    2,
-	}
-	profile := &cover.Profile{}
+	fset, fd := funcAtLines10To20(t)
+	lc := &LineCoverage{blocks: map[string][]cover.ProfileBlock{}}
 
-	covered, total := funcCoverage(fn, profile)
-	if total != 0 || covered != 0 {
-		t.Errorf("expected 0/0 for empty profile, got %d/%d", covered, total)
+	if got := lc.funcCoverage("foo.go", fset, fd); got != 0 {
+		t.Errorf("expected 0%% for empty profile, got %.1f%%", got)
 	}
 }
 
@@ -1047,6 +896,9 @@ func TestDefaultOptions_ReturnsExpectedDefaults(t *testing.T) {
 	if opts.GazeCRAPThreshold != 15 {
 		t.Errorf("GazeCRAPThreshold = %.0f, want 15", opts.GazeCRAPThreshold)
 	}
+	if opts.Complexity != Cyclomatic {
+		t.Errorf("Complexity = %q, want %q", opts.Complexity, Cyclomatic)
+	}
 	if !opts.IgnoreGenerated {
 		t.Error("IgnoreGenerated should default to true")
 	}
@@ -1092,7 +944,7 @@ func moduleRoot(t *testing.T) string {
 // error when the user-supplied cover profile path does not exist.
 func TestAnalyze_CoverProfileNotFound(t *testing.T) {
 	opts := DefaultOptions()
-	opts.CoverProfile = "/nonexistent/path/cover.out"
+	opts.CoverProfiles = []string{"/nonexistent/path/cover.out"}
 
 	_, err := Analyze([]string{"./internal/crap"}, moduleRoot(t), opts)
 	if err == nil {
@@ -1107,7 +959,7 @@ func TestAnalyze_CoverProfileNotFound(t *testing.T) {
 // an error when the user-supplied cover profile path is a directory.
 func TestAnalyze_CoverProfileIsDirectory(t *testing.T) {
 	opts := DefaultOptions()
-	opts.CoverProfile = t.TempDir() // a directory, not a file
+	opts.CoverProfiles = []string{t.TempDir()} // a directory, not a file
 
 	_, err := Analyze([]string{"./internal/crap"}, moduleRoot(t), opts)
 	if err == nil {
@@ -1118,10 +970,33 @@ func TestAnalyze_CoverProfileIsDirectory(t *testing.T) {
 	}
 }
 
+// coveredBody returns a coverage profile line marking the body of
+// the named function in crap.go as executed, so that profiles built
+// by tests do not depend on the file's line numbers.
+func coveredBody(t *testing.T, modRoot, name string) string {
+	t.Helper()
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filepath.Join(modRoot, "internal", "crap", "crap.go"), nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, decl := range f.Decls {
+		fd, ok := decl.(*ast.FuncDecl)
+		if !ok || fd.Name.Name != name || fd.Body == nil {
+			continue
+		}
+		start, end := fset.Position(fd.Body.Lbrace), fset.Position(fd.Body.End())
+		return fmt.Sprintf("github.com/unbound-force/gaze/internal/crap/crap.go:%d.%d,%d.%d %d 1\n",
+			start.Line, start.Column, end.Line, end.Column, len(fd.Body.List))
+	}
+	t.Fatalf("%s not found in crap.go", name)
+	return ""
+}
+
 // TestAnalyze_WithPrebuiltProfile runs the full Analyze pipeline
 // using a pre-built coverage profile, bypassing the go test
-// subprocess. This tests steps 2-6 of Analyze and also exercises
-// ParseCoverProfile and buildCoverMap.
+// subprocess. This tests steps 2-5 of Analyze and also exercises
+// ParseLineCoverage and funcCoverage.
 func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	modRoot := moduleRoot(t)
 
@@ -1130,8 +1005,8 @@ func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	// are marked as covered (Count=1); everything else is absent
 	// and defaults to 0% coverage.
 	profileContent := "mode: set\n" +
-		"github.com/unbound-force/gaze/internal/crap/crap.go:89.39,92.2 2 1\n" +
-		"github.com/unbound-force/gaze/internal/crap/crap.go:97.57,105.2 3 1\n"
+		coveredBody(t, modRoot, "Formula") +
+		coveredBody(t, modRoot, "ClassifyQuadrant")
 
 	profileFile := filepath.Join(t.TempDir(), "cover.out")
 	if err := os.WriteFile(profileFile, []byte(profileContent), 0o644); err != nil {
@@ -1139,7 +1014,7 @@ func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	}
 
 	opts := DefaultOptions()
-	opts.CoverProfile = profileFile
+	opts.CoverProfiles = []string{profileFile}
 
 	report, err := Analyze([]string{"./internal/crap"}, modRoot, opts)
 	if err != nil {
@@ -1191,23 +1066,179 @@ func TestAnalyze_WithPrebuiltProfile(t *testing.T) {
 	}
 }
 
+// writeModule writes files, keyed by slash-separated path, to a new
+// temporary directory and returns it.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// emptyProfile writes a coverage profile without blocks so that
+// Analyze does not spawn go test.
+func emptyProfile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(path, []byte("mode: set\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestAnalyze_FunctionIdentity verifies that scores carry the
+// FunctionID of the loaded package, so that same-named packages are
+// told apart, and that files excluded by build constraints and
+// functions without bodies are not scored.
+func TestAnalyze_FunctionIdentity(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":           "module example.com/app\n\ngo 1.21\n",
+		"a/store/store.go": "package store\n\nfunc Open() {}\n",
+		"b/store/store.go": "package store\n\nfunc Open() {}\n\ntype Bar struct{}\n\nfunc (b Bar) Method2() int { return 0 }\n\ntype Service interface{ DoWork() }\n",
+		"b/store/cache.go": "package store\n\ntype Cache[K comparable, V any] struct{}\n\nfunc (c *Cache[K, V]) Get() {}\n",
+		"b/store/never.go": "//go:build never\n\npackage store\n\nfunc Tagged() {}\n",
+	})
+
+	opts := DefaultOptions()
+	opts.CoverProfiles = []string{emptyProfile(t)}
+	report, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	got := make(map[taxonomy.FunctionID]string)
+	for _, s := range report.Scores {
+		got[s.FunctionID] = s.Function
+	}
+	want := map[taxonomy.FunctionID]string{
+		"example.com/app/a/store.Open":               "Open",
+		"example.com/app/b/store.Open":               "Open",
+		"example.com/app/b/store.(Bar).Method2":      "(Bar).Method2",
+		"example.com/app/b/store.(*Cache[K, V]).Get": "(*Cache[K, V]).Get",
+	}
+	if len(got) != len(want) {
+		t.Errorf("scored %d functions, want %d: %v", len(got), len(want), got)
+	}
+	for id, fn := range want {
+		if got[id] != fn {
+			t.Errorf("function %s = %q, want %q", id, got[id], fn)
+		}
+	}
+}
+
+// TestAnalyze_PackageErrors verifies that a package that fails
+// type-checking is reported on Stderr rather than silently left out
+// of the scores, and that the other packages are still scored.
+func TestAnalyze_PackageErrors(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":        "module example.com/app\n\ngo 1.21\n",
+		"good/good.go":  "package good\n\nfunc Fine() {}\n",
+		"broken/bad.go": "package broken\n\nfunc Bad() int { return \"x\" }\n",
+	})
+
+	var stderr bytes.Buffer
+	opts := DefaultOptions()
+	opts.CoverProfiles = []string{emptyProfile(t)}
+	opts.Stderr = &stderr
+	report, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(report.Scores) != 1 || report.Scores[0].FunctionID != "example.com/app/good.Fine" {
+		t.Errorf("scores = %v, want only example.com/app/good.Fine", report.Scores)
+	}
+	warning := stderr.String()
+	if !strings.Contains(warning, "example.com/app/broken") || !strings.Contains(warning, "bad.go") {
+		t.Errorf("stderr = %q, want a warning naming example.com/app/broken and its error", warning)
+	}
+}
+
+// TestAnalyze_ComplexityMetric verifies that the Complexity option
+// selects the metric used for scoring and is recorded in the
+// summary, and that unknown metrics are rejected.
+func TestAnalyze_ComplexityMetric(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/kinds\n\ngo 1.21\n",
+		"kinds.go": `package kinds
+
+func Kind(s string) int {
+	switch s {
+	case "a":
+		return 1
+	case "b":
+		return 2
+	case "c":
+		return 3
+	case "d":
+		return 4
+	}
+	return 0
+}
+`,
+	})
+	profile := emptyProfile(t)
+
+	tests := []struct {
+		metric ComplexityMetric
+		want   int
+	}{
+		{Cyclomatic, 5},
+		{Cognitive, 1},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			opts := DefaultOptions()
+			opts.CoverProfiles = []string{profile}
+			opts.Complexity = tt.metric
+			report, err := Analyze([]string{"."}, dir, opts)
+			if err != nil {
+				t.Fatalf("Analyze failed: %v", err)
+			}
+			if len(report.Scores) != 1 || report.Scores[0].Complexity != tt.want {
+				t.Fatalf("scores = %+v, want one score with complexity %d", report.Scores, tt.want)
+			}
+			if report.Summary.ComplexityMetric != tt.metric {
+				t.Errorf("Summary.ComplexityMetric = %q, want %q", report.Summary.ComplexityMetric, tt.metric)
+			}
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		opts := DefaultOptions()
+		opts.CoverProfiles = []string{profile}
+		opts.Complexity = "halstead"
+		_, err := Analyze([]string{"."}, dir, opts)
+		if err == nil || !strings.Contains(err.Error(), "invalid complexity metric") {
+			t.Errorf("expected invalid complexity metric error, got %v", err)
+		}
+	})
+}
+
 // TestAnalyze_ContractCoverageFunc verifies that Analyze populates
 // GazeCRAP, ContractCoverage, and Quadrant when ContractCoverageFunc
 // is provided.
 func TestAnalyze_ContractCoverageFunc(t *testing.T) {
 	modRoot := moduleRoot(t)
 
-	profileContent := "mode: set\n" +
-		"github.com/unbound-force/gaze/internal/crap/crap.go:89.39,92.2 2 1\n"
+	profileContent := "mode: set\n" + coveredBody(t, modRoot, "Formula")
 	profileFile := filepath.Join(t.TempDir(), "cover.out")
 	if err := os.WriteFile(profileFile, []byte(profileContent), 0o644); err != nil {
 		t.Fatalf("writing cover profile: %v", err)
 	}
 
 	opts := DefaultOptions()
-	opts.CoverProfile = profileFile
-	opts.ContractCoverageFunc = func(pkg, function string) (float64, bool) {
-		if function == "Formula" {
+	opts.CoverProfiles = []string{profileFile}
+	opts.ContractCoverageFunc = func(id taxonomy.FunctionID) (float64, bool) {
+		if id == "github.com/unbound-force/gaze/internal/crap.Formula" {
 			return 80.0, true
 		}
 		return 0, false
@@ -1268,6 +1299,17 @@ func GeneratedFunc() int { return 42 }
 		t.Fatal(err)
 	}
 
+	// A "Code generated" comment after the package clause does not
+	// mark the file as generated.
+	lateSrc := `package gentest
+
+// Code generated by something. DO NOT EDIT.
+func Baz() {}
+`
+	if err := os.WriteFile(filepath.Join(dir, "late.go"), []byte(lateSrc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
 	// Write a minimal (empty) cover profile so Analyze doesn't
 	// spawn a subprocess.
 	profileContent := "mode: set\n"
@@ -1277,7 +1319,7 @@ func GeneratedFunc() int { return 42 }
 	}
 
 	opts := DefaultOptions()
-	opts.CoverProfile = profileFile
+	opts.CoverProfiles = []string{profileFile}
 	opts.IgnoreGenerated = true
 
 	report, err := Analyze([]string{"."}, dir, opts)
@@ -1285,11 +1327,18 @@ func GeneratedFunc() int { return 42 }
 		t.Fatalf("Analyze failed: %v", err)
 	}
 
+	var bazFound bool
 	for _, s := range report.Scores {
-		if s.Function == "GeneratedFunc" {
+		switch s.Function {
+		case "GeneratedFunc":
 			t.Error("GeneratedFunc should be excluded when IgnoreGenerated=true")
+		case "Baz":
+			bazFound = true
 		}
 	}
+	if !bazFound {
+		t.Error("Baz should be scored: its Code generated comment follows the package clause")
+	}
 }
 
 // ---------------------------------------------------------------------------
@@ -1437,3 +1486,402 @@ func TestWriteText_WorstOffendersSection(t *testing.T) {
 		t.Errorf("expected '420.0' CRAP score, got:\n%s", out)
 	}
 }
+
+// --- LineCoverage tests ---
+
+func TestParseLineCoverage_Executed(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src := filepath.Join(dir, "a.go")
+	if err := os.WriteFile(src, []byte("package m\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Lines 3-5 ran; the error branch on lines 6-8 did not.
+	profileContent := "mode: set\n" +
+		"example.com/m/a.go:3.30,5.16 2 1\n" +
+		"example.com/m/a.go:6.3,8.4 1 0\n" +
+		"example.com/m/a.go:9.2,9.12 1 1\n"
+	profileFile := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(profileFile, []byte(profileContent), 0o644); err != nil {
+		t.Fatalf("writing cover profile: %v", err)
+	}
+
+	lc, err := ParseLineCoverage([]string{profileFile}, dir)
+	if err != nil {
+		t.Fatalf("ParseLineCoverage failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		file     string
+		line     int
+		executed bool
+		known    bool
+	}{
+		{"statement in executed block", src, 4, true, true},
+		{"statement in unexecuted block", src, 7, false, true},
+		{"signature judged by next block", src, 2, true, true},
+		{"line after every block", src, 20, false, false},
+		{"file not in profile", filepath.Join(dir, "b.go"), 4, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executed, known := lc.Executed(tt.file, tt.line)
+			if executed != tt.executed || known != tt.known {
+				t.Errorf("Executed(%d) = (%v, %v), want (%v, %v)",
+					tt.line, executed, known, tt.executed, tt.known)
+			}
+		})
+	}
+}
+
+func TestLineCoverage_NilReceiver(t *testing.T) {
+	var lc *LineCoverage
+	if executed, known := lc.Executed("a.go", 1); executed || known {
+		t.Errorf("nil LineCoverage: Executed = (%v, %v), want (false, false)", executed, known)
+	}
+}
+
+// writeProfile writes a coverage profile with the given content to a
+// new temporary file and returns its path.
+func writeProfile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "cover.out")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("writing cover profile: %v", err)
+	}
+	return path
+}
+
+func TestReadProfiles_MergesBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		first    string
+		second   string
+		wantMode string
+		want     []int // counts of the merged blocks, in source order
+	}{
+		{
+			name:     "set mode combines",
+			first:    "mode: set\nm/a.go:3.1,5.2 2 1\nm/a.go:6.1,8.2 1 0\n",
+			second:   "mode: set\nm/a.go:3.1,5.2 2 1\nm/a.go:6.1,8.2 1 0\n",
+			wantMode: "set",
+			want:     []int{1, 0},
+		},
+		{
+			name:     "count mode sums",
+			first:    "mode: count\nm/a.go:3.1,5.2 2 4\nm/a.go:6.1,8.2 1 0\n",
+			second:   "mode: count\nm/a.go:3.1,5.2 2 3\nm/a.go:6.1,8.2 1 2\n",
+			wantMode: "count",
+			want:     []int{7, 2},
+		},
+		{
+			name:     "mixed modes count",
+			first:    "mode: set\nm/a.go:3.1,5.2 2 1\n",
+			second:   "mode: atomic\nm/a.go:3.1,5.2 2 5\n",
+			wantMode: "atomic",
+			want:     []int{6},
+		},
+		{
+			name:     "distinct blocks are kept in order",
+			first:    "mode: set\nm/a.go:6.1,8.2 1 1\n",
+			second:   "mode: set\nm/a.go:3.1,5.2 2 0\n",
+			wantMode: "set",
+			want:     []int{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profiles, err := ReadProfiles(writeProfile(t, tt.first), writeProfile(t, tt.second))
+			if err != nil {
+				t.Fatalf("ReadProfiles failed: %v", err)
+			}
+			if len(profiles) != 1 {
+				t.Fatalf("got %d profiles, want 1", len(profiles))
+			}
+			if profiles[0].Mode != tt.wantMode {
+				t.Errorf("Mode = %q, want %q", profiles[0].Mode, tt.wantMode)
+			}
+			var got []int
+			for _, b := range profiles[0].Blocks {
+				got = append(got, b.Count)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("block counts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadProfiles_InvalidProfile(t *testing.T) {
+	_, err := ReadProfiles(writeProfile(t, "not a profile\n"))
+	if err == nil {
+		t.Error("expected error for malformed profile")
+	}
+}
+
+// TestAnalyze_MultipleProfiles verifies that coverage from several
+// profiles is merged: each function counts as covered by whichever
+// profile executed it.
+func TestAnalyze_MultipleProfiles(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/tiers\n\ngo 1.21\n",
+		"tiers.go": `package tiers
+
+func Unit() int {
+	return 1
+}
+
+func Integration() int {
+	return 2
+}
+`,
+	})
+	// The unit profile ran Unit only; the integration profile, built
+	// with -coverpkg, ran Integration only.
+	unit := writeProfile(t, "mode: set\n"+
+		"example.com/tiers/tiers.go:3.17,5.2 1 1\n"+
+		"example.com/tiers/tiers.go:7.24,9.2 1 0\n")
+	integration := writeProfile(t, "mode: set\n"+
+		"example.com/tiers/tiers.go:3.17,5.2 1 0\n"+
+		"example.com/tiers/tiers.go:7.24,9.2 1 1\n")
+
+	opts := DefaultOptions()
+	opts.CoverProfiles = []string{unit, integration}
+	report, err := Analyze([]string{"."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(report.Scores) != 2 {
+		t.Fatalf("got %d scores, want 2", len(report.Scores))
+	}
+	for _, s := range report.Scores {
+		if s.LineCoverage != 100 {
+			t.Errorf("%s: LineCoverage = %.1f%%, want 100%%", s.Function, s.LineCoverage)
+		}
+	}
+}
+
+// TestAnalyze_CoverData verifies that binary coverage data written
+// to GOCOVERDIR by a binary built with -cover is converted and
+// scored.
+func TestAnalyze_CoverData(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.com/bin\n\ngo 1.21\n",
+		"main.go": `package main
+
+func main() {
+	Used()
+}
+
+func Used() int {
+	return 1
+}
+
+func Unused() int {
+	return 2
+}
+`,
+	})
+	bin := filepath.Join(t.TempDir(), "bin")
+	build := exec.Command("go", "build", "-cover", "-o", bin, ".")
+	build.Dir = dir
+	if out, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("go build -cover: %v\n%s", err, out)
+	}
+	covDir := t.TempDir()
+	run := exec.Command(bin)
+	run.Env = append(os.Environ(), "GOCOVERDIR="+covDir)
+	if out, err := run.CombinedOutput(); err != nil {
+		t.Fatalf("running instrumented binary: %v\n%s", err, out)
+	}
+
+	opts := DefaultOptions()
+	opts.CoverDataDirs = []string{covDir}
+	report, err := Analyze([]string{"."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	want := map[string]float64{"main": 100, "Used": 100, "Unused": 0}
+	for _, s := range report.Scores {
+		if pct, ok := want[s.Function]; ok && s.LineCoverage != pct {
+			t.Errorf("%s: LineCoverage = %.1f%%, want %.0f%%", s.Function, s.LineCoverage, pct)
+		}
+	}
+	if len(report.Scores) != len(want) {
+		t.Errorf("got %d scores, want %d", len(report.Scores), len(want))
+	}
+}
+
+// TestAnalyze_CoverDataNotDirectory verifies that Analyze rejects a
+// coverage data path that is not a directory.
+func TestAnalyze_CoverDataNotDirectory(t *testing.T) {
+	opts := DefaultOptions()
+	opts.CoverDataDirs = []string{emptyProfile(t)}
+
+	_, err := Analyze([]string{"./internal/crap"}, moduleRoot(t), opts)
+	if err == nil || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("expected not-a-directory error, got: %v", err)
+	}
+}
+
+// TestAnalyze_CoverDataComma verifies that Analyze rejects a coverage
+// data directory whose path contains a comma, which go tool covdata
+// would split into two inputs.
+func TestAnalyze_CoverDataComma(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "unit,e2e")
+	if err := os.Mkdir(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultOptions()
+	opts.CoverDataDirs = []string{dir}
+
+	_, err := Analyze([]string{"./internal/crap"}, moduleRoot(t), opts)
+	if err == nil || !strings.Contains(err.Error(), "containing a comma") {
+		t.Errorf("expected comma error, got: %v", err)
+	}
+}
+
+// --- go test invocation tests ---
+
+func TestGoTestArgs(t *testing.T) {
+	got := goTestArgs(config.GoTestConfig{
+		Tags:     []string{"integration", "sqlite"},
+		Race:     true,
+		Timeout:  5 * time.Minute,
+		Count:    1,
+		CoverPkg: "./...",
+		Run:      "^TestStore",
+	})
+	want := []string{"-tags=integration,sqlite", "-race", "-timeout=5m0s", "-count=1", "-coverpkg=./...", "-run=^TestStore"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("goTestArgs = %v, want %v", got, want)
+	}
+	if got := goTestArgs(config.GoTestConfig{}); len(got) != 0 {
+		t.Errorf("goTestArgs(zero) = %v, want none", got)
+	}
+}
+
+func TestParseTestFailures(t *testing.T) {
+	events := `{"Action":"start","Package":"example.com/exp/bad"}
+{"Action":"output","Package":"example.com/exp/bad","Test":"TestB/sub","Output":"    b_test.go:5: boom\n"}
+{"Action":"fail","Package":"example.com/exp/bad","Test":"TestB/sub"}
+{"Action":"output","Package":"example.com/exp/bad","Test":"TestB","Output":"--- FAIL: TestB (0.00s)\n"}
+{"Action":"fail","Package":"example.com/exp/bad","Test":"TestB"}
+{"Action":"output","Package":"example.com/exp/bad","Output":"FAIL\n"}
+{"Action":"fail","Package":"example.com/exp/bad"}
+{"ImportPath":"example.com/exp/broken [example.com/exp/broken.test]","Action":"build-output","Output":"broken/x_test.go:5:28: undefined: undefined\n"}
+{"ImportPath":"example.com/exp/broken [example.com/exp/broken.test]","Action":"build-fail"}
+{"Action":"fail","Package":"example.com/exp/broken","FailedBuild":"example.com/exp/broken [example.com/exp/broken.test]"}
+{"Action":"output","Package":"example.com/exp/panics","Output":"panic: boom in TestMain\n"}
+{"Action":"fail","Package":"example.com/exp/panics"}
+not a JSON line
+{"Action":"pass","Package":"example.com/exp/good"}
+`
+	got := parseTestFailures(strings.NewReader(events))
+	want := []TestFailure{
+		{Package: "example.com/exp/bad", Tests: []string{"TestB/sub"}, Output: "    b_test.go:5: boom\n"},
+		{Package: "example.com/exp/broken", Output: "broken/x_test.go:5:28: undefined: undefined\n"},
+		{Package: "example.com/exp/panics", Output: "panic: boom in TestMain\n"},
+	}
+	if fmt.Sprintf("%+v", got) != fmt.Sprintf("%+v", want) {
+		t.Errorf("parseTestFailures =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+// TestAnalyze_GoTestFailures runs the generated go test in a module
+// where one package's tests fail and another's do not build. Both
+// are reported and left out of the scores, while the passing
+// package, which needs the configured build tag and environment
+// variable, is scored with its coverage.
+func TestAnalyze_GoTestFailures(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":    "module example.com/exp\n\ngo 1.21\n",
+		"good/g.go": "package good\n\nfunc G() int { return 1 }\n",
+		"good/g_test.go": `package good
+
+import (
+	"os"
+	"testing"
+)
+
+func TestEnv(t *testing.T) {
+	if os.Getenv("GAZE_TEST_ENV") != "on" {
+		t.Fatal("GAZE_TEST_ENV not set")
+	}
+}
+`,
+		"good/tagged_test.go": "//go:build extra\n\npackage good\n\nimport \"testing\"\n\nfunc TestG(t *testing.T) { G() }\n",
+		"bad/b.go":            "package bad\n\nfunc B() int { return 1 }\n",
+		"bad/b_test.go": `package bad
+
+import "testing"
+
+func TestB(t *testing.T) {
+	B()
+	t.Run("sub", func(t *testing.T) { t.Fail() })
+}
+`,
+		"broken/x.go":      "package broken\n\nfunc X() int { return 1 }\n",
+		"broken/x_test.go": "package broken\n\nimport \"testing\"\n\nfunc TestX(t *testing.T) { undefined() }\n",
+	})
+
+	var stderr bytes.Buffer
+	opts := DefaultOptions()
+	opts.Stderr = &stderr
+	opts.GoTest = config.GoTestConfig{
+		Tags:  []string{"extra"},
+		Count: 1,
+		Env:   map[string]string{"GAZE_TEST_ENV": "on"},
+	}
+	report, err := Analyze([]string{"./..."}, dir, opts)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+
+	if len(report.Scores) != 1 || report.Scores[0].Function != "G" || report.Scores[0].LineCoverage != 100 {
+		t.Errorf("scores = %+v, want only G at 100%% coverage", report.Scores)
+	}
+	if len(report.TestFailures) != 2 {
+		t.Fatalf("TestFailures = %+v, want bad and broken", report.TestFailures)
+	}
+	failures := make(map[string]TestFailure)
+	for _, f := range report.TestFailures {
+		failures[f.Package] = f
+	}
+	if bad := failures["example.com/exp/bad"]; strings.Join(bad.Tests, ",") != "TestB/sub" {
+		t.Errorf("bad failure = %+v, want TestB/sub", bad)
+	}
+	if broken := failures["example.com/exp/broken"]; !strings.Contains(broken.Output, "undefined") {
+		t.Errorf("broken failure = %+v, want its build output", broken)
+	}
+	if !strings.Contains(stderr.String(), "tests failed in 2 package(s)") {
+		t.Errorf("stderr = %q, want a test failure warning", stderr.String())
+	}
+}
+
+func TestWriteText_TestFailures(t *testing.T) {
+	rpt := &Report{
+		TestFailures: []TestFailure{
+			{Package: "example.com/exp/bad", Tests: []string{"TestB/sub", "TestC"}},
+			{Package: "example.com/exp/broken", Output: "# example.com/exp/broken\nx_test.go:5:28: undefined: undefined\n"},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteText(&buf, rpt); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	out := stripANSI(buf.String())
+	for _, want := range []string{
+		"--- Test Failures (packages not scored) ---",
+		"example.com/exp/bad  TestB/sub, TestC",
+		"    x_test.go:5:28: undefined: undefined",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -29,6 +29,7 @@ func WriteText(w io.Writer, rpt *Report) error {
 
 	if len(rpt.Scores) == 0 {
 		_, _ = fmt.Fprintln(w, styles.Muted.Render("No functions analyzed."))
+		writeTestFailures(w, styles, rpt.TestFailures)
 		return nil
 	}
 
@@ -83,7 +84,11 @@ func WriteText(w io.Writer, rpt *Report) error {
 	_, _ = fmt.Fprintln(w)
 	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Summary ---"))
 	_, _ = fmt.Fprintf(w, "%s  %d\n", styles.SummaryLabel.Render("Functions analyzed:"), rpt.Summary.TotalFunctions)
-	_, _ = fmt.Fprintf(w, "%s  %.1f\n", styles.SummaryLabel.Render("Avg complexity:"), rpt.Summary.AvgComplexity)
+	avgComplexity := fmt.Sprintf("%.1f", rpt.Summary.AvgComplexity)
+	if rpt.Summary.ComplexityMetric == Cognitive {
+		avgComplexity += styles.Muted.Render(" (cognitive)")
+	}
+	_, _ = fmt.Fprintf(w, "%s  %s\n", styles.SummaryLabel.Render("Avg complexity:"), avgComplexity)
 	_, _ = fmt.Fprintf(w, "%s  %.1f%%\n", styles.SummaryLabel.Render("Avg line coverage:"), rpt.Summary.AvgLineCoverage)
 	_, _ = fmt.Fprintf(w, "%s  %.1f\n", styles.SummaryLabel.Render("Avg CRAP score:"), rpt.Summary.AvgCRAP)
 	_, _ = fmt.Fprintf(w, "%s  %.0f\n", styles.SummaryLabel.Render("CRAP threshold:"), rpt.Summary.CRAPThreshold)
@@ -132,9 +137,34 @@ func WriteText(w io.Writer, rpt *Report) error {
 		}
 	}
 
+	writeTestFailures(w, styles, rpt.TestFailures)
+
 	return nil
 }
 
+// writeTestFailures lists the packages whose tests failed while
+// generating coverage, with their failing tests, or with the output
+// of a package that failed outside any test, such as a build error.
+func writeTestFailures(w io.Writer, styles report.Styles, failures []TestFailure) {
+	if len(failures) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, styles.Header.Render("--- Test Failures (packages not scored) ---"))
+	for _, f := range failures {
+		if len(f.Tests) > 0 {
+			_, _ = fmt.Fprintf(w, "  %s  %s\n", styles.CRAPBad.Render(f.Package), strings.Join(f.Tests, ", "))
+			continue
+		}
+		_, _ = fmt.Fprintf(w, "  %s\n", styles.CRAPBad.Render(f.Package))
+		for _, line := range strings.Split(strings.TrimRight(f.Output, "\n"), "\n") {
+			if line != "" {
+				_, _ = fmt.Fprintf(w, "    %s\n", styles.Muted.Render(line))
+			}
+		}
+	}
+}
+
 // shortenPath removes common Go module path prefixes and returns
 // a shorter relative-looking path.
 func shortenPath(path string) string {
@@ -0,0 +1,98 @@
+// Package crap computes CRAP scores for Go functions.
+package crap
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+// testEvent is an event of go test -json output (see go doc
+// test2json). Build output is reported by ImportPath, which names
+// the failed build of a package's FailedBuild.
+type testEvent struct {
+	Action      string
+	Package     string
+	Test        string
+	Output      string
+	ImportPath  string
+	FailedBuild string
+}
+
+// parseTestFailures reads go test -json output and returns the
+// packages that failed, in order of failure. Lines that are not JSON
+// events are ignored.
+func parseTestFailures(r io.Reader) []TestFailure {
+	type testKey struct{ pkg, test string }
+	output := make(map[testKey]*strings.Builder)
+	buildOutput := make(map[string]*strings.Builder)
+	appendTo := func(b *strings.Builder, s string) *strings.Builder {
+		if b == nil {
+			b = new(strings.Builder)
+		}
+		b.WriteString(s)
+		return b
+	}
+
+	var failures []TestFailure
+	index := make(map[string]int)
+	failure := func(pkg string) *TestFailure {
+		i, ok := index[pkg]
+		if !ok {
+			i = len(failures)
+			index[pkg] = i
+			failures = append(failures, TestFailure{Package: pkg})
+		}
+		return &failures[i]
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var ev testEvent
+		if json.Unmarshal(scanner.Bytes(), &ev) != nil {
+			continue
+		}
+		switch ev.Action {
+		case "build-output":
+			buildOutput[ev.ImportPath] = appendTo(buildOutput[ev.ImportPath], ev.Output)
+		case "output":
+			key := testKey{ev.Package, ev.Test}
+			output[key] = appendTo(output[key], ev.Output)
+		case "fail":
+			f := failure(ev.Package)
+			switch {
+			case ev.Test != "":
+				// Subtests fail before their parents; a parent adds
+				// nothing once one of its subtests is recorded.
+				if hasSubtest(f.Tests, ev.Test) {
+					continue
+				}
+				f.Tests = append(f.Tests, ev.Test)
+				if b := output[testKey{ev.Package, ev.Test}]; b != nil {
+					f.Output += b.String()
+				}
+			case ev.FailedBuild != "":
+				if b := buildOutput[ev.FailedBuild]; b != nil {
+					f.Output += b.String()
+				}
+			case len(f.Tests) == 0:
+				if b := output[testKey{ev.Package, ""}]; b != nil {
+					f.Output += b.String()
+				}
+			}
+		}
+	}
+	return failures
+}
+
+// hasSubtest reports whether tests contains a subtest of test.
+func hasSubtest(tests []string, test string) bool {
+	for _, t := range tests {
+		if strings.HasPrefix(t, test+"/") {
+			return true
+		}
+	}
+	return false
+}
@@ -71,6 +71,10 @@ type ModuleResult struct {
 	// Packages is the list of all loaded packages in the module.
 	Packages []*packages.Package
 
+	// Failed lists the packages excluded from Packages because of
+	// load or type errors, with the errors in their Errors field.
+	Failed []*packages.Package
+
 	// Fset is the shared file set for position information.
 	Fset *token.FileSet
 }
@@ -83,15 +87,23 @@ type ModuleResult struct {
 // Returns a *ModuleResult containing the valid (error-free) packages
 // and their shared FileSet, or an error if package loading fails or
 // all packages have errors. Packages with individual errors are
-// silently excluded from the result.
+// excluded from Packages and listed in Failed.
 func LoadModule(dir string) (*ModuleResult, error) {
+	return LoadPatterns(dir, "./...")
+}
+
+// LoadPatterns loads the packages matching the given patterns,
+// resolved relative to dir, with the same rules as LoadModule:
+// packages with errors are excluded, and an error is returned only
+// if loading fails or no package is free of errors.
+func LoadPatterns(dir string, patterns ...string) (*ModuleResult, error) {
 	cfg := &packages.Config{
 		Mode:  LoadMode,
 		Tests: false,
 		Dir:   dir,
 	}
 
-	pkgs, err := packages.Load(cfg, "./...")
+	pkgs, err := packages.Load(cfg, patterns...)
 	if err != nil {
 		return nil, fmt.Errorf("loading module packages: %w", err)
 	}
@@ -101,10 +113,12 @@ func LoadModule(dir string) (*ModuleResult, error) {
 	}
 
 	// Collect only packages without errors.
-	var valid []*packages.Package
+	var valid, failed []*packages.Package
 	var fset *token.FileSet
 	for _, pkg := range pkgs {
-		if len(pkg.Errors) == 0 {
+		if len(pkg.Errors) > 0 {
+			failed = append(failed, pkg)
+		} else {
 			valid = append(valid, pkg)
 			if fset == nil {
 				fset = pkg.Fset
@@ -118,6 +132,7 @@ func LoadModule(dir string) (*ModuleResult, error) {
 
 	return &ModuleResult{
 		Packages: valid,
+		Failed:   failed,
 		Fset:     fset,
 	}, nil
 }
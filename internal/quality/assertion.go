@@ -40,6 +40,27 @@ const (
 	// (e.g., if diff := cmp.Diff(want, got); diff != "").
 	AssertionKindGoCmpDiff AssertionKind = "gocmp_diff"
 
+	// AssertionKindExampleOutput is the "// Output:" comment of an
+	// Example function, or a value the example prints to stdout for
+	// that comment to check (e.g., fmt.Println(got)).
+	AssertionKindExampleOutput AssertionKind = "example_output"
+
+	// AssertionKindProperty is an invariant checked over generated
+	// inputs: an assertion inside an f.Fuzz or rapid.Check function,
+	// a quick.Check property result, or a quick.CheckEqual call.
+	AssertionKindProperty AssertionKind = "property"
+
+	// AssertionKindMockExpectation is a gomock or testify/mock
+	// expectation on a call to an injected dependency (e.g.,
+	// repo.EXPECT().Save(gomock.Any()) or m.On("Save", ...)).
+	AssertionKindMockExpectation AssertionKind = "mock_expectation"
+
+	// AssertionKindErrorMatch is an errors.Is or errors.As check on a
+	// returned error (e.g., if !errors.Is(err, ErrNotFound) {...} or
+	// assert.ErrorIs(t, err, ErrNotFound)), which asserts that the
+	// error chain is preserved through wrapping.
+	AssertionKindErrorMatch AssertionKind = "error_match"
+
 	// AssertionKindUnknown is an unrecognized assertion pattern.
 	AssertionKindUnknown AssertionKind = "unknown"
 )
@@ -62,8 +83,17 @@ type AssertionSite struct {
 	Depth int
 
 	// Expr is the comparison or call expression that constitutes
-	// the assertion.
+	// the assertion. It is nil for an example's "// Output:" comment,
+	// which asserts on everything the example writes to stdout.
 	Expr ast.Expr
+
+	// MockMethod is the dependency method a mock expectation
+	// expects to be called. Empty for other kinds.
+	MockMethod string
+
+	// Strict marks a mock expectation that also pins the call count
+	// or call order (Times, Once, InOrder, AssertNumberOfCalls).
+	Strict bool
 }
 
 // DetectAssertions walks the test function's AST looking for
@@ -80,8 +110,103 @@ func DetectAssertions(
 		maxDepth:  maxDepth,
 		visited:   make(map[string]bool),
 		funcDecls: buildFuncDeclIndex(pkg),
+		verified:  verifiedMocks(testDecl.Body),
+	}
+	if isExampleFunction(testDecl) {
+		return d.detectExampleOutput(testDecl)
+	}
+	return d.markPropertySites(testDecl, d.detect(testDecl, 0))
+}
+
+// detectExampleOutput returns the assertion sites of an Example
+// function: its "// Output:" comment, plus every value it prints to
+// stdout, since go test compares that output against the comment.
+// Examples without an output comment are never run and yield none.
+func (d *assertionDetector) detectExampleOutput(fn *ast.FuncDecl) []AssertionSite {
+	var output *ast.CommentGroup
+	for _, file := range d.pkg.Syntax {
+		if file.Pos() <= fn.Pos() && fn.Pos() < file.End() {
+			output = exampleOutputComment(file, fn)
+			break
+		}
+	}
+	if output == nil {
+		return nil
+	}
+
+	sites := []AssertionSite{{
+		Location: d.posString(output.Pos()),
+		Kind:     AssertionKindExampleOutput,
+		FuncDecl: fn,
+	}}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if ok && d.isStdoutPrint(call) && !d.printsOnlyLiterals(call) {
+			sites = append(sites, AssertionSite{
+				Location: d.posString(call.Pos()),
+				Kind:     AssertionKindExampleOutput,
+				FuncDecl: fn,
+				Expr:     call,
+			})
+		}
+		return true
+	})
+	return sites
+}
+
+// printsOnlyLiterals reports whether every printed argument is a
+// literal (e.g., fmt.Println("---")), which checks no computed value.
+// The writer argument of an fmt.Fprint* call is not a printed value.
+func (d *assertionDetector) printsOnlyLiterals(call *ast.CallExpr) bool {
+	for i, arg := range call.Args {
+		if i == 0 && d.osStream(arg) != "" {
+			continue
+		}
+		if _, ok := arg.(*ast.BasicLit); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// isStdoutPrint reports whether call prints to stdout: fmt.Print,
+// fmt.Printf, fmt.Println, or an fmt.Fprint* call on os.Stdout.
+func (d *assertionDetector) isStdoutPrint(call *ast.CallExpr) bool {
+	if d.pkg.TypesInfo == nil {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	fn, ok := d.pkg.TypesInfo.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "fmt" {
+		return false
+	}
+	switch fn.Name() {
+	case "Print", "Printf", "Println":
+		return true
+	case "Fprint", "Fprintf", "Fprintln":
+		return len(call.Args) > 0 && d.osStream(call.Args[0]) == "Stdout"
+	}
+	return false
+}
+
+// osStream returns "Stdout" or "Stderr" when x refers to os.Stdout
+// or os.Stderr, and "" otherwise.
+func (d *assertionDetector) osStream(x ast.Expr) string {
+	sel, ok := x.(*ast.SelectorExpr)
+	if !ok || d.pkg.TypesInfo == nil {
+		return ""
+	}
+	v, ok := d.pkg.TypesInfo.Uses[sel.Sel].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Pkg().Path() != "os" {
+		return ""
+	}
+	if v.Name() == "Stdout" || v.Name() == "Stderr" {
+		return v.Name()
 	}
-	return d.detect(testDecl, 0)
+	return ""
 }
 
 // assertionDetector holds state for the assertion detection walk.
@@ -91,6 +216,7 @@ type assertionDetector struct {
 	maxDepth  int
 	visited   map[string]bool          // prevents infinite recursion
 	funcDecls map[string]*ast.FuncDecl // cached function declarations by name
+	verified  map[string]bool          // testify mocks the test verifies
 }
 
 // detect walks a function declaration for assertion patterns.
@@ -111,16 +237,25 @@ func (d *assertionDetector) detect(fn *ast.FuncDecl, depth int) []AssertionSite
 	ast.Inspect(fn.Body, func(n ast.Node) bool {
 		switch node := n.(type) {
 		case *ast.IfStmt:
-			// Check for stdlib assertion patterns:
+			// Check for error chain checks, then stdlib assertion
+			// patterns:
+			// if !errors.Is(err, ErrX) { t.Errorf(...) }
 			// if got != want { t.Errorf(...) }
 			// if err != nil { t.Fatal(err) }
-			if site := d.detectStdlibAssertion(node, fn, depth); site != nil {
+			if site := d.detectErrorMatch(node, fn, depth); site != nil {
+				sites = append(sites, *site)
+			} else if site := d.detectStdlibAssertion(node, fn, depth); site != nil {
 				sites = append(sites, *site)
 			}
 
 		case *ast.ExprStmt:
-			// Check for testify/go-cmp assertion calls.
+			// Check for mock expectations, then testify/go-cmp
+			// assertion calls.
 			if call, ok := node.X.(*ast.CallExpr); ok {
+				if mockSites := d.detectMockExpectations(call, fn, depth); len(mockSites) > 0 {
+					sites = append(sites, mockSites...)
+					return false
+				}
 				if site := d.detectCallAssertion(call, fn, depth); site != nil {
 					sites = append(sites, *site)
 				}
@@ -154,13 +289,24 @@ func (d *assertionDetector) detect(fn *ast.FuncDecl, depth int) []AssertionSite
 }
 
 // detectStdlibAssertion checks if an if-statement is an assertion
-// pattern like "if got != want { t.Errorf(...) }" or
-// "if err != nil { t.Fatal(err) }".
+// pattern like "if got != want { t.Errorf(...) }",
+// "if err != nil { t.Fatal(err) }", or
+// "if !reflect.DeepEqual(got, want) { t.Errorf(...) }".
 func (d *assertionDetector) detectStdlibAssertion(
 	ifStmt *ast.IfStmt,
 	fn *ast.FuncDecl,
 	depth int,
 ) *AssertionSite {
+	if call := deepEqualCall(ifStmt.Cond); call != nil && d.bodyContainsTestFail(ifStmt.Body) {
+		return &AssertionSite{
+			Location: d.posString(ifStmt.Pos()),
+			Kind:     AssertionKindStdlibComparison,
+			FuncDecl: fn,
+			Depth:    depth,
+			Expr:     call,
+		}
+	}
+
 	// Must have a binary comparison condition.
 	binExpr, ok := ifStmt.Cond.(*ast.BinaryExpr)
 	if !ok {
@@ -188,6 +334,27 @@ func (d *assertionDetector) detectStdlibAssertion(
 	}
 }
 
+// deepEqualCall returns the reflect.DeepEqual call of a negated
+// condition such as "!reflect.DeepEqual(got, want)".
+func deepEqualCall(cond ast.Expr) *ast.CallExpr {
+	not, ok := ast.Unparen(cond).(*ast.UnaryExpr)
+	if !ok || not.Op != token.NOT {
+		return nil
+	}
+	call, ok := ast.Unparen(not.X).(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 {
+		return nil
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "DeepEqual" {
+		return nil
+	}
+	if pkg, ok := sel.X.(*ast.Ident); !ok || pkg.Name != "reflect" {
+		return nil
+	}
+	return call
+}
+
 // isErrorNilCheck checks if a binary expression is "err != nil" or
 // "err == nil" (or the reverse "nil != err").
 func isErrorNilCheck(expr *ast.BinaryExpr) bool {
@@ -308,10 +475,12 @@ func (d *assertionDetector) classifyTestifyCall(method string) AssertionKind {
 		"FileExists", "NoFileExists", "DirExists", "NoDirExists":
 		return AssertionKindTestifyEqual
 
-	case "NoError", "Error", "ErrorIs", "ErrorAs",
-		"ErrorContains", "EqualError":
+	case "NoError", "Error", "ErrorContains", "EqualError":
 		return AssertionKindTestifyNoError
 
+	case "ErrorIs", "NotErrorIs", "ErrorAs", "NotErrorAs":
+		return AssertionKindErrorMatch
+
 	case "Nil", "NotNil":
 		return AssertionKindTestifyNilCheck
 
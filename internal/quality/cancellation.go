@@ -0,0 +1,49 @@
+package quality
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// mapCancellationCheck maps an assertion that compares against
+// context.Canceled or context.DeadlineExceeded, such as
+// errors.Is(err, context.Canceled) or assert.ErrorIs(t, err,
+// context.DeadlineExceeded), to the target's RespectsCancellation
+// effects. A test only gets that error back by cancelling the context
+// it passed in, so the check asserts that the target stops when told
+// to.
+func mapCancellationCheck(
+	site AssertionSite,
+	effects []taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	if site.Expr == nil || testPkg == nil || testPkg.TypesInfo == nil ||
+		!referencesCancellationError(site.Expr, testPkg.TypesInfo) {
+		return nil
+	}
+	covered := filterEffectsByType(effects, taxonomy.RespectsCancellation)
+	return mappingsForSite(site, covered, 70)
+}
+
+// referencesCancellationError reports whether expr refers to
+// context.Canceled or context.DeadlineExceeded.
+func referencesCancellationError(expr ast.Node, info *types.Info) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || found {
+			return !found
+		}
+		v, ok := info.Uses[sel.Sel].(*types.Var)
+		if ok && v.Pkg() != nil && v.Pkg().Path() == "context" &&
+			(v.Name() == "Canceled" || v.Name() == "DeadlineExceeded") {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
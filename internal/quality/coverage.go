@@ -1,6 +1,8 @@
 package quality
 
 import (
+	"sort"
+
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
 
@@ -19,11 +21,16 @@ func ComputeContractCoverage(
 	effects []taxonomy.SideEffect,
 	mappings []taxonomy.AssertionMapping,
 ) taxonomy.ContractCoverage {
-	// Build a set of asserted side effect IDs.
+	// Build a set of asserted side effect IDs, and of those asserted
+	// by a property-based invariant.
 	assertedIDs := make(map[string]bool, len(mappings))
+	propertyIDs := make(map[string]bool)
 	for _, m := range mappings {
 		if m.SideEffectID != "" {
 			assertedIDs[m.SideEffectID] = true
+			if m.AssertionType == taxonomy.AssertionProperty {
+				propertyIDs[m.SideEffectID] = true
+			}
 		}
 	}
 
@@ -32,6 +39,7 @@ func ComputeContractCoverage(
 	var coveredCount int
 	var gaps []taxonomy.SideEffect
 	var gapHints []string
+	var propertyCovered []string
 
 	for _, e := range effects {
 		// Skip ambiguous effects — they are excluded from the metric.
@@ -50,6 +58,9 @@ func ComputeContractCoverage(
 
 		if assertedIDs[e.ID] {
 			coveredCount++
+			if propertyIDs[e.ID] {
+				propertyCovered = append(propertyCovered, e.ID)
+			}
 		} else {
 			gaps = append(gaps, e)
 			gapHints = append(gapHints, hintForEffect(e))
@@ -68,5 +79,192 @@ func ComputeContractCoverage(
 		TotalContractual: totalContractual,
 		Gaps:             gaps,
 		GapHints:         gapHints,
+		PropertyCovered:  propertyCovered,
+	}
+}
+
+// BuildFunctionCoverage computes function-centric contract coverage
+// by unioning the assertions of every test that targets a function.
+// An effect is covered when any test asserts on it, so a function
+// whose error return is checked in one test and whose value return
+// is checked in another reaches 100%.
+//
+// The effects a report covers are the target's contractual effects
+// minus that report's gaps. The asserted domain values and struct
+// fields of each effect are likewise unioned across reports.
+// Functions no report targets are omitted. The result is sorted by
+// package, then qualified name.
+func BuildFunctionCoverage(
+	results []taxonomy.AnalysisResult,
+	reports []taxonomy.QualityReport,
+) []taxonomy.FunctionCoverage {
+	byTarget := make(map[taxonomy.FunctionID][]taxonomy.QualityReport)
+	for _, r := range reports {
+		key := r.TargetFunction.FunctionID()
+		byTarget[key] = append(byTarget[key], r)
+	}
+
+	var out []taxonomy.FunctionCoverage
+	for _, result := range results {
+		targeting := byTarget[result.Target.FunctionID()]
+		if len(targeting) == 0 {
+			continue
+		}
+
+		coveredBy := make(map[string][]string)
+		assertedValues := make(map[string]map[string]bool)
+		assertedFields := make(map[string]map[string]bool)
+		var tests []string
+		for _, r := range targeting {
+			tests = appendUnique(tests, r.TestFunction)
+			for _, vc := range r.ValueCoverage {
+				if assertedValues[vc.SideEffectID] == nil {
+					assertedValues[vc.SideEffectID] = make(map[string]bool)
+				}
+				for _, v := range vc.Asserted {
+					assertedValues[vc.SideEffectID][v] = true
+				}
+			}
+			for _, fc := range r.FieldCoverage {
+				if assertedFields[fc.SideEffectID] == nil {
+					assertedFields[fc.SideEffectID] = make(map[string]bool)
+				}
+				for _, f := range fc.Asserted {
+					assertedFields[fc.SideEffectID][f] = true
+				}
+			}
+			gapIDs := make(map[string]bool, len(r.ContractCoverage.Gaps))
+			for _, g := range r.ContractCoverage.Gaps {
+				gapIDs[g.ID] = true
+			}
+			for _, e := range result.SideEffects {
+				if isContractual(e) && !gapIDs[e.ID] {
+					coveredBy[e.ID] = appendUnique(coveredBy[e.ID], r.TestFunction)
+				}
+			}
+		}
+		sort.Strings(tests)
+
+		// Reuse the per-test metric on the union of covered effects
+		// so percentages, gaps, and hints are computed identically.
+		var mappings []taxonomy.AssertionMapping
+		effects := make([]taxonomy.EffectCoverage, 0, len(result.SideEffects))
+		for _, e := range result.SideEffects {
+			if !isContractual(e) {
+				continue
+			}
+			covering := coveredBy[e.ID]
+			sort.Strings(covering)
+			if len(covering) > 0 {
+				mappings = append(mappings, taxonomy.AssertionMapping{SideEffectID: e.ID})
+			}
+			ec := taxonomy.EffectCoverage{
+				SideEffectID: e.ID,
+				Type:         e.Type,
+				CoveredBy:    covering,
+			}
+			for _, d := range e.Domain {
+				ec.Values = append(ec.Values, d.String())
+				if assertedValues[e.ID][d.String()] {
+					ec.AssertedValues = append(ec.AssertedValues, d.String())
+				}
+			}
+			if e.Type == taxonomy.ReturnValue || e.Type == taxonomy.ReceiverMutation {
+				ec.Fields = effectFields(e)
+			}
+			for _, f := range ec.Fields {
+				if assertedFields[e.ID][f] {
+					ec.AssertedFields = append(ec.AssertedFields, f)
+				}
+			}
+			effects = append(effects, ec)
+		}
+
+		out = append(out, taxonomy.FunctionCoverage{
+			Function:         result.Target,
+			ContractCoverage: ComputeContractCoverage(result.SideEffects, mappings),
+			Effects:          effects,
+			Tests:            tests,
+		})
+	}
+
+	sortFunctionCoverage(out)
+	return out
+}
+
+// BuildUntestedContracts lists the functions in results that have
+// contractual effects but are targeted by no report. Each entry
+// carries 0% coverage with every contractual effect as a gap, so
+// callers can render it like any other function coverage.
+func BuildUntestedContracts(
+	results []taxonomy.AnalysisResult,
+	reports []taxonomy.QualityReport,
+) []taxonomy.FunctionCoverage {
+	tested := make(map[taxonomy.FunctionID]bool, len(reports))
+	for _, r := range reports {
+		tested[r.TargetFunction.FunctionID()] = true
+	}
+
+	var out []taxonomy.FunctionCoverage
+	for _, result := range results {
+		if tested[result.Target.FunctionID()] {
+			continue
+		}
+		var effects []taxonomy.EffectCoverage
+		for _, e := range result.SideEffects {
+			if isContractual(e) {
+				ec := taxonomy.EffectCoverage{SideEffectID: e.ID, Type: e.Type}
+				for _, d := range e.Domain {
+					ec.Values = append(ec.Values, d.String())
+				}
+				if e.Type == taxonomy.ReturnValue || e.Type == taxonomy.ReceiverMutation {
+					ec.Fields = effectFields(e)
+				}
+				effects = append(effects, ec)
+			}
+		}
+		if len(effects) == 0 {
+			continue
+		}
+		out = append(out, taxonomy.FunctionCoverage{
+			Function:         result.Target,
+			ContractCoverage: ComputeContractCoverage(result.SideEffects, nil),
+			Effects:          effects,
+		})
+	}
+
+	sortFunctionCoverage(out)
+	return out
+}
+
+// sortFunctionCoverage orders entries by package, then qualified
+// name (SC-004 determinism).
+func sortFunctionCoverage(fcs []taxonomy.FunctionCoverage) {
+	sort.SliceStable(fcs, func(i, j int) bool {
+		if fcs[i].Function.Package != fcs[j].Function.Package {
+			return fcs[i].Function.Package < fcs[j].Function.Package
+		}
+		return fcs[i].Function.QualifiedName() < fcs[j].Function.QualifiedName()
+	})
+}
+
+// isContractual reports whether an effect counts toward Contract
+// Coverage: unclassified effects are treated as contractual, while
+// ambiguous and incidental ones are excluded.
+func isContractual(e taxonomy.SideEffect) bool {
+	if e.Classification == nil {
+		return true
+	}
+	return e.Classification.Label != taxonomy.Ambiguous &&
+		e.Classification.Label != taxonomy.Incidental
+}
+
+// appendUnique appends s to list unless it is already present.
+func appendUnique(list []string, s string) []string {
+	for _, v := range list {
+		if v == s {
+			return list
+		}
 	}
+	return append(list, s)
 }
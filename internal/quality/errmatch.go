@@ -0,0 +1,162 @@
+package quality
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// detectErrorMatch checks if an if-statement fails the test on the
+// result of errors.Is or errors.As:
+//
+//	if !errors.Is(err, ErrNotFound) { t.Errorf(...) }
+//	if errors.As(err, &pathErr) != tt.wantPathErr { t.Fatal(...) }
+//
+// The site's expression is the errors.Is or errors.As call.
+func (d *assertionDetector) detectErrorMatch(
+	ifStmt *ast.IfStmt,
+	fn *ast.FuncDecl,
+	depth int,
+) *AssertionSite {
+	call := d.errorMatchCall(ifStmt.Cond)
+	if call == nil || !d.bodyContainsTestFail(ifStmt.Body) {
+		return nil
+	}
+	return &AssertionSite{
+		Location: d.posString(ifStmt.Pos()),
+		Kind:     AssertionKindErrorMatch,
+		FuncDecl: fn,
+		Depth:    depth,
+		Expr:     call,
+	}
+}
+
+// errorMatchCall returns the errors.Is or errors.As call tested by an
+// if condition: the whole condition, its negation, or one side of an
+// == or != comparison. A call combined with other conditions by && or
+// || is not returned, since it may merely exclude a case
+// (err != nil && !errors.Is(err, io.EOF)) rather than assert a match.
+func (d *assertionDetector) errorMatchCall(cond ast.Expr) *ast.CallExpr {
+	switch c := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		if c.Op == token.NOT {
+			return d.errorMatchCall(c.X)
+		}
+	case *ast.BinaryExpr:
+		if c.Op != token.EQL && c.Op != token.NEQ {
+			return nil
+		}
+		for _, side := range []ast.Expr{c.X, c.Y} {
+			if call, ok := ast.Unparen(side).(*ast.CallExpr); ok && d.isErrorsMatchFunc(call) {
+				return call
+			}
+		}
+	case *ast.CallExpr:
+		if d.isErrorsMatchFunc(c) {
+			return c
+		}
+	}
+	return nil
+}
+
+// isErrorsMatchFunc reports whether call is errors.Is or errors.As
+// from the standard library.
+func (d *assertionDetector) isErrorsMatchFunc(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Is" && sel.Sel.Name != "As") {
+		return false
+	}
+	if d.pkg.TypesInfo == nil {
+		ident, ok := sel.X.(*ast.Ident)
+		return ok && ident.Name == "errors"
+	}
+	fn, ok := d.pkg.TypesInfo.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && fn.Pkg().Path() == "errors"
+}
+
+// mapErrorMatch maps an errors.Is or errors.As check. Like any other
+// assertion it covers the effect whose value it checks; when that is
+// the target's returned error, matching through the error chain also
+// asserts the target's ErrorWrap effects of the matched error, since
+// the check only passes if that error was wrapped rather than
+// flattened. errors.Is(err, ErrX) asserts the wraps of ErrX, and
+// errors.As the wraps of all other errors, which, unlike sentinels,
+// can carry a typed error.
+func mapErrorMatch(
+	site AssertionSite,
+	effects []taxonomy.SideEffect,
+	objToEffectID map[types.Object]string,
+	effectMap map[string]*taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	mapping := matchAssertionToEffect(site, objToEffectID, effectMap, testPkg)
+	if mapping == nil {
+		return nil
+	}
+	mappings := []taxonomy.AssertionMapping{*mapping}
+	if effect := effectMap[mapping.SideEffectID]; effect != nil && effect.Type == taxonomy.ErrorReturn {
+		var wraps []taxonomy.SideEffect
+		for _, e := range filterEffectsByType(effects, taxonomy.ErrorWrap) {
+			if errorMatchWraps(site, e, testPkg) {
+				wraps = append(wraps, e)
+			}
+		}
+		mappings = append(mappings, mappingsForSite(site, wraps, mapping.Confidence)...)
+	}
+	return mappings
+}
+
+// errorMatchWraps reports whether the errors.Is or errors.As call of
+// site matches an upstream error of the ErrorWrap effect wrap, whose
+// target lists the upstream errors' origins: sentinel variables by
+// name ("ErrNotFound", or "store.ErrNotFound" from another package),
+// callees by function ("os.Open"), and other variables by name
+// ("err"). The sentinels among them are listed in wrap.Sentinels.
+func errorMatchWraps(site AssertionSite, wrap taxonomy.SideEffect, testPkg *packages.Package) bool {
+	call, ok := site.Expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 2 || testPkg == nil || testPkg.TypesInfo == nil {
+		return false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	sentinel := sentinelVar(call.Args[1], testPkg.TypesInfo)
+	for _, origin := range strings.Split(wrap.Target, ", ") {
+		switch {
+		case sel.Sel.Name == "As":
+			if !slices.Contains(wrap.Sentinels, origin) {
+				return true
+			}
+		case sentinel != nil:
+			if origin == sentinel.Name() || origin == sentinel.Pkg().Name()+"."+sentinel.Name() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sentinelVar returns the package-level variable x refers to, or nil.
+func sentinelVar(x ast.Expr, info *types.Info) *types.Var {
+	var id *ast.Ident
+	switch x := ast.Unparen(x).(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return nil
+	}
+	v, ok := info.Uses[id].(*types.Var)
+	if !ok || v.Pkg() == nil || v.Parent() != v.Pkg().Scope() {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,268 @@
+package quality
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// computeFieldCoverage reports, for each covered ReturnValue of
+// struct type and for the receiver's mutated fields, which of the
+// fields the function sets the test's assertions read. A test that
+// only checks cfg.Name covers NewConfig's ReturnValue, yet asserts
+// one of the fields the constructor fills:
+//
+//	if cfg.Name != "x" { ... }               // Name
+//	assert.Equal(t, 8080, got.Port)          // Port
+//	if diff := cmp.Diff(want, got); ...      // every field
+//
+// Deep-equality assertions on the whole value (reflect.DeepEqual,
+// cmp.Diff, assert.Equal, or == on a struct) read every field.
+// Field reads are followed through locals (`name := cfg.Name`).
+func computeFieldCoverage(
+	testPkg *packages.Package,
+	target taxonomy.FunctionTarget,
+	sites []AssertionSite,
+	mappings []taxonomy.AssertionMapping,
+	effects []taxonomy.SideEffect,
+) []taxonomy.FieldCoverage {
+	if testPkg == nil || testPkg.TypesInfo == nil {
+		return nil
+	}
+
+	// The receiver's mutations share one traced variable, so an
+	// assertion mapped to any of them may read the fields of all.
+	byEffect := make(map[string]bool)
+	receiverMapped := false
+	for _, m := range mappings {
+		byEffect[m.SideEffectID] = true
+	}
+	for _, e := range effects {
+		if e.Type == taxonomy.ReceiverMutation && byEffect[e.ID] {
+			receiverMapped = true
+		}
+	}
+
+	sitesByLoc := make(map[string][]AssertionSite, len(sites))
+	for _, site := range sites {
+		sitesByLoc[site.Location] = append(sitesByLoc[site.Location], site)
+	}
+	// sitesFor returns the assertion sites mapped to any effect
+	// accepted by match.
+	sitesFor := func(match func(taxonomy.SideEffect) bool) []AssertionSite {
+		ids := make(map[string]bool)
+		for _, e := range effects {
+			if match(e) {
+				ids[e.ID] = true
+			}
+		}
+		var out []AssertionSite
+		seen := make(map[string]bool)
+		for _, m := range mappings {
+			if ids[m.SideEffectID] && !seen[m.AssertionLocation] {
+				seen[m.AssertionLocation] = true
+				out = append(out, sitesByLoc[m.AssertionLocation]...)
+			}
+		}
+		return out
+	}
+
+	var receiverReads *fieldReads
+	var out []taxonomy.FieldCoverage
+	for _, e := range effects {
+		if !isContractual(e) {
+			continue
+		}
+		var reads *fieldReads
+		switch {
+		case e.Type == taxonomy.ReturnValue && len(e.Fields) > 0 && byEffect[e.ID]:
+			id := e.ID
+			reads = readFields(testPkg.TypesInfo, baseTypeName(e.Target),
+				sitesFor(func(x taxonomy.SideEffect) bool { return x.ID == id }))
+		case e.Type == taxonomy.ReceiverMutation && receiverMapped:
+			if receiverReads == nil {
+				receiverReads = readFields(testPkg.TypesInfo, baseTypeName(target.Receiver),
+					sitesFor(func(x taxonomy.SideEffect) bool { return x.Type == taxonomy.ReceiverMutation }))
+			}
+			reads = receiverReads
+		default:
+			continue
+		}
+
+		fc := taxonomy.FieldCoverage{
+			SideEffectID: e.ID,
+			Type:         e.Type,
+			Fields:       effectFields(e),
+		}
+		for _, f := range fc.Fields {
+			if reads.covers(f) {
+				fc.Asserted = append(fc.Asserted, f)
+			}
+		}
+		out = append(out, fc)
+	}
+	return out
+}
+
+// effectFields returns the fields an effect sets. A ReceiverMutation
+// of a non-struct field is the field itself.
+func effectFields(e taxonomy.SideEffect) []string {
+	if len(e.Fields) == 0 && e.Type == taxonomy.ReceiverMutation {
+		return []string{e.Target}
+	}
+	return e.Fields
+}
+
+// baseTypeName strips pointers and package qualifiers from a type
+// string: "*store.Config" becomes "Config".
+func baseTypeName(typ string) string {
+	typ = strings.TrimLeft(typ, "*")
+	if i := strings.LastIndex(typ, "."); i >= 0 {
+		typ = typ[i+1:]
+	}
+	return typ
+}
+
+// fieldReads is the set of field paths of one struct type that a
+// group of assertions reads.
+type fieldReads struct {
+	whole bool
+	paths []string
+}
+
+// covers reports whether the reads include field, a dotted path
+// from the struct root. Reading a field's parent or one of its
+// nested fields counts.
+func (r *fieldReads) covers(field string) bool {
+	if r.whole {
+		return true
+	}
+	for _, p := range r.paths {
+		if p == field || strings.HasPrefix(field, p+".") || strings.HasPrefix(p, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// readFields collects the fields of the struct type named typeName
+// that the assertions at sites read.
+func readFields(info *types.Info, typeName string, sites []AssertionSite) *fieldReads {
+	r := &fieldReads{}
+	if typeName == "" {
+		return r
+	}
+	for _, site := range sites {
+		if site.Expr == nil {
+			continue
+		}
+		exprs := []ast.Expr{site.Expr}
+		for i := 0; i < len(exprs) && i < 8; i++ {
+			ast.Inspect(exprs[i], func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.Ident:
+					if rhs := definingExpr(site.FuncDecl, info, info.Uses[node]); rhs != nil {
+						exprs = append(exprs, rhs)
+					}
+				case *ast.SelectorExpr:
+					if path, ok := fieldPath(node, info, typeName); ok {
+						r.paths = append(r.paths, path)
+						return false
+					}
+				}
+				return true
+			})
+		}
+		if comparesWhole(site.Expr, info, typeName) {
+			r.whole = true
+		}
+	}
+	return r
+}
+
+// fieldPath returns the dotted field path sel reads from a value of
+// the struct type named typeName (got.Limits.Max gives
+// "Limits.Max").
+func fieldPath(sel *ast.SelectorExpr, info *types.Info, typeName string) (string, bool) {
+	var names []string
+	for {
+		s, ok := info.Selections[sel]
+		if !ok || s.Kind() != types.FieldVal {
+			return "", false
+		}
+		names = append([]string{sel.Sel.Name}, names...)
+		inner, ok := ast.Unparen(sel.X).(*ast.SelectorExpr)
+		if !ok || !isFieldSelection(inner, info) {
+			return strings.Join(names, "."), namedBase(s.Recv()) == typeName
+		}
+		sel = inner
+	}
+}
+
+// isFieldSelection reports whether sel selects a struct field.
+func isFieldSelection(sel *ast.SelectorExpr, info *types.Info) bool {
+	s, ok := info.Selections[sel]
+	return ok && s.Kind() == types.FieldVal
+}
+
+// comparesWhole reports whether expr is a deep-equality assertion
+// with an operand of the struct type named typeName.
+func comparesWhole(expr ast.Expr, info *types.Info, typeName string) bool {
+	var operands []ast.Expr
+	allowPointer := true
+	switch e := ast.Unparen(expr).(type) {
+	case *ast.BinaryExpr:
+		if e.Op != token.EQL && e.Op != token.NEQ {
+			return false
+		}
+		// == on pointers compares identity, not contents.
+		operands, allowPointer = []ast.Expr{e.X, e.Y}, false
+	case *ast.CallExpr:
+		var name string
+		switch fun := e.Fun.(type) {
+		case *ast.SelectorExpr:
+			name = fun.Sel.Name
+		case *ast.Ident:
+			name = fun.Name
+		}
+		switch name {
+		case "DeepEqual", "Diff", "Equal", "EqualValues", "Exactly",
+			"EqualExportedValues", "ObjectsAreEqual":
+			operands = e.Args
+		}
+	case *ast.UnaryExpr:
+		// !reflect.DeepEqual(got, want)
+		return comparesWhole(e.X, info, typeName)
+	}
+
+	for _, op := range operands {
+		t := info.TypeOf(op)
+		if t == nil {
+			continue
+		}
+		if _, isPtr := t.(*types.Pointer); isPtr && !allowPointer {
+			continue
+		}
+		if namedBase(t) == typeName {
+			return true
+		}
+	}
+	return false
+}
+
+// namedBase returns the name of t's named type, looking through one
+// pointer, or "" when t is not named.
+func namedBase(t types.Type) string {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	if named, ok := t.(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
@@ -0,0 +1,90 @@
+package quality
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/analysis"
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// mapGRPCStatusCheck maps an assertion on the gRPC status code of an
+// error to the target's GRPCStatusCode effects:
+//
+//	if status.Code(err) != codes.NotFound { ... }
+//	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+//	st, _ := status.FromError(err); if st.Code() != codes.InvalidArgument { ... }
+//
+// A check against a codes constant covers the effect for that code;
+// one against a variable (tt.wantCode) covers every code the target
+// can return. The check usually also reads the returned error, so it
+// is mapped in addition to the ErrorReturn it covers.
+func mapGRPCStatusCheck(
+	site AssertionSite,
+	effects []taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	if site.Expr == nil || testPkg == nil || testPkg.TypesInfo == nil {
+		return nil
+	}
+	info := testPkg.TypesInfo
+
+	// Read through locals such as `got := status.Code(err)`.
+	exprs := []ast.Expr{site.Expr}
+	ast.Inspect(site.Expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if rhs := definingExpr(site.FuncDecl, info, info.Uses[ident]); rhs != nil {
+				exprs = append(exprs, rhs)
+			}
+		}
+		return true
+	})
+
+	checksCode := false
+	var codes []string
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.CallExpr:
+				if isGRPCCodeCall(node, info) {
+					checksCode = true
+				}
+			case *ast.SelectorExpr:
+				if c, ok := info.Uses[node.Sel].(*types.Const); ok && c.Pkg() != nil && analysis.IsGRPCPackage(c.Pkg(), "codes") {
+					codes = append(codes, c.Name())
+					return false
+				}
+			}
+			return true
+		})
+	}
+	if !checksCode {
+		return nil
+	}
+
+	confidence := 75
+	if len(codes) == 0 {
+		confidence = 65
+	}
+	var covered []taxonomy.SideEffect
+	for _, e := range filterEffectsByType(effects, taxonomy.GRPCStatusCode) {
+		if len(codes) == 0 || slices.Contains(codes, e.Target) || !analysis.IsGRPCCode(e.Target) {
+			covered = append(covered, e)
+		}
+	}
+	return mappingsForSite(site, covered, confidence)
+}
+
+// isGRPCCodeCall reports whether call reads a gRPC status code:
+// status.Code(err) or (*status.Status).Code().
+func isGRPCCodeCall(call *ast.CallExpr, info *types.Info) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Code" {
+		return false
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	return ok && fn.Pkg() != nil && analysis.IsGRPCPackage(fn.Pkg(), "status")
+}
@@ -2,6 +2,8 @@ package quality
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/unbound-force/gaze/internal/taxonomy"
 )
@@ -59,6 +61,19 @@ func hintForEffect(e taxonomy.SideEffect) string {
 	case taxonomy.HTTPResponseWrite:
 		return "// assert HTTP response status and body after calling target()"
 
+	case taxonomy.HTTPStatusCode:
+		if _, err := strconv.Atoi(target); err == nil {
+			return fmt.Sprintf("rec := httptest.NewRecorder(); target(rec, req); if rec.Code != %s { t.Errorf(\"status = %%d, want %s\", rec.Code) }",
+				target, target)
+		}
+		return "rec := httptest.NewRecorder(); target(rec, req) // assert rec.Code == the expected status"
+
+	case taxonomy.HTTPHeader:
+		if target != "" {
+			return fmt.Sprintf("rec := httptest.NewRecorder(); target(rec, req) // assert rec.Header().Get(%q)", target)
+		}
+		return "rec := httptest.NewRecorder(); target(rec, req) // assert the header via rec.Header().Get"
+
 	case taxonomy.ChannelSend:
 		if target != "" {
 			return fmt.Sprintf("// assert value sent on %s after calling target()", target)
@@ -71,11 +86,59 @@ func hintForEffect(e taxonomy.SideEffect) string {
 		}
 		return "// assert channel is closed after calling target()"
 
+	case taxonomy.ErrorWrap:
+		if target != "" {
+			return fmt.Sprintf("if !errors.Is(err, want) { t.Errorf(\"error chain lost: %%v\", err) } // want: the error from %s (errors.As for typed errors)",
+				target)
+		}
+		return "if !errors.Is(err, want) { t.Errorf(\"error chain lost: %v\", err) } // want: the wrapped error (errors.As for typed errors)"
+
+	case taxonomy.RespectsCancellation:
+		return "ctx, cancel := context.WithCancel(context.Background()); cancel(); err := target(ctx) // assert errors.Is(err, context.Canceled)"
+
+	case taxonomy.GRPCStatusCode:
+		if target != "" {
+			return fmt.Sprintf("if status.Code(err) != codes.%s { t.Errorf(\"got %%v, want %s\", err) }", target, target)
+		}
+		return "if status.Code(err) != codes.Expected { t.Errorf(\"got %v\", err) }"
+
 	case taxonomy.DeferredReturnMutation:
 		return "// assert named return value after calling target() (check via defer or named returns)"
 
-	// P2-P4 — Generic fallback for less common effect types.
+	// P2 — Interactions are asserted through the dependency's mock.
+	case taxonomy.InterfaceInteraction:
+		if iface, method, ok := strings.Cut(target, "."); ok {
+			return fmt.Sprintf("mock%s.EXPECT().%s(gomock.Any()) // or m.On(%q, ...) with m.AssertExpectations(t)",
+				iface, method, method)
+		}
+		return "// assert the call on the mocked dependency after calling target()"
+
+	// P2 — Network effects are asserted against a test double of the
+	// remote end.
+	case taxonomy.NetworkDial:
+		return "ln, _ := net.Listen(\"tcp\", \"127.0.0.1:0\") // point target() at ln.Addr() and assert the accepted connection"
+
+	case taxonomy.HTTPRequest:
+		return "srv := httptest.NewServer(handler) // point target() at srv.URL and assert the request the handler received"
+
+	case taxonomy.RPCCall:
+		if target != "" {
+			return fmt.Sprintf("// serve a fake over bufconn (or mock the client) and assert the %s request", target)
+		}
+		return "// serve a fake over bufconn (or mock the client) and assert the RPC request"
+
+	case taxonomy.MessagePublish:
+		return "// inject a fake producer and assert the published message's topic, key, and payload"
+
+	case taxonomy.MetricEmit:
+		return "// usually incidental; if contractual: testutil.ToFloat64(counter) == expected after calling target()"
+
+	// P2-P4 — Generic fallback for less common effect types, and
+	// custom types from .gaze.yaml detection rules.
 	default:
+		if !taxonomy.IsBuiltin(e.Type) && target != "" {
+			return fmt.Sprintf("// assert the %s effect of the %s call made by target()", e.Type, target)
+		}
 		return fmt.Sprintf("// assert %s side effect of target()", e.Type)
 	}
 }
@@ -28,6 +28,11 @@ func TestHintForEffect_AllTypes(t *testing.T) {
 		taxonomy.ChannelSend,
 		taxonomy.ChannelClose,
 		taxonomy.DeferredReturnMutation,
+		taxonomy.ErrorWrap,
+		taxonomy.RespectsCancellation,
+		taxonomy.HTTPStatusCode,
+		taxonomy.HTTPHeader,
+		taxonomy.GRPCStatusCode,
 		// P2
 		taxonomy.FileSystemWrite,
 		taxonomy.FileSystemDelete,
@@ -39,6 +44,11 @@ func TestHintForEffect_AllTypes(t *testing.T) {
 		taxonomy.CallbackInvocation,
 		taxonomy.LogWrite,
 		taxonomy.ContextCancellation,
+		taxonomy.NetworkDial,
+		taxonomy.HTTPRequest,
+		taxonomy.RPCCall,
+		taxonomy.MessagePublish,
+		taxonomy.MetricEmit,
 		// P3
 		taxonomy.StdoutWrite,
 		taxonomy.StderrWrite,
@@ -90,6 +100,15 @@ func TestHintForEffect_SpecificValues(t *testing.T) {
 		{taxonomy.WriterOutput, "w", "written to w"},
 		{taxonomy.ChannelSend, "ch", "sent on ch"},
 		{taxonomy.ChannelClose, "done", "done is closed"},
+		{taxonomy.ErrorWrap, "os.Open", "errors.Is(err, want)"},
+		{taxonomy.RespectsCancellation, "ctx", "errors.Is(err, context.Canceled)"},
+		{taxonomy.HTTPStatusCode, "404", "rec.Code != 404"},
+		{taxonomy.HTTPHeader, "Content-Type", `rec.Header().Get("Content-Type")`},
+		{taxonomy.GRPCStatusCode, "NotFound", "status.Code(err) != codes.NotFound"},
+		{taxonomy.HTTPRequest, "Client.Do", "httptest.NewServer"},
+		{taxonomy.RPCCall, "GreeterClient.SayHello", "GreeterClient.SayHello request"},
+		{taxonomy.MetricEmit, "Counter.Inc", "testutil.ToFloat64"},
+		{taxonomy.SideEffectType("EventPublish"), "Producer.Send", "EventPublish effect of the Producer.Send call"},
 	}
 
 	for _, tc := range cases {
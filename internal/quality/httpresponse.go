@@ -0,0 +1,196 @@
+package quality
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// mapHTTPResponseCheck maps an assertion on a recorded HTTP response
+// to the handler's HTTPStatusCode and HTTPHeader effects. Handlers
+// return nothing, so these assertions read the response through the
+// httptest.ResponseRecorder (or an *http.Response) rather than a
+// traced return value:
+//
+//	if rec.Code != http.StatusNotFound { ... }
+//	assert.Equal(t, 200, rec.Result().StatusCode)
+//	if got := rec.Header().Get("Content-Type"); got != "application/json" { ... }
+//
+// A status check that compares against a constant covers the effect
+// for that code; one that compares against a variable (tt.wantCode)
+// covers every status the handler can produce. A header check covers
+// the effect for the header key it reads. Either also covers the
+// matching HTTPResponseWrite effect (w.WriteHeader or w.Header).
+func mapHTTPResponseCheck(
+	site AssertionSite,
+	effects []taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	if site.Expr == nil || testPkg == nil || testPkg.TypesInfo == nil {
+		return nil
+	}
+	info := testPkg.TypesInfo
+
+	// Read through locals such as `got := rec.Code` so that the
+	// comparison `got != 200` is recognized.
+	exprs := []ast.Expr{site.Expr}
+	ast.Inspect(site.Expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok {
+			if rhs := definingExpr(site.FuncDecl, info, info.Uses[ident]); rhs != nil {
+				exprs = append(exprs, rhs)
+			}
+		}
+		return true
+	})
+
+	var checksStatus, checksHeader bool
+	var codes []int
+	var headers []string
+	for _, expr := range exprs {
+		ast.Inspect(expr, func(n ast.Node) bool {
+			if e, ok := n.(ast.Expr); ok {
+				if tv, ok := info.Types[e]; ok && tv.Value != nil && tv.Value.Kind() == constant.Int {
+					if v, ok := constant.Int64Val(tv.Value); ok && v >= 100 && v <= 599 {
+						codes = append(codes, int(v))
+					}
+					return false
+				}
+			}
+			switch node := n.(type) {
+			case *ast.SelectorExpr:
+				if isRecordedStatus(node, info) {
+					checksStatus = true
+					return false
+				}
+				if isRecordedHeader(node, info) {
+					checksHeader = true
+				}
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if ok && (sel.Sel.Name == "Get" || sel.Sel.Name == "Values") &&
+					len(node.Args) == 1 && isHTTPHeaderType(info.TypeOf(sel.X)) {
+					checksHeader = true
+					if tv, ok := info.Types[node.Args[0]]; ok && tv.Value != nil && tv.Value.Kind() == constant.String {
+						headers = append(headers, http.CanonicalHeaderKey(constant.StringVal(tv.Value)))
+					}
+					return false
+				}
+			}
+			return true
+		})
+	}
+	if !checksStatus && !checksHeader {
+		return nil
+	}
+
+	confidence := 75
+	var covered []taxonomy.SideEffect
+	for _, e := range effects {
+		switch e.Type {
+		case taxonomy.HTTPStatusCode:
+			if !checksStatus {
+				continue
+			}
+			// A non-numeric target is a status the detector could not
+			// fold, which may be any of the codes checked.
+			if n, err := strconv.Atoi(e.Target); err != nil || len(codes) == 0 || slices.Contains(codes, n) {
+				covered = append(covered, e)
+			}
+		case taxonomy.HTTPHeader:
+			if checksHeader && (len(headers) == 0 || slices.Contains(headers, e.Target)) {
+				covered = append(covered, e)
+			}
+		case taxonomy.HTTPResponseWrite:
+			if (checksStatus && strings.HasSuffix(e.Target, ".WriteHeader")) ||
+				(checksHeader && strings.HasSuffix(e.Target, ".Header")) {
+				covered = append(covered, e)
+			}
+		}
+	}
+	if (checksStatus && len(codes) == 0) || (checksHeader && len(headers) == 0) {
+		confidence = 65
+	}
+	return mappingsForSite(site, covered, confidence)
+}
+
+// isRecordedStatus reports whether sel reads a response status:
+// ResponseRecorder.Code or http.Response.StatusCode.
+func isRecordedStatus(sel *ast.SelectorExpr, info *types.Info) bool {
+	switch sel.Sel.Name {
+	case "Code":
+		return isNamedType(info.TypeOf(sel.X), "net/http/httptest", "ResponseRecorder")
+	case "StatusCode":
+		return isNamedType(info.TypeOf(sel.X), "net/http", "Response")
+	}
+	return false
+}
+
+// isRecordedHeader reports whether sel reads a response's headers as
+// a whole: rec.Header() or resp.Header.
+func isRecordedHeader(sel *ast.SelectorExpr, info *types.Info) bool {
+	return sel.Sel.Name == "Header" &&
+		(isNamedType(info.TypeOf(sel.X), "net/http/httptest", "ResponseRecorder") ||
+			isNamedType(info.TypeOf(sel.X), "net/http", "Response"))
+}
+
+// isHTTPHeaderType reports whether t is net/http.Header.
+func isHTTPHeaderType(t types.Type) bool {
+	return isNamedType(t, "net/http", "Header")
+}
+
+// isNamedType reports whether t, or the type it points to, is the
+// named type pkgPath.name.
+func isNamedType(t types.Type, pkgPath, name string) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+	return named.Obj().Pkg().Path() == pkgPath && named.Obj().Name() == name
+}
+
+// definingExpr returns the expression that initializes obj when obj
+// is a local declared by a single-valued `:=` or var statement in fn.
+func definingExpr(fn *ast.FuncDecl, info *types.Info, obj types.Object) ast.Expr {
+	if fn == nil || fn.Body == nil || obj == nil {
+		return nil
+	}
+	var rhs ast.Expr
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if rhs != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			if len(node.Lhs) != len(node.Rhs) {
+				return true
+			}
+			for i, lhs := range node.Lhs {
+				if ident, ok := lhs.(*ast.Ident); ok && info.Defs[ident] == obj {
+					rhs = node.Rhs[i]
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) != len(node.Values) {
+				return true
+			}
+			for i, name := range node.Names {
+				if info.Defs[name] == obj {
+					rhs = node.Values[i]
+				}
+			}
+		}
+		return true
+	})
+	return rhs
+}
@@ -5,6 +5,7 @@ import (
 	"go/token"
 	"go/types"
 
+	"golang.org/x/tools/go/callgraph"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 
@@ -32,12 +33,32 @@ import (
 // It returns three values: mapped assertions, unmapped assertions,
 // and a set of side effect IDs whose return values were explicitly
 // discarded (e.g., _ = target()), making them definitively unasserted.
+//
+// Only statically dispatched calls to the target are traced; use
+// MapAssertionsToEffectsWithCallGraph when the target is reached
+// through an interface or a function value.
 func MapAssertionsToEffects(
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
 	sites []AssertionSite,
 	effects []taxonomy.SideEffect,
 	testPkg *packages.Package,
+) (mapped []taxonomy.AssertionMapping, unmapped []taxonomy.AssertionMapping, discardedIDs map[string]bool) {
+	return MapAssertionsToEffectsWithCallGraph(testFunc, targetFunc, sites, effects, testPkg, nil)
+}
+
+// MapAssertionsToEffectsWithCallGraph is like MapAssertionsToEffects
+// but consults cg (typically from BuildTestCallGraph) when locating
+// the call to the target, so that `got, err := s.Get(k)` on an
+// interface-typed s is traced as a call to the concrete Get method.
+// If cg is nil, only static calls are considered.
+func MapAssertionsToEffectsWithCallGraph(
+	testFunc *ssa.Function,
+	targetFunc *ssa.Function,
+	sites []AssertionSite,
+	effects []taxonomy.SideEffect,
+	testPkg *packages.Package,
+	cg *callgraph.Graph,
 ) (mapped []taxonomy.AssertionMapping, unmapped []taxonomy.AssertionMapping, discardedIDs map[string]bool) {
 	discardedIDs = make(map[string]bool)
 
@@ -56,7 +77,7 @@ func MapAssertionsToEffects(
 	}
 
 	// Find the call to the target function in the test SSA.
-	targetCall := FindTargetCall(testFunc, targetFunc)
+	targetCall := findTargetCall(testFunc, targetFunc, cg)
 
 	// Build a map from side effect ID to side effect for matching.
 	effectMap := make(map[string]*taxonomy.SideEffect, len(effects))
@@ -71,14 +92,55 @@ func MapAssertionsToEffects(
 	// Build a map from types.Object to effect ID by finding the
 	// AST assignment that receives the target call's return values
 	// and correlating LHS identifiers with side effects.
-	objToEffectID := traceTargetValues(targetCall, effects, testPkg, testFunc, targetFunc)
+	objToEffectID := traceTargetValues(targetCall, effects, testPkg, testFunc, targetFunc, cg)
 
 	// Match assertion expressions to traced values.
 	for _, site := range sites {
+		if site.Kind == AssertionKindExampleOutput {
+			if m := mapExampleOutput(site, targetFunc, effects, objToEffectID, effectMap, testPkg); len(m) > 0 {
+				mapped = append(mapped, m...)
+				continue
+			}
+			// An output comment on a target that writes nothing
+			// asserts only through the values the example prints.
+			if site.Expr == nil {
+				continue
+			}
+		}
+		if site.Kind == AssertionKindMockExpectation {
+			if m := mapMockExpectation(site, effects); len(m) > 0 {
+				mapped = append(mapped, m...)
+				continue
+			}
+		}
+		// Handlers return nothing; their responses are asserted
+		// through the recorder they were given.
+		if m := mapHTTPResponseCheck(site, effects, testPkg); len(m) > 0 {
+			mapped = append(mapped, m...)
+			continue
+		}
+		// A check against context.Canceled asserts that the target
+		// honours cancellation, and a check of status.Code(err) the
+		// gRPC code it returns, besides the value they check.
+		extra := mapCancellationCheck(site, effects, testPkg)
+		extra = append(extra, mapGRPCStatusCheck(site, effects, testPkg)...)
+		mapped = append(mapped, extra...)
+		if site.Kind == AssertionKindErrorMatch {
+			if m := mapErrorMatch(site, effects, objToEffectID, effectMap, testPkg); len(m) > 0 {
+				mapped = append(mapped, m...)
+				continue
+			}
+		}
 		mapping := matchAssertionToEffect(site, objToEffectID, effectMap, testPkg)
+		if mapping == nil && site.Kind == AssertionKindProperty {
+			if m := mapPropertyInvariant(site, targetFunc, effects, testPkg); len(m) > 0 {
+				mapped = append(mapped, m...)
+				continue
+			}
+		}
 		if mapping != nil {
 			mapped = append(mapped, *mapping)
-		} else {
+		} else if len(extra) == 0 {
 			// Per spec FR-003: unmapped assertions are reported
 			// separately and excluded from metrics.
 			unmapped = append(unmapped, taxonomy.AssertionMapping{
@@ -93,6 +155,103 @@ func MapAssertionsToEffects(
 	return mapped, unmapped, discardedIDs
 }
 
+// mapExampleOutput maps the example-specific assertion forms that
+// identity matching cannot see. The "// Output:" comment asserts on
+// everything written to stdout, so it covers the target's StdoutWrite
+// and WriterOutput effects. A print whose arguments call the target
+// inline (fmt.Println(Format(3))) covers the target's return values,
+// and a print of traced variables (fmt.Println(n, err)) covers every
+// effect those variables hold.
+func mapExampleOutput(
+	site AssertionSite,
+	targetFunc *ssa.Function,
+	effects []taxonomy.SideEffect,
+	objToEffectID map[types.Object]string,
+	effectMap map[string]*taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	var covered []taxonomy.SideEffect
+	confidence := 75
+	if site.Expr == nil {
+		covered = filterEffectsByType(effects, taxonomy.StdoutWrite, taxonomy.WriterOutput)
+		confidence = 70
+	} else if testPkg != nil && testPkg.TypesInfo != nil {
+		if referencesTarget(site.Expr, targetFunc, testPkg.TypesInfo) {
+			covered = filterEffectsByType(effects, taxonomy.ReturnValue, taxonomy.ErrorReturn)
+		} else {
+			seen := make(map[string]bool)
+			ast.Inspect(site.Expr, func(n ast.Node) bool {
+				ident, ok := n.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				id, ok := objToEffectID[testPkg.TypesInfo.Uses[ident]]
+				if ok && !seen[id] && effectMap[id] != nil {
+					seen[id] = true
+					covered = append(covered, *effectMap[id])
+				}
+				return true
+			})
+		}
+	}
+
+	return mappingsForSite(site, covered, confidence)
+}
+
+// mapPropertyInvariant maps a property assertion that names the
+// target instead of holding a traced value: a quick.Check result such
+// as `Abs(x) >= 0`, or quick.CheckEqual(Abs, refAbs, nil). Checking
+// the invariant over every generated input asserts on the target's
+// return values.
+func mapPropertyInvariant(
+	site AssertionSite,
+	targetFunc *ssa.Function,
+	effects []taxonomy.SideEffect,
+	testPkg *packages.Package,
+) []taxonomy.AssertionMapping {
+	if site.Expr == nil || testPkg == nil || testPkg.TypesInfo == nil ||
+		!referencesTarget(site.Expr, targetFunc, testPkg.TypesInfo) {
+		return nil
+	}
+	covered := filterEffectsByType(effects, taxonomy.ReturnValue, taxonomy.ErrorReturn)
+	return mappingsForSite(site, covered, 70)
+}
+
+// referencesTarget reports whether expr calls or names the target
+// function, e.g., fmt.Println(Format(3)) or quick.CheckEqual(Abs, ...).
+func referencesTarget(expr ast.Node, targetFunc *ssa.Function, info *types.Info) bool {
+	if targetFunc == nil || targetFunc.Object() == nil {
+		return false
+	}
+	target := targetFunc.Object()
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || found {
+			return !found
+		}
+		if fn, ok := info.Uses[ident].(*types.Func); ok && fn.Origin() == target {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// mappingsForSite maps one assertion site to each covered effect.
+func mappingsForSite(site AssertionSite, covered []taxonomy.SideEffect, confidence int) []taxonomy.AssertionMapping {
+	mappings := make([]taxonomy.AssertionMapping, 0, len(covered))
+	for _, e := range covered {
+		mappings = append(mappings, taxonomy.AssertionMapping{
+			AssertionLocation: site.Location,
+			AssertionType:     mapKindToType(site.Kind),
+			SideEffectID:      e.ID,
+			Confidence:        confidence,
+		})
+	}
+	return mappings
+}
+
 // detectDiscardedReturns identifies return/error side effects whose
 // values were explicitly discarded at the call site (e.g., _ = f()
 // or f() with ignored returns). In SSA, discarded returns produce
@@ -152,12 +311,23 @@ func detectDiscardedReturns(
 func FindTargetCall(
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
+) *ssa.Call {
+	return findTargetCall(testFunc, targetFunc, nil)
+}
+
+// findTargetCall is the call-graph-aware implementation of
+// FindTargetCall. Calls without a static callee match the target
+// when cg has an edge from the call site to it.
+func findTargetCall(
+	testFunc *ssa.Function,
+	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 ) *ssa.Call {
 	if testFunc == nil || testFunc.Blocks == nil || targetFunc == nil {
 		return nil
 	}
 
-	return findTargetCallInFunc(testFunc, targetFunc, make(map[*ssa.Function]bool))
+	return findTargetCallInFunc(testFunc, targetFunc, cg, make(map[*ssa.Function]bool))
 }
 
 // findTargetCallInFunc recursively searches an SSA function and its
@@ -165,6 +335,7 @@ func FindTargetCall(
 func findTargetCallInFunc(
 	fn *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 	visited map[*ssa.Function]bool,
 ) *ssa.Call {
 	if fn == nil || fn.Blocks == nil || visited[fn] {
@@ -176,8 +347,7 @@ func findTargetCallInFunc(
 		for _, instr := range block.Instrs {
 			// Check for direct calls to the target.
 			if call, ok := instr.(*ssa.Call); ok {
-				callee := call.Call.StaticCallee()
-				if callee != nil && (callee == targetFunc || sameFunction(callee, targetFunc)) {
+				if callReaches(call, targetFunc, cg) {
 					return call
 				}
 			}
@@ -185,13 +355,19 @@ func findTargetCallInFunc(
 			// closures (handles t.Run sub-tests and anonymous functions).
 			if mc, ok := instr.(*ssa.MakeClosure); ok {
 				if closureFn, ok := mc.Fn.(*ssa.Function); ok {
-					if result := findTargetCallInFunc(closureFn, targetFunc, visited); result != nil {
+					if result := findTargetCallInFunc(closureFn, targetFunc, cg, visited); result != nil {
 						return result
 					}
 				}
 			}
 		}
 	}
+	// Closures without free variables have no MakeClosure.
+	for _, anon := range fn.AnonFuncs {
+		if result := findTargetCallInFunc(anon, targetFunc, cg, visited); result != nil {
+			return result
+		}
+	}
 	return nil
 }
 
@@ -216,6 +392,7 @@ func traceTargetValues(
 	testPkg *packages.Package,
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 ) map[types.Object]string {
 	objToEffectID := make(map[types.Object]string)
 
@@ -226,7 +403,7 @@ func traceTargetValues(
 	// Trace return values by finding the AST assignment.
 	// When targetCall is nil (target called inside a helper),
 	// traceReturnValues falls back to helper return tracing.
-	traceReturnValues(targetCall, effects, objToEffectID, testPkg, testFunc, targetFunc)
+	traceReturnValues(targetCall, effects, objToEffectID, testPkg, testFunc, targetFunc, cg)
 
 	// Trace mutations (receiver and pointer arg values).
 	// Mutation tracing requires a direct target call.
@@ -261,6 +438,7 @@ func traceReturnValues(
 	testPkg *packages.Package,
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 ) {
 	returnEffects := filterEffectsByType(effects,
 		taxonomy.ReturnValue, taxonomy.ErrorReturn)
@@ -286,7 +464,7 @@ func traceReturnValues(
 	// the target is called inside a helper, or findAssignLHS returns
 	// nil), search the test function's SSA for calls to helpers that
 	// invoke the target at depth 1.
-	traceHelperReturnValues(returnEffects, objToEffectID, testPkg, testFunc, targetFunc)
+	traceHelperReturnValues(returnEffects, objToEffectID, testPkg, testFunc, targetFunc, cg)
 }
 
 // mapAssignLHSToEffects maps each non-blank LHS identifier of an
@@ -337,6 +515,7 @@ func traceHelperReturnValues(
 	testPkg *packages.Package,
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 ) {
 	if testFunc == nil || testFunc.Blocks == nil || targetFunc == nil || testPkg == nil {
 		return
@@ -344,7 +523,7 @@ func traceHelperReturnValues(
 
 	// Find helper calls in the test function's SSA that invoke
 	// the target at depth 1.
-	helperCall := findHelperCall(testFunc, targetFunc)
+	helperCall := findHelperCall(testFunc, targetFunc, cg)
 	if helperCall == nil {
 		return
 	}
@@ -370,8 +549,9 @@ func traceHelperReturnValues(
 func findHelperCall(
 	testFunc *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 ) *ssa.Call {
-	return findHelperCallInFunc(testFunc, targetFunc, make(map[*ssa.Function]bool))
+	return findHelperCallInFunc(testFunc, targetFunc, cg, make(map[*ssa.Function]bool))
 }
 
 // maxClosureDepth bounds the recursion depth when following MakeClosure
@@ -387,9 +567,10 @@ const maxClosureDepth = 10
 func findHelperCallInFunc(
 	fn *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 	visited map[*ssa.Function]bool,
 ) *ssa.Call {
-	return findHelperCallInFuncDepth(fn, targetFunc, visited, 0)
+	return findHelperCallInFuncDepth(fn, targetFunc, cg, visited, 0)
 }
 
 // findHelperCallInFuncDepth is the depth-bounded implementation of
@@ -398,6 +579,7 @@ func findHelperCallInFunc(
 func findHelperCallInFuncDepth(
 	fn *ssa.Function,
 	targetFunc *ssa.Function,
+	cg *callgraph.Graph,
 	visited map[*ssa.Function]bool,
 	depth int,
 ) *ssa.Call {
@@ -419,14 +601,14 @@ func findHelperCallInFuncDepth(
 					continue
 				}
 				// Check if this callee calls the target at depth 1.
-				if helperCallsTarget(callee, targetFunc) {
+				if helperCallsTarget(callee, targetFunc, cg) {
 					return call
 				}
 			}
 			// Follow closures (handles t.Run sub-tests).
 			if mc, ok := instr.(*ssa.MakeClosure); ok {
 				if closureFn, ok := mc.Fn.(*ssa.Function); ok {
-					if result := findHelperCallInFuncDepth(closureFn, targetFunc, visited, depth+1); result != nil {
+					if result := findHelperCallInFuncDepth(closureFn, targetFunc, cg, visited, depth+1); result != nil {
 						return result
 					}
 				}
@@ -439,8 +621,8 @@ func findHelperCallInFuncDepth(
 // helperCallsTarget checks whether a helper SSA function directly
 // calls the target function (depth 1 only). It iterates the helper's
 // blocks and instructions looking for *ssa.Call instructions whose
-// callee matches the target.
-func helperCallsTarget(helper *ssa.Function, target *ssa.Function) bool {
+// callee matches the target, either statically or through cg.
+func helperCallsTarget(helper *ssa.Function, target *ssa.Function, cg *callgraph.Graph) bool {
 	if helper == nil || helper.Blocks == nil || target == nil {
 		return false
 	}
@@ -451,8 +633,7 @@ func helperCallsTarget(helper *ssa.Function, target *ssa.Function) bool {
 			if !ok {
 				continue
 			}
-			callee := call.Call.StaticCallee()
-			if callee != nil && (callee == target || sameFunction(callee, target)) {
+			if callReaches(call, target, cg) {
 				return true
 			}
 		}
@@ -529,12 +710,17 @@ func traceMutations(
 	}
 
 	args := targetCall.Call.Args
-	isMethod := targetCall.Call.IsInvoke() ||
-		(len(args) > 0 && hasReceiverMutation(mutationEffects))
 
-	// Determine the offset for explicit parameters.
+	// Determine the receiver value and the offset for explicit
+	// parameters. Interface (invoke-mode) calls carry the receiver
+	// in Call.Value and only explicit parameters in Args.
+	var receiver ssa.Value
 	paramOffset := 0
-	if isMethod {
+	switch {
+	case targetCall.Call.IsInvoke():
+		receiver = targetCall.Call.Value
+	case len(args) > 0 && hasReceiverMutation(mutationEffects):
+		receiver = args[0]
 		paramOffset = 1
 	}
 
@@ -544,9 +730,7 @@ func traceMutations(
 
 		switch effect.Type {
 		case taxonomy.ReceiverMutation:
-			if len(args) > 0 {
-				argValue = args[0]
-			}
+			argValue = receiver
 		case taxonomy.PointerArgMutation:
 			argIdx := paramOffset + ptrArgIdx
 			if argIdx < len(args) {
@@ -602,6 +786,49 @@ func resolveSSAValueToObj(
 			return
 		}
 	}
+
+	// A heap allocation such as `c := &Counter{}` is positioned at
+	// the literal's brace; map it to the variable it initializes.
+	if _, ok := v.(*ssa.Alloc); ok {
+		if obj := compositeLitVar(testPkg, pos); obj != nil {
+			objToEffectID[obj] = effectID
+		}
+	}
+}
+
+// compositeLitVar returns the variable initialized with the address
+// of the composite literal whose opening brace is at lbrace.
+func compositeLitVar(testPkg *packages.Package, lbrace token.Pos) types.Object {
+	var found types.Object
+	for _, file := range testPkg.Syntax {
+		if lbrace < file.Pos() || lbrace > file.End() {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			assign, ok := n.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != len(assign.Rhs) {
+				return true
+			}
+			for i, rhs := range assign.Rhs {
+				addr, ok := rhs.(*ast.UnaryExpr)
+				if !ok || addr.Op != token.AND {
+					continue
+				}
+				lit, ok := addr.X.(*ast.CompositeLit)
+				if !ok || lit.Lbrace != lbrace {
+					continue
+				}
+				if ident, ok := assign.Lhs[i].(*ast.Ident); ok {
+					found = testPkg.TypesInfo.ObjectOf(ident)
+				}
+			}
+			return true
+		})
+	}
+	return found
 }
 
 // hasReceiverMutation checks if any mutation effect is a receiver mutation.
@@ -869,7 +1096,9 @@ func classifyUnmappedReason(
 
 	// Cause B: return values were not traced because the call was inline.
 	// Heuristic: no traced objects AND target has return/error effects.
-	if len(objToEffectID) == 0 && hasReturnEffects(effects) {
+	// Mock expectations never assert on return values.
+	if site.Kind != AssertionKindMockExpectation &&
+		len(objToEffectID) == 0 && hasReturnEffects(effects) {
 		return taxonomy.UnmappedReasonInlineCall
 	}
 
@@ -905,6 +1134,14 @@ func mapKindToType(kind AssertionKind) taxonomy.AssertionType {
 		return taxonomy.AssertionNilCheck
 	case AssertionKindGoCmpDiff:
 		return taxonomy.AssertionDiffCheck
+	case AssertionKindExampleOutput:
+		return taxonomy.AssertionExampleOutput
+	case AssertionKindProperty:
+		return taxonomy.AssertionProperty
+	case AssertionKindMockExpectation:
+		return taxonomy.AssertionInteraction
+	case AssertionKindErrorMatch:
+		return taxonomy.AssertionErrorMatch
 	default:
 		return taxonomy.AssertionCustom
 	}
@@ -0,0 +1,251 @@
+package quality
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// Mock expectations assert on the target's interactions with its
+// injected dependencies. Two frameworks are recognized by the shape
+// of their expectation chains:
+//
+//   - gomock: mockRepo.EXPECT().Save(gomock.Any()).Return(nil), with
+//     strict modifiers Times, MinTimes, MaxTimes, and After, and
+//     gomock.InOrder(...) for call ordering. AnyTimes relaxes the
+//     expectation to a stub. The implicit Times(1) that every gomock
+//     expectation carries is not counted as strict.
+//   - testify/mock: m.On("Save", mock.Anything).Return(nil), with
+//     strict modifiers Once, Twice, and Times, and Maybe relaxing it.
+//     An On expectation is checked only by m.AssertExpectations(t)
+//     (or mock.AssertExpectationsForObjects), so it counts only when
+//     the test verifies the same mock that way, or with AssertCalled
+//     or AssertNumberOfCalls; otherwise m.On merely stubs the method.
+//     m.AssertCalled(t, "Save", ...) and m.AssertNotCalled assert a
+//     call after the fact; m.AssertNumberOfCalls(t, "Save", n) also
+//     pins the call count.
+//
+// Like the testify assertion detection, matching is syntactic so that
+// generated mocks need not be type-checked against the framework.
+
+// strictMockModifiers are the chained calls that pin how many times,
+// or in what order, an expected call must happen.
+var strictMockModifiers = map[string]bool{
+	"Times": true, "MinTimes": true, "MaxTimes": true, "After": true,
+	"Once": true, "Twice": true,
+}
+
+// relaxedMockModifiers turn an expectation into a stub that may be
+// called any number of times, including never.
+var relaxedMockModifiers = map[string]bool{
+	"AnyTimes": true, "Maybe": true,
+}
+
+// detectMockExpectations returns the assertion sites for a mock
+// expectation statement, or nil if call is not one. A
+// gomock.InOrder(...) call yields one strict site per expectation it
+// orders.
+func (d *assertionDetector) detectMockExpectations(
+	call *ast.CallExpr,
+	fn *ast.FuncDecl,
+	depth int,
+) []AssertionSite {
+	if isGomockInOrder(call) {
+		var sites []AssertionSite
+		for _, arg := range call.Args {
+			inner, ok := arg.(*ast.CallExpr)
+			if !ok {
+				continue
+			}
+			if site := d.mockExpectation(inner, fn, depth); site != nil {
+				site.Strict = true
+				sites = append(sites, *site)
+			}
+		}
+		return sites
+	}
+	if site := d.mockExpectation(call, fn, depth); site != nil {
+		return []AssertionSite{*site}
+	}
+	return nil
+}
+
+// mockExpectation parses one expectation chain, collecting the
+// modifiers chained after the expected method.
+func (d *assertionDetector) mockExpectation(
+	call *ast.CallExpr,
+	fn *ast.FuncDecl,
+	depth int,
+) *AssertionSite {
+	strict := false
+	relaxed := false
+	for cur := call; cur != nil; {
+		sel, ok := cur.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		if method, isExpect := gomockExpectedMethod(sel); isExpect {
+			return d.mockSite(call, fn, depth, method, strict && !relaxed)
+		}
+		if method, isOn := testifyExpectedMethod(cur, sel); isOn {
+			if !d.isVerifiedMock(fn, sel.X) {
+				return nil
+			}
+			return d.mockSite(call, fn, depth, method, strict && !relaxed)
+		}
+		if method, pinsCount, isAssert := testifyCallAssertion(cur, sel); isAssert {
+			return d.mockSite(call, fn, depth, method, pinsCount)
+		}
+		strict = strict || strictMockModifiers[sel.Sel.Name]
+		relaxed = relaxed || relaxedMockModifiers[sel.Sel.Name]
+		cur, _ = sel.X.(*ast.CallExpr)
+	}
+	return nil
+}
+
+// mockSite builds the assertion site for an expectation of method.
+func (d *assertionDetector) mockSite(
+	call *ast.CallExpr,
+	fn *ast.FuncDecl,
+	depth int,
+	method string,
+	strict bool,
+) *AssertionSite {
+	return &AssertionSite{
+		Location:   d.posString(call.Pos()),
+		Kind:       AssertionKindMockExpectation,
+		FuncDecl:   fn,
+		Depth:      depth,
+		Expr:       call,
+		MockMethod: method,
+		Strict:     strict,
+	}
+}
+
+// gomockExpectedMethod reports whether sel is the expected-method
+// selector of a gomock chain (recorder.EXPECT().Method) and returns
+// the method name.
+func gomockExpectedMethod(sel *ast.SelectorExpr) (string, bool) {
+	recorder, ok := sel.X.(*ast.CallExpr)
+	if !ok || len(recorder.Args) != 0 {
+		return "", false
+	}
+	expect, ok := recorder.Fun.(*ast.SelectorExpr)
+	if !ok || expect.Sel.Name != "EXPECT" {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// testifyExpectedMethod reports whether call is a testify
+// m.On("Method", ...) expectation and returns the method name.
+func testifyExpectedMethod(call *ast.CallExpr, sel *ast.SelectorExpr) (string, bool) {
+	if sel.Sel.Name != "On" || len(call.Args) == 0 {
+		return "", false
+	}
+	method := stringLiteral(call.Args[0])
+	return method, method != ""
+}
+
+// testifyCallAssertion reports whether call is a testify after-the-fact
+// call assertion (m.AssertCalled(t, "Method", ...)) and returns the
+// method name and whether the assertion pins the call count.
+func testifyCallAssertion(call *ast.CallExpr, sel *ast.SelectorExpr) (method string, pinsCount, ok bool) {
+	switch sel.Sel.Name {
+	case "AssertCalled", "AssertNotCalled":
+	case "AssertNumberOfCalls":
+		pinsCount = true
+	default:
+		return "", false, false
+	}
+	if len(call.Args) < 2 {
+		return "", false, false
+	}
+	method = stringLiteral(call.Args[1])
+	return method, pinsCount, method != ""
+}
+
+// testifyVerifiers are the testify mock methods that check the
+// expectations registered with On.
+var testifyVerifiers = map[string]bool{
+	"AssertExpectations": true, "AssertCalled": true, "AssertNumberOfCalls": true,
+}
+
+// verifiedMocks returns the testify mocks that body verifies, keyed
+// by their expression: m.AssertExpectations(t), m.AssertCalled(...),
+// m.AssertNumberOfCalls(...), and the objects passed to
+// mock.AssertExpectationsForObjects(t, ...). Deferred calls and calls
+// in closures such as t.Cleanup count too.
+func verifiedMocks(body ast.Node) map[string]bool {
+	verified := make(map[string]bool)
+	if body == nil {
+		return verified
+	}
+	ast.Inspect(body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch {
+		case testifyVerifiers[sel.Sel.Name]:
+			verified[types.ExprString(sel.X)] = true
+		case sel.Sel.Name == "AssertExpectationsForObjects" && len(call.Args) > 1:
+			for _, obj := range call.Args[1:] {
+				if addr, ok := obj.(*ast.UnaryExpr); ok && addr.Op == token.AND {
+					obj = addr.X
+				}
+				verified[types.ExprString(obj)] = true
+			}
+		}
+		return true
+	})
+	return verified
+}
+
+// isVerifiedMock reports whether the testify mock m is verified by
+// the test, or by fn when fn is a helper the test calls.
+func (d *assertionDetector) isVerifiedMock(fn *ast.FuncDecl, m ast.Expr) bool {
+	key := types.ExprString(m)
+	return d.verified[key] || verifiedMocks(fn.Body)[key]
+}
+
+// isGomockInOrder reports whether call is gomock.InOrder(...).
+func isGomockInOrder(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "InOrder" {
+		return false
+	}
+	pkg, ok := sel.X.(*ast.Ident)
+	return ok && pkg.Name == "gomock"
+}
+
+// mapMockExpectation maps an expectation of a method to the target's
+// InterfaceInteraction effects that call a method of that name. The
+// mock type is not resolved to the interface it implements, so a
+// method name shared by two dependencies maps to both.
+func mapMockExpectation(site AssertionSite, effects []taxonomy.SideEffect) []taxonomy.AssertionMapping {
+	var covered []taxonomy.SideEffect
+	for _, e := range filterEffectsByType(effects, taxonomy.InterfaceInteraction) {
+		if interactionMethod(e.Target) == site.MockMethod {
+			covered = append(covered, e)
+		}
+	}
+	mappings := mappingsForSite(site, covered, 70)
+	for i := range mappings {
+		mappings[i].Strict = site.Strict
+	}
+	return mappings
+}
+
+// interactionMethod returns the method of an InterfaceInteraction
+// target ("Repository.Save" -> "Save").
+func interactionMethod(target string) string {
+	return target[strings.LastIndex(target, ".")+1:]
+}
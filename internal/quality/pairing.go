@@ -7,9 +7,35 @@ import (
 	"sort"
 	"strings"
 
+	"golang.org/x/tools/go/callgraph"
+	"golang.org/x/tools/go/callgraph/vta"
 	"golang.org/x/tools/go/packages"
 	"golang.org/x/tools/go/ssa"
 	"golang.org/x/tools/go/ssa/ssautil"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// TestKind distinguishes the forms of test function that go test
+// runs and that quality assesses.
+type TestKind string
+
+// Test kind constants.
+const (
+	// TestKindTest is a func TestXxx(t *testing.T).
+	TestKindTest TestKind = "test"
+
+	// TestKindExample is a func ExampleXxx() whose body ends with an
+	// "// Output:" comment that go test verifies against stdout.
+	TestKindExample TestKind = "example"
+
+	// TestKindFuzz is a func FuzzXxx(f *testing.F) whose f.Fuzz
+	// closure checks invariants over generated inputs.
+	TestKindFuzz TestKind = "fuzz"
+
+	// TestKindProperty is a func TestXxx(t *testing.T) that checks
+	// invariants through testing/quick or pgregory.net/rapid.
+	TestKindProperty TestKind = "property"
 )
 
 // TestFunc represents a test function found in a test package.
@@ -17,6 +43,9 @@ type TestFunc struct {
 	// Name is the function name (e.g., "TestFoo").
 	Name string
 
+	// Kind is the form of the test function.
+	Kind TestKind
+
 	// Decl is the AST declaration of the test function.
 	Decl *ast.FuncDecl
 
@@ -27,17 +56,29 @@ type TestFunc struct {
 // InferredTarget represents a function identified as the target
 // under test by call graph inference.
 type InferredTarget struct {
+	// ID is the target's FunctionID, joining it to the analysis
+	// results.
+	ID taxonomy.FunctionID
+
 	// FuncName is the qualified function name matching
 	// FunctionTarget.QualifiedName() format.
 	FuncName string
 
+	// Package is the import path of the package declaring the
+	// target, matching FunctionTarget.Package.
+	Package string
+
 	// SSAFunc is the SSA representation of the target function.
 	SSAFunc *ssa.Function
 }
 
 // FindTestFunctions scans the loaded test package for functions
-// matching the Test*(*testing.T) signature. It returns a list of
-// TestFunc values for each test function found.
+// matching the Test*(*testing.T) and Fuzz*(*testing.F) signatures,
+// and for Example*() functions with an "// Output:" comment (examples
+// without one are compiled but never run, so they assert nothing).
+// Tests that call testing/quick or rapid checkers are reported as
+// property tests. It returns a list of TestFunc values for each test
+// function found.
 func FindTestFunctions(pkg *packages.Package) []TestFunc {
 	var tests []TestFunc
 
@@ -47,12 +88,23 @@ func FindTestFunctions(pkg *packages.Package) []TestFunc {
 			if !ok {
 				continue
 			}
-			if !isTestFunction(fn) {
+			var kind TestKind
+			switch {
+			case isTestFunction(fn) && hasPropertyCheck(pkg, fn):
+				kind = TestKindProperty
+			case isTestFunction(fn):
+				kind = TestKindTest
+			case isFuzzFunction(fn):
+				kind = TestKindFuzz
+			case isExampleFunction(fn) && exampleOutputComment(file, fn) != nil:
+				kind = TestKindExample
+			default:
 				continue
 			}
 			pos := pkg.Fset.Position(fn.Pos())
 			tests = append(tests, TestFunc{
 				Name:     fn.Name.Name,
+				Kind:     kind,
 				Decl:     fn,
 				Location: fmt.Sprintf("%s:%d", pos.Filename, pos.Line),
 			})
@@ -61,6 +113,36 @@ func FindTestFunctions(pkg *packages.Package) []TestFunc {
 	return tests
 }
 
+// isExampleFunction checks whether the function declaration has the
+// Example* name prefix and takes no parameters and returns nothing.
+func isExampleFunction(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || fn.Body == nil {
+		return false
+	}
+	if !strings.HasPrefix(fn.Name.Name, "Example") {
+		return false
+	}
+	return (fn.Type.Params == nil || len(fn.Type.Params.List) == 0) &&
+		(fn.Type.Results == nil || len(fn.Type.Results.List) == 0)
+}
+
+// exampleOutputComment returns the "// Output:" or "// Unordered
+// output:" comment group inside an example's body, or nil if the
+// example has none.
+func exampleOutputComment(file *ast.File, fn *ast.FuncDecl) *ast.CommentGroup {
+	var output *ast.CommentGroup
+	for _, cg := range file.Comments {
+		if cg.Pos() < fn.Body.Lbrace || cg.End() > fn.Body.Rbrace {
+			continue
+		}
+		text := strings.ToLower(strings.TrimSpace(cg.Text()))
+		if strings.HasPrefix(text, "output:") || strings.HasPrefix(text, "unordered output:") {
+			output = cg
+		}
+	}
+	return output
+}
+
 // isTestFunction checks whether the function declaration has the
 // Test* name prefix and accepts a single *testing.T parameter.
 func isTestFunction(fn *ast.FuncDecl) bool {
@@ -78,8 +160,25 @@ func isTestFunction(fn *ast.FuncDecl) bool {
 	return isTestingTParam(param)
 }
 
+// isFuzzFunction checks whether the function declaration has the
+// Fuzz* name prefix and accepts a single *testing.F parameter.
+func isFuzzFunction(fn *ast.FuncDecl) bool {
+	if fn.Recv != nil || !strings.HasPrefix(fn.Name.Name, "Fuzz") {
+		return false
+	}
+	if fn.Type.Params == nil || len(fn.Type.Params.List) != 1 {
+		return false
+	}
+	return isTestingParam(fn.Type.Params.List[0], "F")
+}
+
 // isTestingTParam checks whether a field is of type *testing.T.
 func isTestingTParam(field *ast.Field) bool {
+	return isTestingParam(field, "T")
+}
+
+// isTestingParam checks whether a field is of type *testing.<name>.
+func isTestingParam(field *ast.Field, name string) bool {
 	star, ok := field.Type.(*ast.StarExpr)
 	if !ok {
 		return false
@@ -92,7 +191,7 @@ func isTestingTParam(field *ast.Field) bool {
 	if !ok {
 		return false
 	}
-	return ident.Name == "testing" && sel.Sel.Name == "T"
+	return ident.Name == "testing" && sel.Sel.Name == name
 }
 
 // BuildTestSSA builds the SSA representation for a test package.
@@ -110,9 +209,49 @@ func BuildTestSSA(pkg *packages.Package) (*ssa.Program, *ssa.Package, error) {
 	return prog, ssaPkgs[0], nil
 }
 
+// BuildTestCallGraph computes a call graph for the test SSA program
+// using Variable Type Analysis (VTA). The graph resolves interface
+// method calls (invoke mode) and dynamic calls through function
+// values to the concrete functions that can reach them, which static
+// callee resolution cannot do.
+//
+// Only functions outside the standard library (plus the test package
+// itself) are included. Type flows through stdlib code are therefore
+// not tracked, which keeps the analysis fast on large test programs
+// at the cost of missing values that round-trip through the stdlib.
+func BuildTestCallGraph(prog *ssa.Program, testPkgPath string) *callgraph.Graph {
+	if prog == nil {
+		return nil
+	}
+	funcs := make(map[*ssa.Function]bool)
+	for fn := range ssautil.AllFunctions(prog) {
+		if inCallGraphScope(fn, testPkgPath) {
+			funcs[fn] = true
+		}
+	}
+	return vta.CallGraph(funcs, nil)
+}
+
+// inCallGraphScope reports whether fn belongs to the test package or
+// a non-stdlib package. Generic instantiations and other synthetic
+// functions without a package are attributed to their origin.
+func inCallGraphScope(fn *ssa.Function, testPkgPath string) bool {
+	pkg := fn.Package()
+	if pkg == nil && fn.Origin() != nil {
+		pkg = fn.Origin().Package()
+	}
+	if pkg == nil {
+		return false
+	}
+	pkgPath := pkg.Pkg.Path()
+	return pkgPath == testPkgPath || strings.Contains(pkgPath, ".")
+}
+
 // InferTargets identifies which non-test functions the given test
 // function exercises, using SSA call graph analysis bounded to
-// opts.MaxHelperDepth levels.
+// opts.MaxHelperDepth levels. Only statically dispatched calls are
+// followed; use InferTargetsWithCallGraph to also resolve interface
+// and function-value calls.
 //
 // It returns the inferred targets and any warnings (e.g., ambiguous
 // targets, no target found).
@@ -121,38 +260,75 @@ func InferTargets(
 	testPkg *packages.Package,
 	opts Options,
 ) ([]InferredTarget, []string) {
-	if testFunc.Blocks == nil {
-		return nil, []string{"test function has no SSA body"}
-	}
+	return InferTargetsWithCallGraph(testFunc, testPkg, nil, opts)
+}
 
-	testPkgPath := testPkg.PkgPath
+// InferTargetsWithCallGraph is like InferTargets but consults cg
+// (typically from BuildTestCallGraph) to resolve calls that have no
+// static callee: interface method calls such as
+// `var s Store = NewMemStore(); s.Put(...)` pair with
+// (*MemStore).Put, and calls through function values pair with every
+// function the call graph says can flow into them. If cg is nil, only
+// static calls are followed.
+func InferTargetsWithCallGraph(
+	testFunc *ssa.Function,
+	testPkg *packages.Package,
+	cg *callgraph.Graph,
+	opts Options,
+) ([]InferredTarget, []string) {
 	// External test packages have path suffix "_test"; the target
 	// package is the base path without the suffix.
-	targetPkgPath := strings.TrimSuffix(testPkgPath, "_test")
+	targetPkgPath := strings.TrimSuffix(testPkg.PkgPath, "_test")
+	return inferTargets(testFunc, map[string]bool{targetPkgPath: true}, cg, opts)
+}
 
-	candidates := make(map[string]*ssa.Function)
+// inferTargets implements target inference for a test function,
+// accepting callees declared in any package in scope. Assess uses
+// the set of analyzed packages as the scope so that external test
+// packages and integration test packages pair with targets in every
+// production package they exercise.
+func inferTargets(
+	testFunc *ssa.Function,
+	scope map[string]bool,
+	cg *callgraph.Graph,
+	opts Options,
+) ([]InferredTarget, []string) {
+	if testFunc.Blocks == nil {
+		return nil, []string{"test function has no SSA body"}
+	}
+
+	candidates := make(map[*ssa.Function]bool)
 	var warnings []string
 
 	// Walk the SSA blocks looking for calls to functions in the
-	// target package (non-test, non-stdlib).
-	walkCalls(testFunc, targetPkgPath, candidates, opts.MaxHelperDepth, make(map[*ssa.Function]bool))
+	// target packages (non-test, non-stdlib).
+	walkCalls(testFunc, scope, cg, candidates, opts.MaxHelperDepth, make(map[*ssa.Function]bool))
 
 	if len(candidates) == 0 {
 		return nil, []string{"no target function identified"}
 	}
 
+	// Deduplicate by FunctionID: generic instantiations and
+	// wrappers can yield several SSA functions for the same source
+	// function.
+	seen := make(map[taxonomy.FunctionID]bool, len(candidates))
 	targets := make([]InferredTarget, 0, len(candidates))
-	for name, fn := range candidates {
-		targets = append(targets, InferredTarget{
-			FuncName: name,
-			SSAFunc:  fn,
-		})
+	for fn := range candidates {
+		t := newInferredTarget(fn)
+		if seen[t.ID] {
+			continue
+		}
+		seen[t.ID] = true
+		targets = append(targets, t)
 	}
 
 	// Sort targets deterministically by name to ensure stable
 	// ordering across runs (SC-004 determinism requirement).
 	sort.Slice(targets, func(i, j int) bool {
-		return targets[i].FuncName < targets[j].FuncName
+		if targets[i].FuncName != targets[j].FuncName {
+			return targets[i].FuncName < targets[j].FuncName
+		}
+		return targets[i].Package < targets[j].Package
 	})
 
 	if len(targets) > 1 {
@@ -168,12 +344,14 @@ func InferTargets(
 }
 
 // walkCalls recursively walks SSA call instructions to find calls
-// to functions in the target package. It uses a visited set to
-// prevent infinite recursion and respects the maxDepth bound.
+// to functions in the target packages. It uses a visited set to
+// prevent infinite recursion and respects the maxDepth bound. Calls
+// without a static callee are resolved through cg when it is non-nil.
 func walkCalls(
 	fn *ssa.Function,
-	targetPkgPath string,
-	candidates map[string]*ssa.Function,
+	scope map[string]bool,
+	cg *callgraph.Graph,
+	candidates map[*ssa.Function]bool,
 	maxDepth int,
 	visited map[*ssa.Function]bool,
 ) {
@@ -193,7 +371,7 @@ func walkCalls(
 			// call is inside the t.Run closure.
 			if mc, ok := instr.(*ssa.MakeClosure); ok {
 				if closureFn, ok := mc.Fn.(*ssa.Function); ok {
-					walkCalls(closureFn, targetPkgPath, candidates, maxDepth, visited)
+					walkCalls(closureFn, scope, cg, candidates, maxDepth, visited)
 				}
 				continue
 			}
@@ -202,55 +380,121 @@ func walkCalls(
 			if !ok {
 				continue
 			}
-			callee := resolveCallee(call)
-			if callee == nil {
-				continue
-			}
+			for _, callee := range resolveCallees(call, cg) {
+				// Check if the callee belongs to a target package.
+				if isTargetFunction(callee, scope) {
+					candidates[callee] = true
+					// Also recurse into this function to find deeper
+					// target calls. This handles helper functions that
+					// wrap target calls (e.g., processHelper calls Process).
+					// The maxDepth bound prevents unbounded recursion.
+				}
 
-			// Check if the callee belongs to the target package.
-			if isTargetFunction(callee, targetPkgPath) {
-				name := qualifiedSSAName(callee)
-				candidates[name] = callee
-				// Also recurse into this function to find deeper
-				// target calls. This handles helper functions that
-				// wrap target calls (e.g., processHelper calls Process).
-				// The maxDepth bound prevents unbounded recursion.
-			}
+				// Recurse into non-stdlib, non-test callees (helpers).
+				if shouldRecurse(callee) {
+					walkCalls(callee, scope, cg, candidates, maxDepth-1, visited)
+				}
 
-			// Recurse into non-stdlib, non-test callees (helpers).
-			if shouldRecurse(callee, targetPkgPath) {
-				walkCalls(callee, targetPkgPath, candidates, maxDepth-1, visited)
+				// Property checkers call the functions they are given:
+				// quick.CheckEqual(Abs, refAbs, nil) exercises Abs.
+				if isPropertyCheckerFunc(callee) {
+					for _, arg := range functionArgs(call) {
+						if isTargetFunction(arg, scope) {
+							candidates[arg] = true
+						} else if shouldRecurse(arg) {
+							walkCalls(arg, scope, cg, candidates, maxDepth-1, visited)
+						}
+					}
+				}
 			}
 		}
 	}
+
+	// Closures that capture nothing are passed as plain function
+	// values rather than through MakeClosure (e.g., a t.Run subtest
+	// that does not use the table row), so walk them explicitly.
+	for _, anon := range fn.AnonFuncs {
+		walkCalls(anon, scope, cg, candidates, maxDepth, visited)
+	}
 }
 
-// resolveCallee extracts the concrete *ssa.Function from a call
-// instruction, handling both static and invoke calls.
-func resolveCallee(call *ssa.Call) *ssa.Function {
-	if call.Call.IsInvoke() {
-		return nil // interface calls cannot be statically resolved
+// functionArgs returns the functions passed by name as arguments of
+// a call, looking through conversions to interface types.
+func functionArgs(call *ssa.Call) []*ssa.Function {
+	var fns []*ssa.Function
+	for _, arg := range call.Call.Args {
+		if mi, ok := arg.(*ssa.MakeInterface); ok {
+			arg = mi.X
+		}
+		if fn, ok := arg.(*ssa.Function); ok {
+			fns = append(fns, fn)
+		}
 	}
-	return call.Call.StaticCallee()
+	return fns
 }
 
-// isTargetFunction checks whether the callee belongs to the target
-// package and is not a test function or stdlib function.
-func isTargetFunction(callee *ssa.Function, targetPkgPath string) bool {
+// resolveCallees returns the concrete functions a call instruction
+// may invoke. Static calls resolve to their single callee. Interface
+// method calls (invoke mode) and dynamic calls through function
+// values are resolved via the call graph edges recorded for the call
+// site; without a call graph they resolve to nothing. The result is
+// sorted by function name for deterministic traversal.
+func resolveCallees(call *ssa.Call, cg *callgraph.Graph) []*ssa.Function {
+	if callee := call.Call.StaticCallee(); callee != nil {
+		return []*ssa.Function{callee}
+	}
+	if cg == nil {
+		return nil
+	}
+	node := cg.Nodes[call.Parent()]
+	if node == nil {
+		return nil
+	}
+	var callees []*ssa.Function
+	for _, edge := range node.Out {
+		if edge.Site == call && edge.Callee != nil && edge.Callee.Func != nil {
+			callees = append(callees, edge.Callee.Func)
+		}
+	}
+	sort.Slice(callees, func(i, j int) bool {
+		return callees[i].String() < callees[j].String()
+	})
+	return callees
+}
+
+// callReaches reports whether a call instruction may invoke target,
+// either directly or through a call graph edge.
+func callReaches(call *ssa.Call, target *ssa.Function, cg *callgraph.Graph) bool {
+	for _, callee := range resolveCallees(call, cg) {
+		if callee == target || sameFunction(callee, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// isTargetFunction checks whether the callee belongs to one of the
+// target packages and is not a test function or stdlib function.
+func isTargetFunction(callee *ssa.Function, scope map[string]bool) bool {
+	// Instantiations of generic functions belong to no package;
+	// their generic function does.
+	if origin := callee.Origin(); origin != nil {
+		callee = origin
+	}
 	pkg := callee.Package()
 	if pkg == nil {
 		return false
 	}
-	pkgPath := pkg.Pkg.Path()
 
-	// Must be in the target package (not the test variant).
-	if pkgPath != targetPkgPath {
+	// Must be in a target package (not an external test package).
+	if !scope[pkg.Pkg.Path()] {
 		return false
 	}
 
 	// Skip test functions and init functions.
 	name := callee.Name()
-	if strings.HasPrefix(name, "Test") || strings.HasPrefix(name, "Benchmark") || name == "init" {
+	if strings.HasPrefix(name, "Test") || strings.HasPrefix(name, "Benchmark") ||
+		strings.HasPrefix(name, "Example") || name == "init" {
 		return false
 	}
 
@@ -259,13 +503,20 @@ func isTargetFunction(callee *ssa.Function, targetPkgPath string) bool {
 		return false
 	}
 
+	// Skip functions declared in _test.go files: mocks, fakes, and
+	// helpers of an internal test package share its import path.
+	if pos := callee.Pos(); pos.IsValid() &&
+		strings.HasSuffix(callee.Prog.Fset.Position(pos).Filename, "_test.go") {
+		return false
+	}
+
 	return true
 }
 
 // shouldRecurse checks if we should follow calls into this function
 // to find deeper target calls. We recurse into functions in the
 // same module that are not stdlib.
-func shouldRecurse(callee *ssa.Function, _ string) bool {
+func shouldRecurse(callee *ssa.Function) bool {
 	pkg := callee.Package()
 	if pkg == nil {
 		return false
@@ -283,9 +534,10 @@ func shouldRecurse(callee *ssa.Function, _ string) bool {
 }
 
 // HasTestSyntax checks if a package's syntax trees contain test
-// function declarations (func Test*(*testing.T)). This is used by
-// both the CLI layer and tests to select the correct package variant
-// when loading with Tests=true.
+// function declarations (func Test*(*testing.T), Fuzz*(*testing.F),
+// or Example*() with an "// Output:" comment). This is used by both
+// the CLI layer and tests to select the correct package variant when
+// loading with Tests=true.
 func HasTestSyntax(pkg *packages.Package) bool {
 	for _, file := range pkg.Syntax {
 		for _, decl := range file.Decls {
@@ -293,7 +545,10 @@ func HasTestSyntax(pkg *packages.Package) bool {
 			if !ok {
 				continue
 			}
-			if strings.HasPrefix(fn.Name.Name, "Test") {
+			if strings.HasPrefix(fn.Name.Name, "Test") || isFuzzFunction(fn) {
+				return true
+			}
+			if isExampleFunction(fn) && exampleOutputComment(file, fn) != nil {
 				return true
 			}
 		}
@@ -301,39 +556,29 @@ func HasTestSyntax(pkg *packages.Package) bool {
 	return false
 }
 
-// qualifiedSSAName returns a qualified name for an SSA function
-// matching the FunctionTarget.QualifiedName() format.
-//
-// For methods, the analysis module formats receiver types as
-// "(*Type).Method" for pointer receivers and "Type.Method" for
-// value receivers. SSA may present the receiver differently (e.g.,
-// as a value parameter even for pointer-receiver methods). To match,
-// we check whether the receiver type is a pointer using go/types.
-func qualifiedSSAName(fn *ssa.Function) string {
-	if fn.Signature.Recv() == nil {
-		return fn.Name()
-	}
-
-	recvType := fn.Signature.Recv().Type()
-	typeName := ""
-	isPtr := false
-
-	// Unwrap the pointer if present.
-	if ptr, ok := recvType.(*types.Pointer); ok {
-		isPtr = true
-		recvType = ptr.Elem()
-	}
-
-	// Get the type name (strip package path).
-	typeStr := recvType.String()
-	if idx := strings.LastIndex(typeStr, "."); idx >= 0 {
-		typeName = typeStr[idx+1:]
-	} else {
-		typeName = typeStr
+// newInferredTarget returns the target for a callee, identified by
+// the source function it was built from: the generic function of an
+// instantiation, or the declared method of a wrapper. The receiver is
+// written as in the declaration ("Store", "*Store", "*Cache[K]"), so
+// the ID and name match those of the analysis results.
+func newInferredTarget(fn *ssa.Function) InferredTarget {
+	t := InferredTarget{SSAFunc: fn}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || obj.Pkg() == nil {
+		t.FuncName = fn.Name()
+		if pkg := fn.Package(); pkg != nil {
+			t.Package = pkg.Pkg.Path()
+		}
+		t.ID = taxonomy.NewFunctionID(t.Package, "", t.FuncName)
+		return t
 	}
-
-	if isPtr {
-		return fmt.Sprintf("(*%s).%s", typeName, fn.Name())
+	obj = obj.Origin()
+	t.Package = obj.Pkg().Path()
+	recv := ""
+	if r := obj.Type().(*types.Signature).Recv(); r != nil {
+		recv = types.TypeString(r.Type(), types.RelativeTo(obj.Pkg()))
 	}
-	return fmt.Sprintf("%s.%s", typeName, fn.Name())
+	t.ID = taxonomy.NewFunctionID(t.Package, recv, obj.Name())
+	t.FuncName = taxonomy.FunctionTarget{Receiver: recv, Function: obj.Name()}.QualifiedName()
+	return t
 }
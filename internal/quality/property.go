@@ -0,0 +1,290 @@
+package quality
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+)
+
+// Property-based tests check invariants over generated inputs rather
+// than fixed examples. Three forms are recognized:
+//
+//   - Fuzz targets: f.Fuzz(func(t *testing.T, ...) { ... }) inside a
+//     func FuzzXxx(f *testing.F).
+//   - testing/quick: quick.Check(prop, cfg), where prop returns
+//     whether the invariant holds, and quick.CheckEqual(f, g, cfg),
+//     which asserts that f and g agree on every input.
+//   - pgregory.net/rapid: rapid.Check(t, func(t *rapid.T) { ... }).
+//
+// Assertions inside the invariant functions are reported with
+// AssertionKindProperty so that coverage achieved through invariants
+// can be told apart from coverage achieved through fixed examples.
+
+// propertyChecker identifies a call that runs a property-based check.
+type propertyChecker string
+
+// Property checker constants.
+const (
+	checkerNone       propertyChecker = ""
+	checkerFuzz       propertyChecker = "fuzz"
+	checkerQuick      propertyChecker = "quick.Check"
+	checkerQuickEqual propertyChecker = "quick.CheckEqual"
+	checkerRapid      propertyChecker = "rapid.Check"
+)
+
+// classifyPropertyCall reports which property checker call invokes,
+// or checkerNone if it is not a property-based check.
+func classifyPropertyCall(info *types.Info, call *ast.CallExpr) propertyChecker {
+	if info == nil {
+		return checkerNone
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return checkerNone
+	}
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return checkerNone
+	}
+	return propertyCheckerFor(fn.Pkg().Path(), fn.Name())
+}
+
+// propertyCheckerFor maps a function's package path and name to the
+// property checker it implements.
+func propertyCheckerFor(pkgPath, name string) propertyChecker {
+	switch {
+	case pkgPath == "testing" && name == "Fuzz":
+		return checkerFuzz
+	case pkgPath == "testing/quick" && name == "Check":
+		return checkerQuick
+	case pkgPath == "testing/quick" && name == "CheckEqual":
+		return checkerQuickEqual
+	case pkgPath == "pgregory.net/rapid" && name == "Check":
+		return checkerRapid
+	}
+	return checkerNone
+}
+
+// isPropertyCheckerFunc reports whether an SSA callee runs a
+// property-based check over the functions passed to it.
+func isPropertyCheckerFunc(fn *ssa.Function) bool {
+	if fn == nil || fn.Pkg == nil {
+		return false
+	}
+	return propertyCheckerFor(fn.Pkg.Pkg.Path(), fn.Name()) != checkerNone
+}
+
+// hasPropertyCheck reports whether a test function body calls a
+// testing/quick or rapid checker.
+func hasPropertyCheck(pkg *packages.Package, fn *ast.FuncDecl) bool {
+	if fn.Body == nil {
+		return false
+	}
+	found := false
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if call, ok := n.(*ast.CallExpr); ok {
+			switch classifyPropertyCall(pkg.TypesInfo, call) {
+			case checkerQuick, checkerQuickEqual, checkerRapid:
+				found = true
+			}
+		}
+		return !found
+	})
+	return found
+}
+
+// markPropertySites returns sites with every assertion that lies in an
+// invariant function re-kinded as AssertionKindProperty, plus the
+// invariants that testing/quick checks without an explicit assertion:
+// the boolean results returned by a quick.Check property, and the
+// quick.CheckEqual call itself.
+func (d *assertionDetector) markPropertySites(fn *ast.FuncDecl, sites []AssertionSite) []AssertionSite {
+	if fn.Body == nil {
+		return sites
+	}
+
+	var invariants []ast.Node
+	var extra []AssertionSite
+	checkErrs := make(map[types.Object]bool)
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if assign, ok := n.(*ast.AssignStmt); ok {
+			d.recordCheckErrors(assign, checkErrs)
+			return true
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		checker := classifyPropertyCall(d.pkg.TypesInfo, call)
+		switch checker {
+		case checkerFuzz, checkerRapid:
+			for _, arg := range call.Args {
+				if lit, ok := arg.(*ast.FuncLit); ok {
+					invariants = append(invariants, lit)
+				}
+			}
+		case checkerQuick:
+			if len(call.Args) > 0 {
+				if body := d.propertyBody(fn, call.Args[0]); body != nil {
+					invariants = append(invariants, body)
+					extra = append(extra, d.propertyResults(fn, body)...)
+				}
+			}
+		case checkerQuickEqual:
+			extra = append(extra, AssertionSite{
+				Location: d.posString(call.Pos()),
+				Kind:     AssertionKindProperty,
+				FuncDecl: fn,
+				Expr:     call,
+			})
+		}
+		return true
+	})
+	if len(invariants) == 0 && len(extra) == 0 {
+		return sites
+	}
+
+	kept := sites[:0]
+	for _, site := range sites {
+		if site.Depth > 0 || site.Expr == nil {
+			kept = append(kept, site)
+			continue
+		}
+		// The error a checker returns only reports that an invariant
+		// failed; the invariant itself is already a property site.
+		if site.Kind == AssertionKindStdlibErrorCheck && d.checksObject(site.Expr, checkErrs) {
+			continue
+		}
+		for _, inv := range invariants {
+			if inv.Pos() <= site.Expr.Pos() && site.Expr.End() <= inv.End() {
+				site.Kind = AssertionKindProperty
+				break
+			}
+		}
+		kept = append(kept, site)
+	}
+	return append(kept, extra...)
+}
+
+// recordCheckErrors adds to errs the variables assigned the result of
+// a quick.Check or quick.CheckEqual call (err := quick.Check(...)).
+func (d *assertionDetector) recordCheckErrors(assign *ast.AssignStmt, errs map[types.Object]bool) {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return
+	}
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	switch classifyPropertyCall(d.pkg.TypesInfo, call) {
+	case checkerQuick, checkerQuickEqual:
+	default:
+		return
+	}
+	if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+		if obj := d.pkg.TypesInfo.ObjectOf(ident); obj != nil {
+			errs[obj] = true
+		}
+	}
+}
+
+// checksObject reports whether expr refers to any object in objs.
+func (d *assertionDetector) checksObject(expr ast.Expr, objs map[types.Object]bool) bool {
+	if len(objs) == 0 {
+		return false
+	}
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if ident, ok := n.(*ast.Ident); ok && objs[d.pkg.TypesInfo.Uses[ident]] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// propertyBody resolves the property passed to quick.Check to its
+// function literal, to the literal assigned to a local variable of
+// fn, or to the declaration of a named function in the test package.
+func (d *assertionDetector) propertyBody(fn *ast.FuncDecl, arg ast.Expr) ast.Node {
+	switch a := arg.(type) {
+	case *ast.FuncLit:
+		return a
+	case *ast.Ident:
+		if lit := localFuncLit(d.pkg.TypesInfo, fn, a); lit != nil {
+			return lit
+		}
+		if decl := d.findFuncDecl(a.Name); decl != nil && decl.Body != nil {
+			return decl
+		}
+	}
+	return nil
+}
+
+// localFuncLit returns the function literal assigned to the local
+// variable ident refers to (prop := func(n int) bool { ... }), or nil.
+func localFuncLit(info *types.Info, fn *ast.FuncDecl, ident *ast.Ident) *ast.FuncLit {
+	if info == nil {
+		return nil
+	}
+	obj, ok := info.Uses[ident].(*types.Var)
+	if !ok {
+		return nil
+	}
+	var lit *ast.FuncLit
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || lit != nil || len(assign.Lhs) != len(assign.Rhs) {
+			return lit == nil
+		}
+		for i, lhs := range assign.Lhs {
+			id, ok := lhs.(*ast.Ident)
+			if !ok || info.Defs[id] != obj {
+				continue
+			}
+			if fl, ok := assign.Rhs[i].(*ast.FuncLit); ok {
+				lit = fl
+			}
+		}
+		return lit == nil
+	})
+	return lit
+}
+
+// propertyResults returns an assertion site for every non-constant
+// result returned by a quick.Check property: the property holds only
+// if each returned expression evaluates to true.
+func (d *assertionDetector) propertyResults(fn *ast.FuncDecl, prop ast.Node) []AssertionSite {
+	var body *ast.BlockStmt
+	switch p := prop.(type) {
+	case *ast.FuncLit:
+		body = p.Body
+	case *ast.FuncDecl:
+		body = p.Body
+	}
+	var sites []AssertionSite
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false // returns of nested closures are not the property's
+		case *ast.ReturnStmt:
+			if len(node.Results) != 1 {
+				return true
+			}
+			if ident, ok := node.Results[0].(*ast.Ident); ok &&
+				(ident.Name == "true" || ident.Name == "false") {
+				return true
+			}
+			sites = append(sites, AssertionSite{
+				Location: d.posString(node.Pos()),
+				Kind:     AssertionKindProperty,
+				FuncDecl: fn,
+				Expr:     node.Results[0],
+			})
+		}
+		return true
+	})
+	return sites
+}
@@ -36,6 +36,13 @@ type Options struct {
 	// ambiguity, and other non-fatal issues. If nil, warnings are
 	// suppressed.
 	Stderr io.Writer
+
+	// LineExecuted is an optional lookup into a coverage profile
+	// that reports whether the statement at file:line ran during
+	// the test run, and whether the profile covers that line at all.
+	// When provided, contractual effects whose location was never
+	// executed are flagged in the function coverage.
+	LineExecuted func(file string, line int) (executed, known bool)
 }
 
 // DefaultOptions returns options with sensible defaults.
@@ -56,11 +63,36 @@ func Assess(
 	testPkg *packages.Package,
 	opts Options,
 ) ([]taxonomy.QualityReport, *taxonomy.PackageSummary, error) {
-	start := time.Now()
-
 	if testPkg == nil {
 		return nil, nil, fmt.Errorf("test package is nil")
 	}
+	return AssessPackages(results, []*packages.Package{testPkg}, opts)
+}
+
+// AssessPackages computes test quality metrics for tests spread over
+// several test packages: in-package tests, external `foo_test`
+// packages, and integration test packages that have no production
+// code of their own. Every test is paired with targets in any
+// package that appears in results, so black-box tests count toward
+// the contract coverage of the code they actually exercise.
+//
+// Reports are returned in testPkgs order, then in source order of
+// the test functions within each package.
+func AssessPackages(
+	results []taxonomy.AnalysisResult,
+	testPkgs []*packages.Package,
+	opts Options,
+) ([]taxonomy.QualityReport, *taxonomy.PackageSummary, error) {
+	start := time.Now()
+
+	if len(testPkgs) == 0 {
+		return nil, nil, fmt.Errorf("no test packages to assess")
+	}
+	for _, testPkg := range testPkgs {
+		if testPkg == nil {
+			return nil, nil, fmt.Errorf("test package is nil")
+		}
+	}
 	if opts.MaxHelperDepth <= 0 {
 		opts.MaxHelperDepth = 3
 	}
@@ -72,27 +104,81 @@ func Assess(
 		Timestamp:   start,
 	}
 
-	// Build a lookup from qualified function name to analysis result.
-	resultMap := make(map[string]*taxonomy.AnalysisResult)
+	// Build a lookup from package-qualified function name to
+	// analysis result, and the set of analyzed packages that tests
+	// may target.
+	resultMap := make(map[taxonomy.FunctionID]*taxonomy.AnalysisResult)
+	scope := make(map[string]bool)
 	for i := range results {
-		key := results[i].Target.QualifiedName()
-		resultMap[key] = &results[i]
+		resultMap[results[i].Target.FunctionID()] = &results[i]
+		scope[results[i].Target.Package] = true
+	}
+
+	var reports []taxonomy.QualityReport
+	foundTests := false
+
+	for _, testPkg := range testPkgs {
+		// Step 1: Find test functions in the test package.
+		testFuncs := FindTestFunctions(testPkg)
+		if len(testFuncs) == 0 {
+			continue
+		}
+		foundTests = true
+
+		pkgReports, err := assessTestPackage(testPkg, testFuncs, resultMap, scope, meta, opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		reports = append(reports, pkgReports...)
 	}
 
-	// Step 1: Find test functions in the test package.
-	testFuncs := FindTestFunctions(testPkg)
-	if len(testFuncs) == 0 {
+	if !foundTests {
 		if opts.Stderr != nil {
 			_, _ = fmt.Fprintln(opts.Stderr, "warning: no test functions found")
 		}
-		return nil, &taxonomy.PackageSummary{}, nil
 	}
 
-	// Step 2: Build SSA for the test package.
-	_, ssaPkg, err := BuildTestSSA(testPkg)
+	// Functions with contractual effects that no test reaches are
+	// reported separately and count as 0% in the summary average.
+	candidates := results
+	if opts.TargetFunc != "" {
+		candidates = nil
+		for _, r := range results {
+			if r.Target.QualifiedName() == opts.TargetFunc {
+				candidates = append(candidates, r)
+			}
+		}
+	}
+
+	summary := BuildPackageSummary(reports)
+	summary.FunctionCoverage = BuildFunctionCoverage(results, reports)
+	applyUntestedContracts(summary, BuildUntestedContracts(candidates, reports))
+	if opts.LineExecuted != nil {
+		summary.NeverExecutedContracts = MarkNeverExecuted(summary.FunctionCoverage, results, opts.LineExecuted) +
+			MarkNeverExecuted(summary.UntestedContracts, results, opts.LineExecuted)
+	}
+	return reports, summary, nil
+}
+
+// assessTestPackage runs target inference, assertion detection,
+// mapping, and metric computation for every test function of a
+// single test package.
+func assessTestPackage(
+	testPkg *packages.Package,
+	testFuncs []TestFunc,
+	resultMap map[taxonomy.FunctionID]*taxonomy.AnalysisResult,
+	scope map[string]bool,
+	meta taxonomy.Metadata,
+	opts Options,
+) ([]taxonomy.QualityReport, error) {
+	// Step 2: Build SSA for the test package, plus a VTA call graph
+	// so that interface and function-value calls in tests resolve to
+	// the concrete functions they exercise.
+	prog, ssaPkg, err := BuildTestSSA(testPkg)
 	if err != nil {
-		return nil, nil, fmt.Errorf("building test SSA: %w", err)
+		return nil, fmt.Errorf("building test SSA: %w", err)
 	}
+	cg := BuildTestCallGraph(prog, testPkg.PkgPath)
 
 	// Step 3: For each test function, infer the target, detect
 	// assertions, map them, and compute metrics.
@@ -105,7 +191,7 @@ func Assess(
 		}
 
 		// Infer the target function.
-		targets, warnings := InferTargets(ssaFunc, testPkg, opts)
+		targets, warnings := inferTargets(ssaFunc, scope, cg, opts)
 		for _, w := range warnings {
 			if opts.Stderr != nil {
 				_, _ = fmt.Fprintf(opts.Stderr, "warning: %s: %s\n", tf.Name, w)
@@ -134,7 +220,7 @@ func Assess(
 		// Compute quality report for each target.
 		for _, target := range targets {
 			pairStart := time.Now()
-			result, ok := resultMap[target.FuncName]
+			result, ok := resultMap[target.ID]
 			if !ok {
 				// Target function was not in the analysis results.
 				if opts.Stderr != nil {
@@ -149,8 +235,8 @@ func Assess(
 			sites := DetectAssertions(tf.Decl, testPkg, opts.MaxHelperDepth)
 
 			// Map assertions to side effects via SSA data flow.
-			mappings, unmapped, discardedIDs := MapAssertionsToEffects(
-				ssaFunc, target.SSAFunc, sites, result.SideEffects, testPkg,
+			mappings, unmapped, discardedIDs := MapAssertionsToEffectsWithCallGraph(
+				ssaFunc, target.SSAFunc, sites, result.SideEffects, testPkg, cg,
 			)
 
 			// Compute metrics, including discarded return detection.
@@ -165,6 +251,8 @@ func Assess(
 
 			report := taxonomy.QualityReport{
 				TestFunction:                 tf.Name,
+				TestPackage:                  testPkg.PkgPath,
+				TestKind:                     string(tf.Kind),
 				TestLocation:                 tf.Location,
 				TargetFunction:               result.Target,
 				ContractCoverage:             coverage,
@@ -172,6 +260,9 @@ func Assess(
 				AmbiguousEffects:             ambiguous,
 				UnmappedAssertions:           unmapped,
 				AssertionDetectionConfidence: detectionConf,
+				Subtests:                     computeSubtestCoverage(tf.Decl, testPkg, sites, mappings, result.SideEffects),
+				ValueCoverage:                computeValueCoverage(tf.Decl, testPkg, sites, mappings, result.SideEffects),
+				FieldCoverage:                computeFieldCoverage(testPkg, result.Target, sites, mappings, result.SideEffects),
 				Metadata: taxonomy.Metadata{
 					GazeVersion: meta.GazeVersion,
 					GoVersion:   meta.GoVersion,
@@ -183,8 +274,7 @@ func Assess(
 		}
 	}
 
-	summary := BuildPackageSummary(reports)
-	return reports, summary, nil
+	return reports, nil
 }
 
 // BuildPackageSummary aggregates QualityReports into a PackageSummary.
@@ -231,6 +321,18 @@ func BuildPackageSummary(reports []taxonomy.QualityReport) *taxonomy.PackageSumm
 	}
 }
 
+// applyUntestedContracts records untested functions on the summary
+// and folds them into the average contract coverage as 0% entries,
+// so that a package does not look better for having fewer tests.
+func applyUntestedContracts(summary *taxonomy.PackageSummary, untested []taxonomy.FunctionCoverage) {
+	if len(untested) == 0 {
+		return
+	}
+	summary.UntestedContracts = untested
+	n := float64(summary.TotalTests)
+	summary.AverageContractCoverage = summary.AverageContractCoverage * n / (n + float64(len(untested)))
+}
+
 // collectDiscardedReturns filters side effects to those whose IDs
 // appear in the discarded set. These are return/error effects whose
 // values were explicitly discarded (e.g., _ = target()), making
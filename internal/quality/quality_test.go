@@ -9,6 +9,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"testing"
@@ -92,11 +93,19 @@ func loadNonTestPackage(pkgName string) (*packages.Package, error) {
 // expensive shared fixtures per Go testing conventions.
 var testFixtureCache *fixtureCache
 
+// fixtureCacheSize bounds the number of fixtures the cache holds.
+// Each fixture is type-checked together with its dependencies from
+// source, so keeping every fixture for the whole run exhausts memory.
+// Tests using the same fixture sit next to each other, so keeping the
+// few most recently used ones preserves nearly all of the reuse.
+const fixtureCacheSize = 4
+
 // fixtureCache is a concurrency-safe cache for loaded test packages.
 // It is initialized once in TestMain and cleared after all tests run.
 type fixtureCache struct {
 	mu      sync.Mutex
 	entries map[string]*packages.Package
+	recent  []string // cached fixture names, least recently used first
 }
 
 func newFixtureCache() *fixtureCache {
@@ -108,6 +117,7 @@ func (c *fixtureCache) get(pkgName string) (*packages.Package, error) {
 	defer c.mu.Unlock()
 
 	if pkg, ok := c.entries[pkgName]; ok {
+		c.touch(pkgName)
 		return pkg, nil
 	}
 
@@ -116,10 +126,26 @@ func (c *fixtureCache) get(pkgName string) (*packages.Package, error) {
 		return nil, err
 	}
 
+	if len(c.recent) == fixtureCacheSize {
+		delete(c.entries, c.recent[0])
+		c.recent = c.recent[1:]
+	}
 	c.entries[pkgName] = pkg
+	c.recent = append(c.recent, pkgName)
 	return pkg, nil
 }
 
+// touch marks pkgName as the most recently used fixture.
+func (c *fixtureCache) touch(pkgName string) {
+	for i, name := range c.recent {
+		if name == pkgName {
+			c.recent = append(c.recent[:i], c.recent[i+1:]...)
+			break
+		}
+	}
+	c.recent = append(c.recent, pkgName)
+}
+
 // TestMain initializes the fixture cache before tests run and
 // clears it afterward. This avoids package-level mutable state.
 func TestMain(m *testing.M) {
@@ -183,6 +209,146 @@ func TestFindTestFunctions_NoTests(t *testing.T) {
 	}
 }
 
+func TestFindTestFunctions_Examples(t *testing.T) {
+	pkg := loadPkg(t, "examples")
+	tests := quality.FindTestFunctions(pkg)
+
+	kinds := make(map[string]quality.TestKind)
+	for _, tf := range tests {
+		kinds[tf.Name] = tf.Kind
+	}
+
+	for _, name := range []string{"ExampleFormat", "ExampleParse", "ExampleRender", "ExampleNewConfig"} {
+		kind, ok := kinds[name]
+		if !ok {
+			t.Errorf("expected example %q to be found; found: %v", name, kinds)
+			continue
+		}
+		if kind != quality.TestKindExample {
+			t.Errorf("%s: expected kind %q, got %q", name, quality.TestKindExample, kind)
+		}
+	}
+
+	// Examples without an output comment are compiled but never
+	// run, so they carry no assertions.
+	if _, ok := kinds["ExampleJoin"]; ok {
+		t.Error("ExampleJoin has no output comment and should not be treated as a test")
+	}
+}
+
+func TestAssess_ExampleOutput(t *testing.T) {
+	reports, _ := assessFixture(t, "examples")
+
+	tests := []struct {
+		test   string
+		target string
+	}{
+		{"ExampleFormat", "Format"},
+		{"ExampleParse", "Parse"},
+		{"ExampleRender", "Render"},
+		// A printed field of the result is an observation of it.
+		{"ExampleNewConfig", "NewConfig"},
+	}
+	for _, tt := range tests {
+		r := findReport(t, reports, tt.test, tt.target)
+		if r == nil {
+			continue
+		}
+		if r.ContractCoverage.Percentage != 100 {
+			t.Errorf("%s: expected 100%% contract coverage, got %.0f%% (gaps: %v)",
+				tt.test, r.ContractCoverage.Percentage, r.ContractCoverage.Gaps)
+		}
+	}
+}
+
+// TestAssess_MethodReceivers verifies that value-receiver methods
+// and methods of generic types pair with their analysis results,
+// joined on the FunctionID analysis derives from the declaration.
+func TestAssess_MethodReceivers(t *testing.T) {
+	reports, _ := assessFixture(t, "receivers")
+
+	const pkg = "github.com/unbound-force/gaze/internal/quality/testdata/src/receivers"
+	tests := []struct {
+		test   string
+		target string
+		id     taxonomy.FunctionID
+	}{
+		{"TestPoint_Add", "Add", pkg + ".(Point).Add"},
+		{"TestStack_Len", "Len", pkg + ".(*Stack[T]).Len"},
+		{"TestStack_Peek", "Peek", pkg + ".(Stack[T]).Peek"},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, tt.target)
+		if report == nil {
+			continue
+		}
+		if got := report.TargetFunction.FunctionID(); got != tt.id {
+			t.Errorf("%s: target = %s, want %s", tt.test, got, tt.id)
+		}
+		if report.ContractCoverage.Percentage != 100 {
+			t.Errorf("%s: expected 100%% contract coverage, got %.0f%% (gaps: %v)",
+				tt.test, report.ContractCoverage.Percentage, report.ContractCoverage.Gaps)
+		}
+	}
+}
+
+func TestFindTestFunctions_FuzzAndProperty(t *testing.T) {
+	pkg := loadPkg(t, "property")
+	tests := quality.FindTestFunctions(pkg)
+
+	kinds := make(map[string]quality.TestKind)
+	for _, tf := range tests {
+		kinds[tf.Name] = tf.Kind
+	}
+
+	want := map[string]quality.TestKind{
+		"FuzzReverse":         quality.TestKindFuzz,
+		"TestAbs_Property":    quality.TestKindProperty,
+		"TestDouble_Property": quality.TestKindProperty,
+	}
+	for name, kind := range want {
+		got, ok := kinds[name]
+		if !ok {
+			t.Errorf("expected %q to be found; found: %v", name, kinds)
+			continue
+		}
+		if got != kind {
+			t.Errorf("%s: expected kind %q, got %q", name, kind, got)
+		}
+	}
+}
+
+func TestAssess_PropertyInvariants(t *testing.T) {
+	reports, _ := assessFixture(t, "property")
+
+	tests := []struct {
+		test   string
+		target string
+		kind   string
+	}{
+		{"FuzzReverse", "Reverse", "fuzz"},
+		{"TestAbs_Property", "Abs", "property"},
+		{"TestDouble_Property", "Double", "property"},
+	}
+	for _, tt := range tests {
+		r := findReport(t, reports, tt.test, tt.target)
+		if r == nil {
+			continue
+		}
+		if r.TestKind != tt.kind {
+			t.Errorf("%s: expected test kind %q, got %q", tt.test, tt.kind, r.TestKind)
+		}
+		if r.ContractCoverage.CoveredCount == 0 {
+			t.Errorf("%s: expected the invariant to cover the return value, got 0 covered (gaps: %v)",
+				tt.test, r.ContractCoverage.Gaps)
+		}
+		if len(r.ContractCoverage.PropertyCovered) != r.ContractCoverage.CoveredCount {
+			t.Errorf("%s: expected every covered effect to be property-covered, got %v of %d",
+				tt.test, r.ContractCoverage.PropertyCovered, r.ContractCoverage.CoveredCount)
+		}
+	}
+}
+
 func TestBuildTestSSA_Success(t *testing.T) {
 	pkg := loadPkg(t, "welltested")
 	prog, ssaPkg, err := quality.BuildTestSSA(pkg)
@@ -253,6 +419,74 @@ func TestInferTargets_NoTarget(t *testing.T) {
 	}
 }
 
+// inferTargetNames runs call-graph-aware target inference for the
+// named test function in a fixture and returns the inferred names.
+func inferTargetNames(t *testing.T, fixture, testName string) []string {
+	t.Helper()
+	pkg := loadPkg(t, fixture)
+	prog, ssaPkg, err := quality.BuildTestSSA(pkg)
+	if err != nil {
+		t.Fatalf("BuildTestSSA failed: %v", err)
+	}
+	ssaFunc := ssaPkg.Func(testName)
+	if ssaFunc == nil {
+		t.Fatalf("expected to find SSA function %s", testName)
+	}
+	cg := quality.BuildTestCallGraph(prog, pkg.PkgPath)
+	targets, _ := quality.InferTargetsWithCallGraph(ssaFunc, pkg, cg, quality.DefaultOptions())
+	names := make([]string, 0, len(targets))
+	for _, target := range targets {
+		names = append(names, target.FuncName)
+	}
+	return names
+}
+
+func TestInferTargets_InterfaceCall(t *testing.T) {
+	names := inferTargetNames(t, "ifacecall", "TestStore_PutGet")
+	want := []string{"(*MemStore).Get", "(*MemStore).Put", "NewMemStore"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("targets = %v, want %v", names, want)
+	}
+
+	// Without a call graph, only the static constructor call pairs.
+	pkg := loadPkg(t, "ifacecall")
+	_, ssaPkg, err := quality.BuildTestSSA(pkg)
+	if err != nil {
+		t.Fatalf("BuildTestSSA failed: %v", err)
+	}
+	targets, _ := quality.InferTargets(ssaPkg.Func("TestStore_PutGet"), pkg, quality.DefaultOptions())
+	if len(targets) != 1 || targets[0].FuncName != "NewMemStore" {
+		t.Errorf("static-only targets = %v, want [NewMemStore]", targets)
+	}
+}
+
+func TestInferTargets_FunctionValueCall(t *testing.T) {
+	names := inferTargetNames(t, "ifacecall", "TestTransforms")
+	want := []string{"Double", "Square"}
+	if strings.Join(names, ",") != strings.Join(want, ",") {
+		t.Errorf("targets = %v, want %v", names, want)
+	}
+}
+
+func TestAssess_InterfaceCallCoverage(t *testing.T) {
+	reports, _ := assessFixture(t, "ifacecall")
+
+	coverage := make(map[string]float64)
+	for _, r := range reports {
+		if r.TestFunction == "TestStore_PutGet" {
+			coverage[r.TargetFunction.QualifiedName()] = r.ContractCoverage.Percentage
+		}
+	}
+	// Get's value and error are both asserted through the interface.
+	if got, ok := coverage["(*MemStore).Get"]; !ok || got != 100 {
+		t.Errorf("(*MemStore).Get coverage = %v (found=%v), want 100", got, ok)
+	}
+	// Put's error return is asserted via `if err := s.Put(...)`.
+	if _, ok := coverage["(*MemStore).Put"]; !ok {
+		t.Error("expected a report pairing TestStore_PutGet with (*MemStore).Put")
+	}
+}
+
 // --- Phase 3 Tests: Assertion Detection ---
 
 func TestDetectAssertions_StdlibComparison(t *testing.T) {
@@ -496,6 +730,203 @@ func TestComputeContractCoverage_NoContractualEffects(t *testing.T) {
 
 // --- Phase 5 Tests: Over-Specification ---
 
+func TestBuildFunctionCoverage_UnionAcrossTests(t *testing.T) {
+	target := taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Divide"}
+	valueEffect := taxonomy.SideEffect{ID: "se-001", Type: taxonomy.ReturnValue, Classification: &taxonomy.Classification{Label: taxonomy.Contractual}}
+	errEffect := taxonomy.SideEffect{ID: "se-002", Type: taxonomy.ErrorReturn, Classification: &taxonomy.Classification{Label: taxonomy.Contractual}}
+	logEffect := taxonomy.SideEffect{ID: "se-003", Type: taxonomy.LogWrite, Classification: &taxonomy.Classification{Label: taxonomy.Incidental}}
+	results := []taxonomy.AnalysisResult{
+		{Target: target, SideEffects: []taxonomy.SideEffect{valueEffect, errEffect, logEffect}},
+		{Target: taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Untested"}},
+	}
+	reports := []taxonomy.QualityReport{
+		{
+			TestFunction:     "TestDivide",
+			TargetFunction:   target,
+			ContractCoverage: taxonomy.ContractCoverage{Percentage: 50, Gaps: []taxonomy.SideEffect{errEffect}},
+		},
+		{
+			TestFunction:     "TestDivide_ZeroError",
+			TargetFunction:   target,
+			ContractCoverage: taxonomy.ContractCoverage{Percentage: 50, Gaps: []taxonomy.SideEffect{valueEffect}},
+		},
+	}
+
+	fcs := quality.BuildFunctionCoverage(results, reports)
+
+	if len(fcs) != 1 {
+		t.Fatalf("expected 1 function coverage entry, got %d", len(fcs))
+	}
+	fc := fcs[0]
+	if fc.ContractCoverage.Percentage != 100 {
+		t.Errorf("expected union coverage of 100%%, got %.0f%%", fc.ContractCoverage.Percentage)
+	}
+	if fc.ContractCoverage.TotalContractual != 2 || len(fc.ContractCoverage.Gaps) != 0 {
+		t.Errorf("expected 2 contractual effects and no gaps, got %+v", fc.ContractCoverage)
+	}
+	if got := strings.Join(fc.Tests, ","); got != "TestDivide,TestDivide_ZeroError" {
+		t.Errorf("tests = %s", got)
+	}
+	if len(fc.Effects) != 2 {
+		t.Fatalf("expected 2 contractual effect entries, got %d", len(fc.Effects))
+	}
+	if got := strings.Join(fc.Effects[0].CoveredBy, ","); got != "TestDivide" {
+		t.Errorf("ReturnValue covered by %q, want TestDivide", got)
+	}
+	if got := strings.Join(fc.Effects[1].CoveredBy, ","); got != "TestDivide_ZeroError" {
+		t.Errorf("ErrorReturn covered by %q, want TestDivide_ZeroError", got)
+	}
+}
+
+func TestBuildFunctionCoverage_SharedGap(t *testing.T) {
+	target := taxonomy.FunctionTarget{Package: "example.com/store", Function: "Set", Receiver: "*Store"}
+	mutation := taxonomy.SideEffect{ID: "se-010", Type: taxonomy.ReceiverMutation}
+	reports := []taxonomy.QualityReport{
+		{TestFunction: "TestSet", TargetFunction: target, ContractCoverage: taxonomy.ContractCoverage{Gaps: []taxonomy.SideEffect{mutation}}},
+		{TestFunction: "TestSet_Twice", TargetFunction: target, ContractCoverage: taxonomy.ContractCoverage{Gaps: []taxonomy.SideEffect{mutation}}},
+	}
+	results := []taxonomy.AnalysisResult{{Target: target, SideEffects: []taxonomy.SideEffect{mutation}}}
+
+	fcs := quality.BuildFunctionCoverage(results, reports)
+
+	if len(fcs) != 1 {
+		t.Fatalf("expected 1 function coverage entry, got %d", len(fcs))
+	}
+	if fcs[0].ContractCoverage.Percentage != 0 || len(fcs[0].ContractCoverage.Gaps) != 1 {
+		t.Errorf("expected an uncovered mutation, got %+v", fcs[0].ContractCoverage)
+	}
+	if len(fcs[0].Effects[0].CoveredBy) != 0 {
+		t.Errorf("expected no covering tests, got %v", fcs[0].Effects[0].CoveredBy)
+	}
+}
+
+func TestBuildUntestedContracts(t *testing.T) {
+	tested := taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Add"}
+	untested := taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Sub"}
+	results := []taxonomy.AnalysisResult{
+		{Target: tested, SideEffects: []taxonomy.SideEffect{{ID: "se-001", Type: taxonomy.ReturnValue}}},
+		{Target: untested, SideEffects: []taxonomy.SideEffect{
+			{ID: "se-002", Type: taxonomy.ReturnValue},
+			{ID: "se-003", Type: taxonomy.LogWrite, Classification: &taxonomy.Classification{Label: taxonomy.Incidental}},
+		}},
+		// No contractual effects: not a contract, so not listed.
+		{Target: taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Noop"}},
+	}
+	reports := []taxonomy.QualityReport{{TestFunction: "TestAdd", TargetFunction: tested}}
+
+	fcs := quality.BuildUntestedContracts(results, reports)
+
+	if len(fcs) != 1 || fcs[0].Function.Function != "Sub" {
+		t.Fatalf("expected only Sub to be untested, got %+v", fcs)
+	}
+	cov := fcs[0].ContractCoverage
+	if cov.Percentage != 0 || cov.TotalContractual != 1 || len(cov.Gaps) != 1 || len(cov.GapHints) != 1 {
+		t.Errorf("expected 0%% coverage with one gap and hint, got %+v", cov)
+	}
+	if len(fcs[0].Tests) != 0 {
+		t.Errorf("expected no tests, got %v", fcs[0].Tests)
+	}
+}
+
+func TestMarkNeverExecuted(t *testing.T) {
+	target := taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Divide"}
+	results := []taxonomy.AnalysisResult{{Target: target, SideEffects: []taxonomy.SideEffect{
+		{ID: "se-001", Type: taxonomy.ReturnValue, Location: "/src/calc/calc.go:10:25"},
+		{ID: "se-002", Type: taxonomy.ErrorWrap, Location: "/src/calc/calc.go:12:10"},
+		{ID: "se-003", Type: taxonomy.LogWrite, Location: "/src/calc/other.go:3:2"},
+	}}}
+	fcs := []taxonomy.FunctionCoverage{{
+		Function: target,
+		Effects: []taxonomy.EffectCoverage{
+			{SideEffectID: "se-001", Type: taxonomy.ReturnValue},
+			{SideEffectID: "se-002", Type: taxonomy.ErrorWrap},
+			{SideEffectID: "se-003", Type: taxonomy.LogWrite},
+		},
+	}}
+
+	// Line 12 (the error branch) never ran; other.go is not in the
+	// profile at all.
+	lineExecuted := func(file string, line int) (bool, bool) {
+		if file != "/src/calc/calc.go" {
+			return false, false
+		}
+		return line != 12, true
+	}
+
+	flagged := quality.MarkNeverExecuted(fcs, results, lineExecuted)
+
+	if flagged != 1 {
+		t.Errorf("expected 1 effect flagged, got %d", flagged)
+	}
+	for _, ec := range fcs[0].Effects {
+		want := ec.SideEffectID == "se-002"
+		if ec.NeverExecuted != want {
+			t.Errorf("%s: NeverExecuted = %v, want %v", ec.SideEffectID, ec.NeverExecuted, want)
+		}
+	}
+}
+
+func TestMarkNeverExecuted_Occurrences(t *testing.T) {
+	target := taxonomy.FunctionTarget{Package: "example.com/calc", Function: "Parse"}
+	results := []taxonomy.AnalysisResult{{Target: target, SideEffects: []taxonomy.SideEffect{
+		// Returned on both paths: flagged only if neither ran.
+		{ID: "se-001", Type: taxonomy.ReturnValue, Location: "/src/calc/calc.go:5:1", Occurrences: []taxonomy.Occurrence{
+			{Location: "/src/calc/calc.go:8:3", Conditions: []string{"err != nil"}},
+			{Location: "/src/calc/calc.go:10:2", Conditions: []string{"err == nil"}},
+		}},
+		// Returned only on the error path.
+		{ID: "se-002", Type: taxonomy.ErrorReturn, Location: "/src/calc/calc.go:5:1", Occurrences: []taxonomy.Occurrence{
+			{Location: "/src/calc/calc.go:8:3", Conditions: []string{"err != nil"}},
+		}},
+	}}}
+	fcs := []taxonomy.FunctionCoverage{{
+		Function: target,
+		Effects: []taxonomy.EffectCoverage{
+			{SideEffectID: "se-001", Type: taxonomy.ReturnValue},
+			{SideEffectID: "se-002", Type: taxonomy.ErrorReturn},
+		},
+	}}
+
+	// The function ran, but its error branch (line 8) did not.
+	lineExecuted := func(_ string, line int) (bool, bool) {
+		return line != 8, true
+	}
+
+	if flagged := quality.MarkNeverExecuted(fcs, results, lineExecuted); flagged != 1 {
+		t.Errorf("expected 1 effect flagged, got %d", flagged)
+	}
+	for _, ec := range fcs[0].Effects {
+		want := ec.SideEffectID == "se-002"
+		if ec.NeverExecuted != want {
+			t.Errorf("%s: NeverExecuted = %v, want %v", ec.SideEffectID, ec.NeverExecuted, want)
+		}
+	}
+}
+
+func TestWriteText_UntestedContracts(t *testing.T) {
+	summary := &taxonomy.PackageSummary{
+		UntestedContracts: []taxonomy.FunctionCoverage{{
+			Function: taxonomy.FunctionTarget{Function: "Sub", Location: "calc.go:10:1"},
+			ContractCoverage: taxonomy.ContractCoverage{
+				TotalContractual: 1,
+				Gaps:             []taxonomy.SideEffect{{Type: taxonomy.ReturnValue, Description: "returns int"}},
+				GapHints:         []string{"got := target(); // assert got == expected"},
+			},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := quality.WriteText(&buf, nil, summary); err != nil {
+		t.Fatalf("WriteText failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"Untested Contracts", "Sub", "ReturnValue: returns int", "hint:", "Untested contracts: "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
 func TestComputeOverSpecification_None(t *testing.T) {
 	effects := []taxonomy.SideEffect{
 		{ID: "se-001", Type: taxonomy.ReturnValue, Classification: &taxonomy.Classification{Label: taxonomy.Contractual}},
@@ -540,6 +971,376 @@ func TestComputeOverSpecification_WithIncidental(t *testing.T) {
 	}
 }
 
+func TestAssess_MockExpectations(t *testing.T) {
+	reports, _ := assessFixture(t, "mocks")
+
+	tests := []struct {
+		test string
+		pct  float64
+	}{
+		{"TestRegister_Gomock", 100},
+		{"TestRegister_Strict", 100},
+		{"TestRegister_Testify", 100},
+		// An unverified testify On expectation is only a stub.
+		{"TestRegister_Unverified", 50},
+	}
+	for _, tt := range tests {
+		r := findReport(t, reports, tt.test, "Register")
+		if r == nil {
+			continue
+		}
+		if r.ContractCoverage.TotalContractual != 2 {
+			t.Errorf("%s: expected 2 interaction effects, got %d (gaps: %v)",
+				tt.test, r.ContractCoverage.TotalContractual, r.ContractCoverage.Gaps)
+		}
+		if r.ContractCoverage.Percentage != tt.pct {
+			t.Errorf("%s: expected %.0f%% of interactions covered by mock expectations, got %.0f%% (gaps: %v)",
+				tt.test, tt.pct, r.ContractCoverage.Percentage, r.ContractCoverage.Gaps)
+		}
+	}
+}
+
+func TestDetectAssertions_MockExpectationStrictness(t *testing.T) {
+	pkg := loadPkg(t, "mocks")
+
+	tests := []struct {
+		test   string
+		strict map[string]bool // mocked method -> strict
+	}{
+		{"TestRegister_Gomock", map[string]bool{"Save": false, "Notify": false}},
+		{"TestRegister_Strict", map[string]bool{"Save": true, "Notify": false}},
+		{"TestRegister_Testify", map[string]bool{"Save": false, "Notify": true}},
+		{"TestRegister_Unverified", map[string]bool{"Save": false}},
+	}
+	for _, tt := range tests {
+		var decl *ast.FuncDecl
+		for _, tf := range quality.FindTestFunctions(pkg) {
+			if tf.Name == tt.test {
+				decl = tf.Decl
+			}
+		}
+		if decl == nil {
+			t.Fatalf("%s not found", tt.test)
+		}
+
+		got := make(map[string]bool)
+		for _, s := range quality.DetectAssertions(decl, pkg, 3) {
+			if s.Kind == quality.AssertionKindMockExpectation {
+				got[s.MockMethod] = s.Strict
+			}
+		}
+		if len(got) != len(tt.strict) {
+			t.Errorf("%s: expected expectations on %v, got %v", tt.test, tt.strict, got)
+			continue
+		}
+		for method, strict := range tt.strict {
+			if got[method] != strict {
+				t.Errorf("%s: %s strict = %v, want %v", tt.test, method, got[method], strict)
+			}
+		}
+	}
+}
+
+func TestAssess_ErrorMatchCoversWrapping(t *testing.T) {
+	reports, _ := assessFixture(t, "errwrap")
+
+	tests := []struct {
+		test        string
+		wantWrapGap bool
+	}{
+		// errors.Is only passes if ErrNotFound was wrapped with %w.
+		{"TestGet_ErrorsIs", false},
+		// A nil check asserts the error but not what it wraps.
+		{"TestGet_NilCheck", true},
+		// errors.Is in a guard excludes a case instead of asserting it.
+		{"TestGet_GuardedFatal", true},
+		// ErrClosed is not the sentinel Get wraps.
+		{"TestGet_OtherSentinel", true},
+	}
+	for _, tt := range tests {
+		r := findReport(t, reports, tt.test, "Get")
+		if r == nil {
+			continue
+		}
+		wrapGap := false
+		for _, gap := range r.ContractCoverage.Gaps {
+			if gap.Type == taxonomy.ErrorReturn {
+				t.Errorf("%s: ErrorReturn reported as a gap", tt.test)
+			}
+			if gap.Type == taxonomy.ErrorWrap {
+				wrapGap = true
+			}
+		}
+		if wrapGap != tt.wantWrapGap {
+			t.Errorf("%s: ErrorWrap gap = %v, want %v (gaps: %v)",
+				tt.test, wrapGap, tt.wantWrapGap, r.ContractCoverage.Gaps)
+		}
+	}
+}
+
+// TestAssess_ErrorsAsCoversWrappedVariables verifies that errors.As
+// covers the wrapping of an error held in a variable, even one whose
+// name starts with "err", since only package-level variables are
+// sentinels that cannot carry a typed error.
+func TestAssess_ErrorsAsCoversWrappedVariables(t *testing.T) {
+	reports, _ := assessFixture(t, "errwrap")
+
+	tests := []struct {
+		test   string
+		target string
+	}{
+		{"TestWrap_ErrorsAs", "Wrap"},
+		{"TestWrapResponse_ErrorsAs", "WrapResponse"},
+	}
+	for _, tt := range tests {
+		r := findReport(t, reports, tt.test, tt.target)
+		if r == nil {
+			continue
+		}
+		for _, gap := range r.ContractCoverage.Gaps {
+			if gap.Type == taxonomy.ErrorWrap {
+				t.Errorf("%s: ErrorWrap reported as a gap", tt.test)
+			}
+		}
+	}
+}
+
+func TestAssess_CancellationCheck(t *testing.T) {
+	reports, _ := assessFixture(t, "cancellation")
+
+	tests := []struct {
+		test    string
+		wantGap bool
+	}{
+		// Cancelling the context and checking for context.Canceled
+		// asserts that Wait honours cancellation.
+		{"TestWait_Canceled", false},
+		// Running to completion never exercises cancellation.
+		{"TestWait_Completes", true},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, "Wait")
+		if report == nil {
+			continue
+		}
+		gap := false
+		for _, g := range report.ContractCoverage.Gaps {
+			if g.Type == taxonomy.RespectsCancellation {
+				gap = true
+			}
+		}
+		if gap != tt.wantGap {
+			t.Errorf("%s: RespectsCancellation gap = %v, want %v (gaps: %v)",
+				tt.test, gap, tt.wantGap, report.ContractCoverage.Gaps)
+		}
+	}
+}
+
+func TestAssess_HTTPResponseChecks(t *testing.T) {
+	reports, _ := assessFixture(t, "httphandler")
+
+	tests := []struct {
+		test    string
+		covered []string
+		gaps    []string
+	}{
+		// Each status check covers only the branch it pins down.
+		{"TestGetItem_NotFound", []string{"404"}, []string{"400", "200", "Content-Type"}},
+		// Checks read through locals and canonicalize header keys.
+		{"TestGetItem_OK", []string{"200", "Content-Type"}, []string{"400", "404"}},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, "GetItem")
+		if report == nil {
+			continue
+		}
+		gaps := make(map[string]bool)
+		for _, g := range report.ContractCoverage.Gaps {
+			if g.Type == taxonomy.HTTPStatusCode || g.Type == taxonomy.HTTPHeader {
+				gaps[g.Target] = true
+			}
+		}
+		for _, target := range tt.covered {
+			if gaps[target] {
+				t.Errorf("%s: %s reported as a gap, want covered", tt.test, target)
+			}
+		}
+		for _, target := range tt.gaps {
+			if !gaps[target] {
+				t.Errorf("%s: %s not reported as a gap (gaps: %v)", tt.test, target, report.ContractCoverage.Gaps)
+			}
+		}
+	}
+}
+
+func TestAssess_GRPCStatusChecks(t *testing.T) {
+	reports, _ := assessFixture(t, "grpcstatus")
+
+	tests := []struct {
+		test    string
+		covered []string
+		gaps    []string
+	}{
+		{"TestGetUser_NotFound", []string{"NotFound"}, []string{"InvalidArgument", "PermissionDenied"}},
+		// The code is read through FromError and a local.
+		{"TestGetUser_InvalidArgument", []string{"InvalidArgument"}, []string{"NotFound", "PermissionDenied"}},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, "GetUser")
+		if report == nil {
+			continue
+		}
+		gaps := make(map[string]bool)
+		for _, g := range report.ContractCoverage.Gaps {
+			if g.Type == taxonomy.GRPCStatusCode {
+				gaps[g.Target] = true
+			}
+		}
+		for _, code := range tt.covered {
+			if gaps[code] {
+				t.Errorf("%s: %s reported as a gap, want covered", tt.test, code)
+			}
+		}
+		for _, code := range tt.gaps {
+			if !gaps[code] {
+				t.Errorf("%s: %s not reported as a gap (gaps: %v)", tt.test, code, report.ContractCoverage.Gaps)
+			}
+		}
+	}
+}
+
+func TestAssess_ValueCoverage(t *testing.T) {
+	reports, summary := assessFixture(t, "valuedomain")
+
+	tests := []struct {
+		test     string
+		target   string
+		values   []string
+		asserted []string
+	}{
+		{"TestClassify_High", "Classify", []string{"Contractual", "Incidental", "Ambiguous"}, []string{"Contractual"}},
+		// The "high" row omits want, so it expects the zero value.
+		{"TestClassify_Table", "Classify", []string{"Contractual", "Incidental", "Ambiguous"}, []string{"Contractual", "Incidental"}},
+		{"TestValid", "Valid", []string{"false", "true"}, []string{"true"}},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, tt.target)
+		if report == nil {
+			continue
+		}
+		if len(report.ValueCoverage) != 1 {
+			t.Errorf("%s: got %d value coverage entries, want 1", tt.test, len(report.ValueCoverage))
+			continue
+		}
+		vc := report.ValueCoverage[0]
+		if !slices.Equal(vc.Values, tt.values) {
+			t.Errorf("%s: values = %v, want %v", tt.test, vc.Values, tt.values)
+		}
+		if !slices.Equal(vc.Asserted, tt.asserted) {
+			t.Errorf("%s: asserted = %v, want %v", tt.test, vc.Asserted, tt.asserted)
+		}
+	}
+
+	// Function coverage unions the asserted values across tests.
+	for _, fc := range summary.FunctionCoverage {
+		if fc.Function.Function != "Classify" {
+			continue
+		}
+		for _, ec := range fc.Effects {
+			if ec.Type != taxonomy.ReturnValue {
+				continue
+			}
+			if want := []string{"Contractual", "Incidental"}; !slices.Equal(ec.AssertedValues, want) {
+				t.Errorf("Classify asserted values = %v, want %v", ec.AssertedValues, want)
+			}
+		}
+	}
+}
+
+func TestAssess_FieldCoverage(t *testing.T) {
+	reports, summary := assessFixture(t, "fields")
+
+	tests := []struct {
+		test     string
+		target   string
+		fields   []string
+		asserted []string
+	}{
+		{"TestNewConfig_Shallow", "NewConfig", []string{"Name", "Port", "Debug"}, []string{"Name"}},
+		// Name is read through a local.
+		{"TestNewConfig_NameAndPort", "NewConfig", []string{"Name", "Port", "Debug"}, []string{"Name", "Port"}},
+		// reflect.DeepEqual on the whole struct reads every field.
+		{"TestNewConfig_Deep", "NewConfig", []string{"Name", "Port", "Debug"}, []string{"Name", "Port", "Debug"}},
+		{"TestCounter_Add", "Add", []string{"count", "total"}, []string{"total"}},
+	}
+	for _, tt := range tests {
+		report := findReport(t, reports, tt.test, tt.target)
+		if report == nil {
+			continue
+		}
+		var fields, asserted []string
+		for _, fc := range report.FieldCoverage {
+			fields = append(fields, fc.Fields...)
+			asserted = append(asserted, fc.Asserted...)
+		}
+		if !slices.Equal(fields, tt.fields) {
+			t.Errorf("%s: fields = %v, want %v", tt.test, fields, tt.fields)
+		}
+		if !slices.Equal(asserted, tt.asserted) {
+			t.Errorf("%s: asserted = %v, want %v", tt.test, asserted, tt.asserted)
+		}
+	}
+
+	// Function coverage unions the asserted fields across tests.
+	for _, fc := range summary.FunctionCoverage {
+		if fc.Function.Function != "NewConfig" {
+			continue
+		}
+		for _, ec := range fc.Effects {
+			if ec.Type == taxonomy.ReturnValue && !slices.Equal(ec.AssertedFields, ec.Fields) {
+				t.Errorf("NewConfig asserted fields = %v, want all of %v", ec.AssertedFields, ec.Fields)
+			}
+		}
+	}
+}
+
+func TestDetectAssertions_ErrorMatch(t *testing.T) {
+	pkg := loadPkg(t, "errwrap")
+	for _, tf := range quality.FindTestFunctions(pkg) {
+		if tf.Name != "TestGet_ErrorsIs" {
+			continue
+		}
+		sites := quality.DetectAssertions(tf.Decl, pkg, 3)
+		if len(sites) != 1 || sites[0].Kind != quality.AssertionKindErrorMatch {
+			t.Fatalf("expected one error_match site, got %v", sites)
+		}
+		return
+	}
+	t.Fatal("TestGet_ErrorsIs not found")
+}
+
+func TestComputeOverSpecification_LooseMockExpectation(t *testing.T) {
+	effects := []taxonomy.SideEffect{
+		{ID: "se-001", Type: taxonomy.InterfaceInteraction, Classification: &taxonomy.Classification{Label: taxonomy.Incidental}},
+		{ID: "se-002", Type: taxonomy.InterfaceInteraction, Classification: &taxonomy.Classification{Label: taxonomy.Incidental}},
+	}
+	mappings := []taxonomy.AssertionMapping{
+		{SideEffectID: "se-001", AssertionType: taxonomy.AssertionInteraction, Confidence: 70},
+		{SideEffectID: "se-002", AssertionType: taxonomy.AssertionInteraction, Confidence: 70, Strict: true},
+	}
+
+	overSpec := quality.ComputeOverSpecification(effects, mappings)
+
+	// Only the strict expectation pins an incidental interaction.
+	if overSpec.Count != 1 {
+		t.Fatalf("expected 1 incidental assertion, got %d", overSpec.Count)
+	}
+	if overSpec.IncidentalAssertions[0].SideEffectID != "se-002" {
+		t.Errorf("expected the strict expectation to be flagged, got %s",
+			overSpec.IncidentalAssertions[0].SideEffectID)
+	}
+}
+
 // --- Phase 5 Tests: Package Summary ---
 
 func TestBuildPackageSummary_Empty(t *testing.T) {
@@ -829,6 +1630,19 @@ func assessFixture(t *testing.T, fixtureName string) ([]taxonomy.QualityReport,
 	return reports, summary
 }
 
+// findReport returns the report pairing test with the target function
+// named target, or reports an error and returns nil if there is none.
+func findReport(t *testing.T, reports []taxonomy.QualityReport, test, target string) *taxonomy.QualityReport {
+	t.Helper()
+	for i, r := range reports {
+		if r.TestFunction == test && r.TargetFunction.Function == target {
+			return &reports[i]
+		}
+	}
+	t.Errorf("no report for %s -> %s", test, target)
+	return nil
+}
+
 // --- Integration: Assess ---
 
 func TestAssess_NilPackage(t *testing.T) {
@@ -839,6 +1653,90 @@ func TestAssess_NilPackage(t *testing.T) {
 	}
 }
 
+func TestAssessPackages_ExternalAndIntegrationTests(t *testing.T) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName |
+			packages.NeedFiles |
+			packages.NeedCompiledGoFiles |
+			packages.NeedImports |
+			packages.NeedDeps |
+			packages.NeedTypes |
+			packages.NeedSyntax |
+			packages.NeedTypesInfo |
+			packages.NeedTypesSizes,
+		Dir:   testdataPath("crosspkg"),
+		Tests: true,
+	}
+	pkgs, err := packages.Load(cfg, "./...")
+	if err != nil {
+		t.Fatalf("loading crosspkg: %v", err)
+	}
+
+	var results []taxonomy.AnalysisResult
+	var testPkgs []*packages.Package
+	for _, pkg := range pkgs {
+		switch {
+		case quality.HasTestSyntax(pkg):
+			testPkgs = append(testPkgs, pkg)
+		case pkg.ID == pkg.PkgPath && len(pkg.Syntax) > 0:
+			// The plain (non-test) variant of a production package.
+			pkgResults, err := analysis.Analyze(pkg, analysis.Options{Version: "test"})
+			if err != nil {
+				t.Fatalf("analysis of %s failed: %v", pkg.PkgPath, err)
+			}
+			results = append(results, pkgResults...)
+		}
+	}
+
+	var stderr bytes.Buffer
+	reports, summary, err := quality.AssessPackages(results, testPkgs, quality.Options{Stderr: &stderr})
+	if err != nil {
+		t.Fatalf("AssessPackages failed: %v", err)
+	}
+	t.Logf("stderr: %s", stderr.String())
+
+	got := make(map[string]bool)
+	for _, r := range reports {
+		key := fmt.Sprintf("%s %s -> %s.%s", filepath.Base(r.TestPackage), r.TestFunction,
+			filepath.Base(r.TargetFunction.Package), r.TargetFunction.QualifiedName())
+		got[key] = true
+	}
+	want := []string{
+		"counter_test TestCounter_Inc -> counter.(*Counter).Inc",
+		"counter_test TestCounter_Inc -> counter.New",
+		"integration TestGreetVisitor -> counter.(*Counter).Inc",
+		"integration TestGreetVisitor -> greet.Greet",
+	}
+	for _, w := range want {
+		if !got[w] {
+			t.Errorf("missing pairing %q; got %v", w, got)
+		}
+	}
+
+	if summary == nil || summary.TotalTests != len(reports) {
+		t.Fatalf("expected summary over %d reports, got %+v", len(reports), summary)
+	}
+
+	// Reset is exported with a receiver mutation but no test calls it.
+	var untested []string
+	for _, fc := range summary.UntestedContracts {
+		untested = append(untested, fc.Function.QualifiedName())
+	}
+	if strings.Join(untested, ",") != "(*Counter).Reset" {
+		t.Errorf("untested contracts = %v, want [(*Counter).Reset]", untested)
+	}
+
+	// The untested contract counts as a 0% entry in the average.
+	var total float64
+	for _, r := range reports {
+		total += r.ContractCoverage.Percentage
+	}
+	wantAvg := total / float64(len(reports)+1)
+	if diff := summary.AverageContractCoverage - wantAvg; diff > 0.001 || diff < -0.001 {
+		t.Errorf("average contract coverage = %.2f, want %.2f", summary.AverageContractCoverage, wantAvg)
+	}
+}
+
 func TestAssess_WellTested(t *testing.T) {
 	pkg := loadPkg(t, "welltested")
 
@@ -1079,6 +1977,75 @@ func TestSC003_MappingAccuracy(t *testing.T) {
 // Note: TestSC005_CIThresholds lives in cmd/gaze/main_test.go
 // because it tests checkQualityThresholds which is in the main package.
 
+// subtestCoverage returns the subtest breakdown of testName's report
+// in fixture, keyed by subtest name.
+func subtestCoverage(t *testing.T, fixture, testName string) map[string]taxonomy.SubtestCoverage {
+	t.Helper()
+	reports, _ := assessFixture(t, fixture)
+	for _, r := range reports {
+		if r.TestFunction != testName {
+			continue
+		}
+		byName := make(map[string]taxonomy.SubtestCoverage, len(r.Subtests))
+		for _, st := range r.Subtests {
+			byName[st.Name] = st
+		}
+		return byName
+	}
+	t.Fatalf("expected %s in %s fixture reports", testName, fixture)
+	return nil
+}
+
+func TestSubtestCoverage_TableRows(t *testing.T) {
+	subtests := subtestCoverage(t, "tabledriven", "TestGreet")
+	if len(subtests) != 2 {
+		t.Fatalf("expected 2 table rows, got %v", subtests)
+	}
+
+	// The valid row asserts both the value (against tt.want) and the
+	// nil error; the error row returns early after checking err, so
+	// its return value is never asserted.
+	valid := subtests["TestGreet/valid"]
+	if valid.ContractCoverage.CoveredCount != 2 {
+		t.Errorf("TestGreet/valid: expected 2 covered effects, got %+v", valid.ContractCoverage)
+	}
+	if strings.Join(valid.WantFields, ",") != "want" {
+		t.Errorf("TestGreet/valid: want fields = %v, want [want]", valid.WantFields)
+	}
+	empty := subtests["TestGreet/empty"]
+	if empty.ContractCoverage.CoveredCount != 1 || len(empty.ContractCoverage.Gaps) != 1 ||
+		empty.ContractCoverage.Gaps[0].Type != taxonomy.ReturnValue {
+		t.Errorf("TestGreet/empty: expected only the return value as a gap, got %+v", empty.ContractCoverage)
+	}
+	if empty.Location == "" {
+		t.Error("TestGreet/empty: expected the row location")
+	}
+
+	// Rows without a name field are numbered like go test does.
+	abs := subtestCoverage(t, "tabledriven", "TestAbs")
+	for _, name := range []string{"TestAbs/#00", "TestAbs/#01", "TestAbs/#02"} {
+		if st, ok := abs[name]; !ok || st.ContractCoverage.Percentage != 100 {
+			t.Errorf("%s: expected 100%% coverage, got %+v (found=%v)", name, st.ContractCoverage, ok)
+		}
+	}
+}
+
+func TestSubtestCoverage_NamedSubtests(t *testing.T) {
+	subtests := subtestCoverage(t, "tabledriven", "TestAbs_Subtests")
+
+	negative, ok := subtests["TestAbs_Subtests/negative_input"]
+	if !ok || negative.ContractCoverage.Percentage != 100 {
+		t.Errorf("negative_input: expected 100%% coverage, got %+v (found=%v)", negative.ContractCoverage, ok)
+	}
+	smoke, ok := subtests["TestAbs_Subtests/smoke"]
+	if !ok || smoke.ContractCoverage.Percentage != 0 {
+		t.Errorf("smoke: expected 0%% coverage, got %+v (found=%v)", smoke.ContractCoverage, ok)
+	}
+	if len(negative.WantFields) != 0 {
+		t.Errorf("expected no want fields outside a table, got %v", negative.WantFields)
+	}
+}
+
 // --- Acceptance Test: SC-007 Table-Driven Union ---
 
 func TestSC007_TableDrivenUnion(t *testing.T) {
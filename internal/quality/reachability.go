@@ -0,0 +1,66 @@
+package quality
+
+import "github.com/unbound-force/gaze/internal/taxonomy"
+
+// MarkNeverExecuted joins function coverage with a coverage profile:
+// each contractual effect whose every occurrence site lineExecuted
+// reports as never run is flagged NeverExecuted. This separates an
+// error return that is asserted on nowhere because its branch never
+// runs from one that runs but goes unchecked. Effects without
+// recorded occurrences are judged by their Location; for those on a
+// signature that is the function body's entry, so they are flagged
+// only when the function itself never ran.
+//
+// An effect with a site the profile does not cover is left
+// unflagged. Returns the number of effects flagged.
+func MarkNeverExecuted(
+	fcs []taxonomy.FunctionCoverage,
+	results []taxonomy.AnalysisResult,
+	lineExecuted func(file string, line int) (executed, known bool),
+) int {
+	sites := make(map[string][]string)
+	for _, r := range results {
+		for _, e := range r.SideEffects {
+			if len(e.Occurrences) == 0 {
+				sites[e.ID] = []string{e.Location}
+				continue
+			}
+			for _, o := range e.Occurrences {
+				sites[e.ID] = append(sites[e.ID], o.Location)
+			}
+		}
+	}
+
+	flagged := 0
+	for i := range fcs {
+		for j := range fcs[i].Effects {
+			ec := &fcs[i].Effects[j]
+			if neverExecuted(sites[ec.SideEffectID], lineExecuted) {
+				ec.NeverExecuted = true
+				flagged++
+			}
+		}
+	}
+	return flagged
+}
+
+// neverExecuted reports whether the profile covers every one of locs
+// and shows none of them executed.
+func neverExecuted(
+	locs []string,
+	lineExecuted func(file string, line int) (executed, known bool),
+) bool {
+	if len(locs) == 0 {
+		return false
+	}
+	for _, loc := range locs {
+		file, line, _, ok := taxonomy.ParseLocation(loc)
+		if !ok {
+			return false
+		}
+		if executed, known := lineExecuted(file, line); !known || executed {
+			return false
+		}
+	}
+	return true
+}
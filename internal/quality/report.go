@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/charmbracelet/lipgloss"
 
@@ -48,6 +49,9 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 			r.TargetFunction.QualifiedName())))
 
 		_, _ = fmt.Fprintf(w, "    Test: %s\n", r.TestLocation)
+		if r.TestKind != "" && r.TestKind != "test" {
+			_, _ = fmt.Fprintf(w, "    Kind: %s\n", r.TestKind)
+		}
 		_, _ = fmt.Fprintf(w, "    Target: %s\n", r.TargetFunction.Location)
 
 		// Contract Coverage.
@@ -62,6 +66,10 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 			covStyle.Render(fmt.Sprintf("%.0f%%", covPct)),
 			r.ContractCoverage.CoveredCount,
 			r.ContractCoverage.TotalContractual)
+		if n := len(r.ContractCoverage.PropertyCovered); n > 0 {
+			_, _ = fmt.Fprintf(w, "    Covered by property invariants: %d/%d\n",
+				n, r.ContractCoverage.CoveredCount)
+		}
 
 		// Over-Specification.
 		overCount := r.OverSpecification.Count
@@ -87,6 +95,57 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 		_, _ = fmt.Fprintf(w, "    Detection Confidence: %s\n",
 			detStyle.Render(fmt.Sprintf("%d%%", detConf)))
 
+		// Per-subtest and per-table-row breakdown.
+		if len(r.Subtests) > 0 {
+			_, _ = fmt.Fprintln(w, muted.Render("    Subtests:"))
+			for _, st := range r.Subtests {
+				line := fmt.Sprintf("      - %s: %.0f%% (%d/%d)",
+					st.Name,
+					st.ContractCoverage.Percentage,
+					st.ContractCoverage.CoveredCount,
+					st.ContractCoverage.TotalContractual)
+				if len(st.WantFields) > 0 {
+					line += " [" + strings.Join(st.WantFields, ", ") + "]"
+				}
+				_, _ = fmt.Fprintln(w, line)
+			}
+		}
+
+		// Value-domain coverage of enumerable return positions.
+		if len(r.ValueCoverage) > 0 {
+			_, _ = fmt.Fprintln(w, muted.Render("    Value coverage:"))
+			for _, vc := range r.ValueCoverage {
+				_, _ = fmt.Fprintf(w, "      - %s returns {%s}; %s\n",
+					r.TargetFunction.QualifiedName(),
+					strings.Join(vc.Values, ", "),
+					assertedText(vc.Values, vc.Asserted))
+			}
+		}
+
+		// Field coverage of struct returns and receiver mutations.
+		// The receiver's fields are listed together.
+		if len(r.FieldCoverage) > 0 {
+			_, _ = fmt.Fprintln(w, muted.Render("    Field coverage:"))
+			var recvFields, recvAsserted []string
+			for _, fc := range r.FieldCoverage {
+				if fc.Type == taxonomy.ReceiverMutation {
+					recvFields = append(recvFields, fc.Fields...)
+					recvAsserted = append(recvAsserted, fc.Asserted...)
+					continue
+				}
+				_, _ = fmt.Fprintf(w, "      - %s returns fields {%s}; %s\n",
+					r.TargetFunction.QualifiedName(),
+					strings.Join(fc.Fields, ", "),
+					assertedText(fc.Fields, fc.Asserted))
+			}
+			if len(recvFields) > 0 {
+				_, _ = fmt.Fprintf(w, "      - %s sets receiver fields {%s}; %s\n",
+					r.TargetFunction.QualifiedName(),
+					strings.Join(recvFields, ", "),
+					assertedText(recvFields, recvAsserted))
+			}
+		}
+
 		// Gaps.
 		if len(r.ContractCoverage.Gaps) > 0 {
 			_, _ = fmt.Fprintln(w, muted.Render("    Gaps (untested contractual effects):"))
@@ -146,11 +205,45 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 		}
 	}
 
+	// Untested contracts: functions no test reaches at all.
+	if summary != nil && len(summary.UntestedContracts) > 0 {
+		if len(reports) > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, header.Render("=== Untested Contracts ==="))
+		for _, fc := range summary.UntestedContracts {
+			_, _ = fmt.Fprintf(w, "    %s %s (%d contractual effects)\n",
+				bad.Render("0%"),
+				fc.Function.QualifiedName(),
+				fc.ContractCoverage.TotalContractual)
+			_, _ = fmt.Fprintf(w, "      Target: %s\n", fc.Function.Location)
+			neverExecuted := make(map[string]bool)
+			for _, ec := range fc.Effects {
+				neverExecuted[ec.SideEffectID] = ec.NeverExecuted
+			}
+			for i, gap := range fc.ContractCoverage.Gaps {
+				_, _ = fmt.Fprintf(w, "      - %s: %s%s\n", gap.Type, gap.Description,
+					neverExecutedText(neverExecuted[gap.ID]))
+				if i < len(fc.ContractCoverage.GapHints) && fc.ContractCoverage.GapHints[i] != "" {
+					_, _ = fmt.Fprintf(w, "        hint: %s\n", fc.ContractCoverage.GapHints[i])
+				}
+			}
+		}
+	}
+
 	// Package summary.
-	if summary != nil && summary.TotalTests > 0 {
+	if summary != nil && (summary.TotalTests > 0 || len(summary.UntestedContracts) > 0) {
 		_, _ = fmt.Fprintln(w)
 		_, _ = fmt.Fprintln(w, header.Render("=== Package Summary ==="))
 		_, _ = fmt.Fprintf(w, "    Tests analyzed: %d\n", summary.TotalTests)
+		if len(summary.UntestedContracts) > 0 {
+			_, _ = fmt.Fprintf(w, "    Untested contracts: %s\n",
+				bad.Render(fmt.Sprintf("%d", len(summary.UntestedContracts))))
+		}
+		if summary.NeverExecutedContracts > 0 {
+			_, _ = fmt.Fprintf(w, "    Contractual effects never executed: %s\n",
+				bad.Render(fmt.Sprintf("%d", summary.NeverExecutedContracts)))
+		}
 		_, _ = fmt.Fprintf(w, "    Average contract coverage: %.0f%%\n",
 			summary.AverageContractCoverage)
 		_, _ = fmt.Fprintf(w, "    Total over-specifications: %d\n",
@@ -158,6 +251,36 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 		_, _ = fmt.Fprintf(w, "    Assertion detection confidence: %d%%\n",
 			summary.AssertionDetectionConfidence)
 
+		if len(summary.FunctionCoverage) > 0 {
+			_, _ = fmt.Fprintln(w, muted.Render("    Function coverage (union across tests):"))
+			for _, fc := range summary.FunctionCoverage {
+				_, _ = fmt.Fprintf(w, "      - %s: %.0f%% (%d/%d) from %d test(s)\n",
+					fc.Function.QualifiedName(),
+					fc.ContractCoverage.Percentage,
+					fc.ContractCoverage.CoveredCount,
+					fc.ContractCoverage.TotalContractual,
+					len(fc.Tests))
+				for _, ec := range fc.Effects {
+					coveredBy := "untested"
+					if len(ec.CoveredBy) > 0 {
+						coveredBy = strings.Join(ec.CoveredBy, ", ")
+					}
+					_, _ = fmt.Fprintf(w, "          %s: %s%s\n", ec.Type, coveredBy,
+						neverExecutedText(ec.NeverExecuted))
+					if len(ec.Fields) > 0 {
+						_, _ = fmt.Fprintf(w, "            fields {%s}; %s\n",
+							strings.Join(ec.Fields, ", "),
+							assertedText(ec.Fields, ec.AssertedFields))
+					}
+					if len(ec.Values) > 0 {
+						_, _ = fmt.Fprintf(w, "            values {%s}; %s\n",
+							strings.Join(ec.Values, ", "),
+							assertedText(ec.Values, ec.AssertedValues))
+					}
+				}
+			}
+		}
+
 		if len(summary.WorstCoverageTests) > 0 {
 			_, _ = fmt.Fprintln(w, muted.Render("    Lowest coverage tests:"))
 			for _, worst := range summary.WorstCoverageTests {
@@ -172,3 +295,22 @@ func WriteText(w io.Writer, reports []taxonomy.QualityReport, summary *taxonomy.
 
 	return nil
 }
+
+// neverExecutedText returns the marker appended to an effect whose
+// source line the coverage profile shows never ran.
+func neverExecutedText(neverExecuted bool) string {
+	if !neverExecuted {
+		return ""
+	}
+	return " (never executed)"
+}
+
+// assertedText summarizes which of a domain's values or a struct's
+// fields are asserted: "tests assert A (1/3)" or "none asserted (0/3)".
+func assertedText(values, asserted []string) string {
+	if len(asserted) == 0 {
+		return fmt.Sprintf("none asserted (0/%d)", len(values))
+	}
+	return fmt.Sprintf("tests assert %s (%d/%d)",
+		strings.Join(asserted, ", "), len(asserted), len(values))
+}
@@ -0,0 +1,584 @@
+package quality
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// computeSubtestCoverage attributes a test's assertions to its t.Run
+// subtests and to the rows of its table-driven loops, returning one
+// SubtestCoverage per subtest or row. Tests without subtests or
+// tables return nil.
+//
+// Attribution is positional: an assertion belongs to a subtest when
+// it lies inside the subtest closure, and to a table row when it lies
+// inside the loop over the table and every guard on the path to it
+// (e.g., `if tt.wantErr { ...; return }`) holds for that row's field
+// values. Guards that cannot be evaluated from the table literal are
+// assumed to hold. Assertions in helpers (depth > 0) are not
+// attributed.
+func computeSubtestCoverage(
+	testDecl *ast.FuncDecl,
+	testPkg *packages.Package,
+	sites []AssertionSite,
+	mappings []taxonomy.AssertionMapping,
+	effects []taxonomy.SideEffect,
+) []taxonomy.SubtestCoverage {
+	if testDecl == nil || testDecl.Body == nil || testPkg.TypesInfo == nil {
+		return nil
+	}
+
+	s := &subtestScanner{
+		root: testDecl.Body,
+		info: testPkg.TypesInfo,
+		fset: testPkg.Fset,
+	}
+	s.scanStmts(testDecl.Body.List, testDecl.Name.Name)
+	if len(s.scopes) == 0 {
+		return nil
+	}
+
+	mappingsByLoc := make(map[string][]taxonomy.AssertionMapping)
+	for _, m := range mappings {
+		mappingsByLoc[m.AssertionLocation] = append(mappingsByLoc[m.AssertionLocation], m)
+	}
+
+	out := make([]taxonomy.SubtestCoverage, 0, len(s.scopes))
+	for _, scope := range s.scopes {
+		var scopeMappings []taxonomy.AssertionMapping
+		fields := make(map[string]bool)
+		for _, site := range sites {
+			if site.Depth != 0 || site.Expr == nil || !scope.contains(site.Expr.Pos()) {
+				continue
+			}
+			if scope.row != nil && !scope.row.executes(site.Expr.Pos(), s.info) {
+				continue
+			}
+			scopeMappings = append(scopeMappings, mappingsByLoc[site.Location]...)
+			if scope.row != nil {
+				for _, f := range scope.row.table.fieldRefs(site.Expr, s.info) {
+					fields[f] = true
+				}
+			}
+		}
+
+		entry := taxonomy.SubtestCoverage{
+			Name:             scope.name,
+			Location:         scope.location,
+			ContractCoverage: ComputeContractCoverage(effects, scopeMappings),
+		}
+		for f := range fields {
+			entry.WantFields = append(entry.WantFields, f)
+		}
+		sort.Strings(entry.WantFields)
+		out = append(out, entry)
+	}
+	return out
+}
+
+// subtestScope is a region of a test body whose assertions are
+// attributed to one subtest or table row.
+type subtestScope struct {
+	name     string
+	location string
+	start    token.Pos
+	end      token.Pos
+	row      *tableRow // non-nil for table rows
+}
+
+func (sc *subtestScope) contains(pos token.Pos) bool {
+	return pos >= sc.start && pos < sc.end
+}
+
+// subtestScanner walks a test body collecting subtest scopes.
+type subtestScanner struct {
+	root   *ast.BlockStmt
+	info   *types.Info
+	fset   *token.FileSet
+	scopes []*subtestScope
+}
+
+// scanStmts walks stmts looking for table loops and t.Run calls
+// with literal names. prefix is the subtest path so far. Table loops
+// are not descended into: their rows, not the dynamically named
+// subtests they start, form the scopes.
+func (s *subtestScanner) scanStmts(stmts []ast.Stmt, prefix string) {
+	for _, stmt := range stmts {
+		ast.Inspect(stmt, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.RangeStmt:
+				if t := s.tableFor(node); t != nil {
+					for i, row := range t.rows {
+						name := row.name
+						if name == "" {
+							name = fmt.Sprintf("#%02d", i)
+						}
+						s.scopes = append(s.scopes, &subtestScope{
+							name:     prefix + "/" + rewriteSubtestName(name),
+							location: s.posString(row.lit.Pos()),
+							start:    node.Body.Pos(),
+							end:      node.Body.End(),
+							row:      row,
+						})
+					}
+					return false
+				}
+			case *ast.CallExpr:
+				name, body := s.tRun(node)
+				if body == nil {
+					return true
+				}
+				full := prefix + "/" + rewriteSubtestName(name)
+				s.scopes = append(s.scopes, &subtestScope{
+					name:     full,
+					location: s.posString(node.Pos()),
+					start:    body.Pos(),
+					end:      body.End(),
+				})
+				s.scanStmts(body.List, full)
+				return false
+			}
+			return true
+		})
+	}
+}
+
+// tRun returns the literal name and closure body of a t.Run call.
+// Calls with a non-literal name or closure return a nil body.
+func (s *subtestScanner) tRun(call *ast.CallExpr) (string, *ast.BlockStmt) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Run" || len(call.Args) != 2 {
+		return "", nil
+	}
+	if tv, ok := s.info.Types[sel.X]; !ok || !isTestingType(tv.Type) {
+		return "", nil
+	}
+	lit, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", nil
+	}
+	fn, ok := call.Args[1].(*ast.FuncLit)
+	if !ok {
+		return "", nil
+	}
+	name, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", nil
+	}
+	return name, fn.Body
+}
+
+func (s *subtestScanner) posString(pos token.Pos) string {
+	p := s.fset.Position(pos)
+	return fmt.Sprintf("%s:%d", p.Filename, p.Line)
+}
+
+// rewriteSubtestName mirrors how the testing package rewrites
+// subtest names: spaces become underscores.
+func rewriteSubtestName(name string) string {
+	return strings.ReplaceAll(name, " ", "_")
+}
+
+// tableLoop is a `for _, tt := range tests` loop over a composite
+// literal of struct rows.
+type tableLoop struct {
+	loopVar types.Object
+	body    *ast.BlockStmt
+	rows    []*tableRow
+}
+
+// tableRow is one case of a table-driven test.
+type tableRow struct {
+	table  *tableLoop
+	name   string
+	lit    *ast.CompositeLit
+	fields map[string]ast.Expr
+}
+
+// tableFor recognizes a range statement over a table literal,
+// either inline or through a local variable initialized with one.
+// Slice tables name rows by a string "name" field; map tables by
+// their string keys.
+func (s *subtestScanner) tableFor(rng *ast.RangeStmt) *tableLoop {
+	lit := s.tableLiteral(rng.X)
+	if lit == nil {
+		return nil
+	}
+
+	_, isMap := s.info.TypeOf(lit).Underlying().(*types.Map)
+	loopIdent, _ := rng.Value.(*ast.Ident)
+	if loopIdent == nil || loopIdent.Name == "_" {
+		return nil
+	}
+	t := &tableLoop{loopVar: s.info.Defs[loopIdent], body: rng.Body}
+	if t.loopVar == nil {
+		return nil
+	}
+
+	for _, elt := range lit.Elts {
+		var key ast.Expr
+		if kv, ok := elt.(*ast.KeyValueExpr); ok && isMap {
+			key, elt = kv.Key, kv.Value
+		}
+		rowLit, ok := elt.(*ast.CompositeLit)
+		if !ok {
+			return nil
+		}
+		row := &tableRow{table: t, lit: rowLit, fields: s.rowFields(rowLit)}
+		switch {
+		case key != nil:
+			row.name = stringLiteral(key)
+		case row.fields["name"] != nil:
+			row.name = stringLiteral(row.fields["name"])
+		}
+		t.rows = append(t.rows, row)
+	}
+	if len(t.rows) == 0 {
+		return nil
+	}
+	return t
+}
+
+// tableLiteral resolves the ranged expression to a composite literal
+// of struct elements.
+func (s *subtestScanner) tableLiteral(x ast.Expr) *ast.CompositeLit {
+	if ident, ok := x.(*ast.Ident); ok {
+		obj := s.info.Uses[ident]
+		if obj == nil {
+			return nil
+		}
+		x = s.initializer(obj)
+	}
+	lit, ok := x.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	var elem types.Type
+	switch u := s.info.TypeOf(lit).Underlying().(type) {
+	case *types.Slice:
+		elem = u.Elem()
+	case *types.Array:
+		elem = u.Elem()
+	case *types.Map:
+		elem = u.Elem()
+	default:
+		return nil
+	}
+	if _, ok := elem.Underlying().(*types.Struct); !ok {
+		return nil
+	}
+	return lit
+}
+
+// initializer finds the expression a local variable of the test was
+// declared with (`tests := []struct{...}{...}` or `var tests = ...`).
+func (s *subtestScanner) initializer(obj types.Object) ast.Expr {
+	var found ast.Expr
+	ast.Inspect(s.root, func(n ast.Node) bool {
+		if found != nil {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.AssignStmt:
+			for i, lhs := range node.Lhs {
+				if id, ok := lhs.(*ast.Ident); ok && s.info.Defs[id] == obj && i < len(node.Rhs) {
+					found = node.Rhs[i]
+				}
+			}
+		case *ast.ValueSpec:
+			for i, id := range node.Names {
+				if s.info.Defs[id] == obj && i < len(node.Values) {
+					found = node.Values[i]
+				}
+			}
+		}
+		return true
+	})
+	return found
+}
+
+// rowFields maps field names to their expressions in a row literal,
+// handling both keyed and positional struct literals.
+func (s *subtestScanner) rowFields(lit *ast.CompositeLit) map[string]ast.Expr {
+	fields := make(map[string]ast.Expr, len(lit.Elts))
+	st, _ := s.info.TypeOf(lit).Underlying().(*types.Struct)
+	for i, elt := range lit.Elts {
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				fields[key.Name] = kv.Value
+			}
+			continue
+		}
+		if st != nil && i < st.NumFields() {
+			fields[st.Field(i).Name()] = elt
+		}
+	}
+	return fields
+}
+
+// stringLiteral returns the value of a string literal expression,
+// or "" if expr is not one.
+func stringLiteral(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	v, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+// fieldRefs returns the table fields (`tt.want`) referenced in expr.
+func (t *tableLoop) fieldRefs(expr ast.Expr, info *types.Info) []string {
+	var refs []string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if f, ok := t.field(n, info); ok {
+			refs = append(refs, f)
+			return false
+		}
+		return true
+	})
+	return refs
+}
+
+// field reports whether n is a selector on the loop variable and
+// returns the selected field name.
+func (t *tableLoop) field(n ast.Node, info *types.Info) (string, bool) {
+	sel, ok := n.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok || info.Uses[ident] != t.loopVar {
+		return "", false
+	}
+	return sel.Sel.Name, true
+}
+
+// guard is a condition that must evaluate to want for a statement
+// to execute.
+type guard struct {
+	cond ast.Expr
+	want bool
+}
+
+// executes reports whether the statement at pos inside the table
+// loop runs for this row, given the guards on the path to it.
+func (r *tableRow) executes(pos token.Pos, info *types.Info) bool {
+	for _, g := range collectGuards(r.table.body.List, pos) {
+		if v, known := r.eval(g.cond, info); known && v != g.want {
+			return false
+		}
+	}
+	return true
+}
+
+// collectGuards returns the guards on the path from stmts to pos.
+// An if statement without else whose body always exits (return,
+// continue, t.Fatal, t.Skip) guards every later statement with its
+// negated condition.
+func collectGuards(stmts []ast.Stmt, pos token.Pos) []guard {
+	var guards []guard
+	for _, stmt := range stmts {
+		if pos >= stmt.Pos() && pos < stmt.End() {
+			return append(guards, guardsWithin(stmt, pos)...)
+		}
+		if ifs, ok := stmt.(*ast.IfStmt); ok && ifs.Else == nil && exits(ifs.Body) {
+			guards = append(guards, guard{cond: ifs.Cond, want: false})
+		}
+	}
+	return guards
+}
+
+// guardsWithin returns the guards inside stmt on the path to pos.
+func guardsWithin(stmt ast.Stmt, pos token.Pos) []guard {
+	if ifs, ok := stmt.(*ast.IfStmt); ok {
+		switch {
+		case pos >= ifs.Body.Pos() && pos < ifs.Body.End():
+			return append([]guard{{cond: ifs.Cond, want: true}}, collectGuards(ifs.Body.List, pos)...)
+		case ifs.Else != nil && pos >= ifs.Else.Pos() && pos < ifs.Else.End():
+			return append([]guard{{cond: ifs.Cond, want: false}}, guardsWithin(ifs.Else, pos)...)
+		}
+		return nil
+	}
+
+	// Descend into the outermost nested statement list containing
+	// pos: a block, a case clause, or a closure body.
+	var guards []guard
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		if guards != nil || n == nil || n == stmt {
+			return guards == nil
+		}
+		if pos < n.Pos() || pos >= n.End() {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.BlockStmt:
+			guards = append([]guard{}, collectGuards(node.List, pos)...)
+			return false
+		case *ast.CaseClause:
+			guards = append([]guard{}, collectGuards(node.Body, pos)...)
+			return false
+		case *ast.CommClause:
+			guards = append([]guard{}, collectGuards(node.Body, pos)...)
+			return false
+		}
+		return true
+	})
+	return guards
+}
+
+// exits reports whether a block always leaves the current loop
+// iteration or test.
+func exits(body *ast.BlockStmt) bool {
+	if len(body.List) == 0 {
+		return false
+	}
+	switch last := body.List[len(body.List)-1].(type) {
+	case *ast.ReturnStmt:
+		return true
+	case *ast.BranchStmt:
+		return last.Tok == token.CONTINUE
+	case *ast.ExprStmt:
+		call, ok := last.X.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return false
+		}
+		switch sel.Sel.Name {
+		case "Fatal", "Fatalf", "FailNow", "Skip", "Skipf", "SkipNow":
+			return true
+		}
+	}
+	return false
+}
+
+// eval evaluates a guard condition against the row's literal field
+// values. known is false when the condition depends on anything
+// other than table fields and constants.
+func (r *tableRow) eval(expr ast.Expr, info *types.Info) (value, known bool) {
+	switch e := expr.(type) {
+	case *ast.ParenExpr:
+		return r.eval(e.X, info)
+	case *ast.UnaryExpr:
+		if e.Op == token.NOT {
+			v, ok := r.eval(e.X, info)
+			return !v, ok
+		}
+	case *ast.BinaryExpr:
+		switch e.Op {
+		case token.LAND:
+			x, xok := r.eval(e.X, info)
+			y, yok := r.eval(e.Y, info)
+			if (xok && !x) || (yok && !y) {
+				return false, true
+			}
+			return true, xok && yok
+		case token.LOR:
+			x, xok := r.eval(e.X, info)
+			y, yok := r.eval(e.Y, info)
+			if (xok && x) || (yok && y) {
+				return true, true
+			}
+			return false, xok && yok
+		case token.EQL, token.NEQ:
+			x, xok := r.operand(e.X, info)
+			y, yok := r.operand(e.Y, info)
+			if !xok || !yok {
+				return false, false
+			}
+			if x == nonNil && y == nonNil {
+				return false, false
+			}
+			return (x == y) == (e.Op == token.EQL), true
+		}
+	default:
+		if v, ok := r.operand(expr, info); ok && (v == "true" || v == "false") {
+			return v == "true", true
+		}
+	}
+	return false, false
+}
+
+// nonNil is the operand value of a table field set to a non-nil
+// expression whose exact value is not statically known.
+const nonNil = "<non-nil>"
+
+// operand returns a comparable rendering of a guard operand: a row
+// field value, a constant, or nil.
+func (r *tableRow) operand(expr ast.Expr, info *types.Info) (string, bool) {
+	if name, ok := r.table.field(expr, info); ok {
+		val, set := r.fields[name]
+		if !set {
+			return zeroValue(info.TypeOf(expr)), true
+		}
+		if v, ok := constOperand(val, info); ok {
+			return v, true
+		}
+		if isNillable(info.TypeOf(expr)) {
+			return nonNil, true
+		}
+		return "", false
+	}
+	return constOperand(expr, info)
+}
+
+// constOperand renders constants (including nil) in a canonical form.
+func constOperand(expr ast.Expr, info *types.Info) (string, bool) {
+	if tv, ok := info.Types[expr]; ok {
+		if tv.IsNil() {
+			return "nil", true
+		}
+		if tv.Value != nil {
+			return tv.Value.ExactString(), true
+		}
+	}
+	return "", false
+}
+
+// zeroValue renders the zero value of t in constOperand's form.
+func zeroValue(t types.Type) string {
+	if t == nil {
+		return ""
+	}
+	if isNillable(t) {
+		return "nil"
+	}
+	if b, ok := t.Underlying().(*types.Basic); ok {
+		switch {
+		case b.Info()&types.IsBoolean != 0:
+			return "false"
+		case b.Info()&types.IsString != 0:
+			return `""`
+		case b.Info()&types.IsNumeric != 0:
+			return "0"
+		}
+	}
+	return ""
+}
+
+// isNillable reports whether nil is a valid value of t.
+func isNillable(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,18 @@
+// Package cancellation is a test fixture for tests that assert a
+// function honours the cancellation of its context.
+package cancellation
+
+import (
+	"context"
+	"time"
+)
+
+// Wait blocks until ctx is done or d elapses.
+func Wait(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
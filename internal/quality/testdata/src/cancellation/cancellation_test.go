@@ -0,0 +1,24 @@
+package cancellation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWait_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := Wait(ctx, time.Hour)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestWait_Completes(t *testing.T) {
+	err := Wait(context.Background(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,24 @@
+// Package counter is a test fixture exercised by an external test
+// package and by a cross-package integration test.
+package counter
+
+// Counter counts events.
+type Counter struct {
+	n int
+}
+
+// New returns a zeroed Counter.
+func New() *Counter {
+	return &Counter{}
+}
+
+// Inc increments the counter and returns the new value.
+func (c *Counter) Inc() int {
+	c.n++
+	return c.n
+}
+
+// Reset sets the counter back to zero. No test exercises it.
+func (c *Counter) Reset() {
+	c.n = 0
+}
@@ -0,0 +1,14 @@
+package counter_test
+
+import (
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/quality/testdata/src/crosspkg/counter"
+)
+
+func TestCounter_Inc(t *testing.T) {
+	c := counter.New()
+	if got := c.Inc(); got != 1 {
+		t.Errorf("Inc() = %d, want 1", got)
+	}
+}
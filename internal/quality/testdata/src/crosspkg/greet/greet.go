@@ -0,0 +1,10 @@
+// Package greet is a test fixture with no tests of its own; it is
+// covered only by the integration test package.
+package greet
+
+import "fmt"
+
+// Greet returns a greeting for name.
+func Greet(name string) string {
+	return fmt.Sprintf("hello, %s", name)
+}
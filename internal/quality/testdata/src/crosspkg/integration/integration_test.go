@@ -0,0 +1,20 @@
+// Package integration is a test-only fixture package whose tests
+// exercise production code in several sibling packages.
+package integration
+
+import (
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/quality/testdata/src/crosspkg/counter"
+	"github.com/unbound-force/gaze/internal/quality/testdata/src/crosspkg/greet"
+)
+
+func TestGreetVisitor(t *testing.T) {
+	c := counter.New()
+	if n := c.Inc(); n != 1 {
+		t.Fatalf("Inc() = %d, want 1", n)
+	}
+	if got := greet.Greet("gaze"); got != "hello, gaze" {
+		t.Errorf("Greet() = %q, want %q", got, "hello, gaze")
+	}
+}
@@ -0,0 +1,44 @@
+// Package errwrap is a test fixture for tests that assert error
+// wrapping through errors.Is and errors.As.
+package errwrap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when a key is missing.
+var ErrNotFound = errors.New("not found")
+
+// ErrClosed is returned once the store is closed.
+var ErrClosed = errors.New("closed")
+
+// Store holds values by key.
+type Store struct {
+	data map[string]string
+}
+
+// Get returns the value for key, wrapping ErrNotFound when missing.
+func (s *Store) Get(key string) error {
+	if _, ok := s.data[key]; !ok {
+		return fmt.Errorf("get %q: %w", key, ErrNotFound)
+	}
+	return nil
+}
+
+// KeyError reports an invalid key.
+type KeyError struct {
+	Key string
+}
+
+func (e *KeyError) Error() string { return "invalid key " + e.Key }
+
+// Wrap annotates err, which may carry a *KeyError.
+func (s *Store) Wrap(err error) error {
+	return fmt.Errorf("store: %w", err)
+}
+
+// WrapResponse annotates the error of a response.
+func (s *Store) WrapResponse(errResp error) error {
+	return fmt.Errorf("store response: %w", errResp)
+}
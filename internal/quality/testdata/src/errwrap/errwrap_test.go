@@ -0,0 +1,59 @@
+package errwrap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGet_ErrorsIs(t *testing.T) {
+	s := &Store{}
+	err := s.Get("missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("got %v, want ErrNotFound", err)
+	}
+}
+
+func TestGet_NilCheck(t *testing.T) {
+	s := &Store{}
+	err := s.Get("missing")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+// TestGet_GuardedFatal excludes ErrNotFound from the failure rather
+// than asserting it.
+func TestGet_GuardedFatal(t *testing.T) {
+	s := &Store{}
+	err := s.Get("missing")
+	if err != nil && !errors.Is(err, ErrNotFound) {
+		t.Fatal(err)
+	}
+}
+
+// TestGet_OtherSentinel matches a sentinel that Get never wraps.
+func TestGet_OtherSentinel(t *testing.T) {
+	s := &Store{}
+	err := s.Get("missing")
+	if errors.Is(err, ErrClosed) {
+		t.Fatal("store reported closed")
+	}
+}
+
+func TestWrap_ErrorsAs(t *testing.T) {
+	s := &Store{}
+	err := s.Wrap(&KeyError{Key: "k"})
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) {
+		t.Errorf("got %v, want a *KeyError", err)
+	}
+}
+
+func TestWrapResponse_ErrorsAs(t *testing.T) {
+	s := &Store{}
+	err := s.WrapResponse(&KeyError{Key: "k"})
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) {
+		t.Errorf("got %v, want a *KeyError", err)
+	}
+}
@@ -0,0 +1,51 @@
+// Package examples is a test fixture whose functions are tested only
+// by Example functions with "// Output:" comments.
+package examples
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Format spells out small numbers.
+func Format(n int) string {
+	switch n {
+	case 1:
+		return "one"
+	case 2:
+		return "two"
+	}
+	return strconv.Itoa(n)
+}
+
+// Parse converts a decimal string to an int.
+func Parse(s string) (int, error) {
+	return strconv.Atoi(s)
+}
+
+// Render writes one line per item to w.
+func Render(w io.Writer, items []string) {
+	for i, item := range items {
+		w.Write([]byte(fmt.Sprintf("%d. %s\n", i+1, item)))
+	}
+}
+
+// Join concatenates items; its example has no output comment.
+func Join(items []string) string {
+	out := ""
+	for _, item := range items {
+		out += item
+	}
+	return out
+}
+
+// Config is a named configuration.
+type Config struct {
+	Name string
+}
+
+// NewConfig returns a Config named name.
+func NewConfig(name string) Config {
+	return Config{Name: name}
+}
@@ -0,0 +1,35 @@
+package examples
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleFormat() {
+	fmt.Println(Format(2))
+	// Output: two
+}
+
+func ExampleParse() {
+	n, err := Parse("42")
+	fmt.Println(n, err)
+	// Output: 42 <nil>
+}
+
+func ExampleRender() {
+	Render(os.Stdout, []string{"a", "b"})
+	// Output:
+	// 1. a
+	// 2. b
+}
+
+// ExampleJoin is compiled but never run: it has no output comment.
+func ExampleJoin() {
+	_ = Join([]string{"a", "b"})
+}
+
+func ExampleNewConfig() {
+	cfg := NewConfig("prod")
+	fmt.Println(cfg.Name)
+	// Output: prod
+}
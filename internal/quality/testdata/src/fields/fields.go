@@ -0,0 +1,27 @@
+// Package fields is a test fixture for field-level contract coverage
+// of returned structs and mutated receivers.
+package fields
+
+// Config is filled in by NewConfig.
+type Config struct {
+	Name  string
+	Port  int
+	Debug bool
+}
+
+// NewConfig returns a config with every field set.
+func NewConfig(name string) *Config {
+	return &Config{Name: name, Port: 8080, Debug: true}
+}
+
+// Counter tracks a running total.
+type Counter struct {
+	count int
+	total int
+}
+
+// Add mutates both receiver fields.
+func (c *Counter) Add(n int) {
+	c.count++
+	c.total += n
+}
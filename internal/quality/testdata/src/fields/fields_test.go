@@ -0,0 +1,37 @@
+package fields
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewConfig_Shallow(t *testing.T) {
+	cfg := NewConfig("svc")
+	if cfg.Name != "svc" {
+		t.Errorf("Name = %q, want svc", cfg.Name)
+	}
+}
+
+func TestNewConfig_NameAndPort(t *testing.T) {
+	cfg := NewConfig("svc")
+	name := cfg.Name
+	if name != "svc" || cfg.Port != 8080 {
+		t.Errorf("NewConfig() = %+v, want svc:8080", cfg)
+	}
+}
+
+func TestNewConfig_Deep(t *testing.T) {
+	got := NewConfig("svc")
+	want := &Config{Name: "svc", Port: 8080, Debug: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewConfig() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCounter_Add(t *testing.T) {
+	c := &Counter{}
+	c.Add(5)
+	if c.total != 5 {
+		t.Errorf("total = %d, want 5", c.total)
+	}
+}
@@ -0,0 +1,36 @@
+// Package grpcstatus is a test fixture for tests that assert the gRPC
+// status codes a service method returns.
+package grpcstatus
+
+import (
+	"context"
+
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/codes"
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/status"
+)
+
+// User is a stored user.
+type User struct {
+	ID    string
+	Owner string
+}
+
+// Server implements the user service.
+type Server struct {
+	Users map[string]User
+}
+
+// GetUser fails with InvalidArgument, NotFound, or PermissionDenied.
+func (s *Server) GetUser(ctx context.Context, id, caller string) (*User, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	u, ok := s.Users[id]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", id)
+	}
+	if u.Owner != caller {
+		return nil, status.Error(codes.PermissionDenied, "not the owner")
+	}
+	return &u, nil
+}
@@ -0,0 +1,26 @@
+package grpcstatus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/codes"
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/status"
+)
+
+func TestGetUser_NotFound(t *testing.T) {
+	s := &Server{Users: map[string]User{}}
+	_, err := s.GetUser(context.Background(), "42", "alice")
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("got %v, want NotFound", err)
+	}
+}
+
+func TestGetUser_InvalidArgument(t *testing.T) {
+	s := &Server{}
+	_, err := s.GetUser(context.Background(), "", "alice")
+	st, _ := status.FromError(err)
+	if got := st.Code(); got != codes.InvalidArgument {
+		t.Errorf("got %v, want InvalidArgument", got)
+	}
+}
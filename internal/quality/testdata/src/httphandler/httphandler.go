@@ -0,0 +1,28 @@
+// Package httphandler is a test fixture for tests that assert the
+// status codes and headers of an HTTP handler's response.
+package httphandler
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Item is the resource served by GetItem.
+type Item struct {
+	ID string `json:"id"`
+}
+
+// GetItem responds 400, 404, or 200 with a JSON body.
+func GetItem(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if id == "" {
+		http.Error(w, "missing id", http.StatusBadRequest)
+		return
+	}
+	if id == "missing" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(Item{ID: id})
+}
@@ -0,0 +1,27 @@
+package httphandler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetItem_NotFound(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GetItem(rec, httptest.NewRequest(http.MethodGet, "/?id=missing", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("got status %d, want 404", rec.Code)
+	}
+}
+
+func TestGetItem_OK(t *testing.T) {
+	rec := httptest.NewRecorder()
+	GetItem(rec, httptest.NewRequest(http.MethodGet, "/?id=42", nil))
+	got := rec.Code
+	if got != 200 {
+		t.Errorf("got status %d, want 200", got)
+	}
+	if ct := rec.Header().Get("content-type"); ct != "application/json" {
+		t.Errorf("got Content-Type %q", ct)
+	}
+}
@@ -0,0 +1,53 @@
+// Package ifacecall is a test fixture whose tests reach their targets
+// only through interface method calls and function values, verifying
+// that call graph resolution pairs them with the concrete functions.
+package ifacecall
+
+import "errors"
+
+// ErrNotFound is returned when a key is not present in a Store.
+var ErrNotFound = errors.New("not found")
+
+// Store persists string values by key.
+type Store interface {
+	Put(key, value string) error
+	Get(key string) (string, error)
+}
+
+// MemStore is an in-memory Store.
+type MemStore struct {
+	data map[string]string
+}
+
+// NewMemStore returns an empty MemStore.
+func NewMemStore() *MemStore {
+	return &MemStore{data: make(map[string]string)}
+}
+
+// Put stores value under key. It returns an error if key is empty.
+func (m *MemStore) Put(key, value string) error {
+	if key == "" {
+		return errors.New("empty key")
+	}
+	m.data[key] = value
+	return nil
+}
+
+// Get returns the value stored under key, or ErrNotFound.
+func (m *MemStore) Get(key string) (string, error) {
+	v, ok := m.data[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+// Double returns twice n.
+func Double(n int) int {
+	return n * 2
+}
+
+// Square returns n squared.
+func Square(n int) int {
+	return n * n
+}
@@ -0,0 +1,38 @@
+package ifacecall
+
+import "testing"
+
+func TestStore_PutGet(t *testing.T) {
+	var s Store = NewMemStore()
+	if err := s.Put("k", "v"); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	got, err := s.Get("k")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got != "v" {
+		t.Errorf("Get(k) = %q, want %q", got, "v")
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(int) int
+		in   int
+		want int
+	}{
+		{name: "double", fn: Double, in: 3, want: 6},
+		{name: "square", fn: Square, in: 3, want: 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.fn(tt.in)
+			if got != tt.want {
+				t.Errorf("%s(%d) = %d, want %d", tt.name, tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+// Package gomock is a minimal stand-in for go.uber.org/mock/gomock,
+// providing the expectation API shape the mocks fixture uses.
+package gomock
+
+// Call is an expected call.
+type Call struct{}
+
+// Return sets the values the call returns.
+func (c *Call) Return(...any) *Call { return c }
+
+// Times pins the number of calls.
+func (c *Call) Times(int) *Call { return c }
+
+// AnyTimes allows any number of calls.
+func (c *Call) AnyTimes() *Call { return c }
+
+// Any matches any argument.
+func Any() any { return nil }
+
+// InOrder requires the calls to happen in order.
+func InOrder(...*Call) {}
@@ -0,0 +1,32 @@
+// Package mock is a minimal stand-in for github.com/stretchr/testify/mock,
+// providing the expectation API shape the mocks fixture uses.
+package mock
+
+import "testing"
+
+// Mock records expected and actual calls.
+type Mock struct{}
+
+// Call is an expected call.
+type Call struct{}
+
+// On registers an expected call of method.
+func (m *Mock) On(method string, args ...any) *Call { return &Call{} }
+
+// Called records a call.
+func (m *Mock) Called(args ...any) {}
+
+// AssertExpectations asserts that every On expectation was met.
+func (m *Mock) AssertExpectations(t *testing.T) bool { return true }
+
+// AssertCalled asserts that method was called with args.
+func (m *Mock) AssertCalled(t *testing.T, method string, args ...any) bool { return true }
+
+// Return sets the values the call returns.
+func (c *Call) Return(...any) *Call { return c }
+
+// Once expects exactly one call.
+func (c *Call) Once() *Call { return c }
+
+// Maybe allows the call not to happen.
+func (c *Call) Maybe() *Call { return c }
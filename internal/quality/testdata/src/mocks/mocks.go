@@ -0,0 +1,30 @@
+// Package mocks is a test fixture for a service tested through mocks
+// of its injected dependencies.
+package mocks
+
+// Repository persists users.
+type Repository interface {
+	Save(name string) error
+}
+
+// Notifier announces events.
+type Notifier interface {
+	Notify(event string)
+}
+
+// Service registers users.
+type Service struct {
+	repo     Repository
+	notifier Notifier
+}
+
+// NewService returns a Service using repo and notifier.
+func NewService(repo Repository, notifier Notifier) *Service {
+	return &Service{repo: repo, notifier: notifier}
+}
+
+// Register saves a user and announces it.
+func (s *Service) Register(name string) {
+	_ = s.repo.Save(name)
+	s.notifier.Notify("registered " + name)
+}
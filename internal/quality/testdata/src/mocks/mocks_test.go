@@ -0,0 +1,70 @@
+package mocks
+
+import (
+	"testing"
+
+	"github.com/unbound-force/gaze/internal/quality/testdata/src/mocks/gomock"
+	"github.com/unbound-force/gaze/internal/quality/testdata/src/mocks/mock"
+)
+
+// MockRepository is a gomock-style mock of Repository.
+type MockRepository struct{}
+
+// MockRepositoryRecorder records expected calls.
+type MockRepositoryRecorder struct{}
+
+func (m *MockRepository) EXPECT() *MockRepositoryRecorder { return &MockRepositoryRecorder{} }
+func (m *MockRepository) Save(string) error               { return nil }
+
+func (r *MockRepositoryRecorder) Save(any) *gomock.Call { return &gomock.Call{} }
+
+// mockNotifier is a testify-style mock of Notifier.
+type mockNotifier struct {
+	mock.Mock
+}
+
+func (m *mockNotifier) Notify(event string) { m.Called(event) }
+
+func TestRegister_Gomock(t *testing.T) {
+	repo := &MockRepository{}
+	notifier := &mockNotifier{}
+	repo.EXPECT().Save(gomock.Any()).Return(nil)
+	notifier.On("Notify", "registered ada").Maybe()
+	defer notifier.AssertExpectations(t)
+
+	NewService(repo, notifier).Register("ada")
+}
+
+func TestRegister_Testify(t *testing.T) {
+	repo := &MockRepository{}
+	notifier := &mockNotifier{}
+	repo.EXPECT().Save(gomock.Any()).Return(nil)
+	notifier.On("Notify", "registered ada").Once()
+
+	NewService(repo, notifier).Register("ada")
+
+	notifier.AssertExpectations(t)
+}
+
+// TestRegister_Unverified stubs Notify with On but never checks the
+// expectation, so only the Save expectation is asserted.
+func TestRegister_Unverified(t *testing.T) {
+	repo := &MockRepository{}
+	notifier := &mockNotifier{}
+	repo.EXPECT().Save(gomock.Any()).Return(nil)
+	notifier.On("Notify", "registered ada")
+
+	NewService(repo, notifier).Register("ada")
+}
+
+func TestRegister_Strict(t *testing.T) {
+	repo := &MockRepository{}
+	notifier := &mockNotifier{}
+	gomock.InOrder(
+		repo.EXPECT().Save(gomock.Any()).Return(nil),
+	)
+
+	NewService(repo, notifier).Register("ada")
+
+	notifier.AssertCalled(t, "Notify", "registered ada")
+}
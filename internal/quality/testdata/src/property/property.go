@@ -0,0 +1,33 @@
+// Package property is a test fixture whose functions are tested by
+// fuzz targets and property-based tests rather than fixed examples.
+package property
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// Reverse returns s with its runes in reverse order.
+func Reverse(s string) (string, error) {
+	if !utf8.ValidString(s) {
+		return s, errors.New("input is not valid UTF-8")
+	}
+	r := []rune(s)
+	for i, j := 0, len(r)-1; i < j; i, j = i+1, j-1 {
+		r[i], r[j] = r[j], r[i]
+	}
+	return string(r), nil
+}
+
+// Abs returns the absolute value of n.
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// Double returns twice n.
+func Double(n int) int {
+	return n + n
+}
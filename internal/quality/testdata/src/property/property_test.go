@@ -0,0 +1,41 @@
+package property
+
+import (
+	"testing"
+	"testing/quick"
+	"unicode/utf8"
+)
+
+func FuzzReverse(f *testing.F) {
+	f.Add("hello")
+	f.Fuzz(func(t *testing.T, s string) {
+		rev, err := Reverse(s)
+		if err != nil {
+			return
+		}
+		if !utf8.ValidString(rev) {
+			t.Errorf("Reverse(%q) = %q, not valid UTF-8", s, rev)
+		}
+		if utf8.RuneCountInString(rev) != utf8.RuneCountInString(s) {
+			t.Errorf("Reverse(%q) = %q, rune count changed", s, rev)
+		}
+	})
+}
+
+func TestAbs_Property(t *testing.T) {
+	nonNegative := func(n int) bool {
+		if n == -n && n != 0 {
+			return true // math.MinInt overflows
+		}
+		return Abs(n) >= 0
+	}
+	if err := quick.Check(nonNegative, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDouble_Property(t *testing.T) {
+	if err := quick.CheckEqual(Double, func(n int) int { return 2 * n }, nil); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,28 @@
+// Package receivers is a test fixture for targets that are
+// value-receiver methods and methods of generic types.
+package receivers
+
+// Point is a position on a grid.
+type Point struct {
+	X, Y int
+}
+
+// Add returns the sum of p and q.
+func (p Point) Add(q Point) Point {
+	return Point{X: p.X + q.X, Y: p.Y + q.Y}
+}
+
+// Stack is a last-in, first-out stack.
+type Stack[T any] struct {
+	items []T
+}
+
+// Len returns the number of items on the stack.
+func (s *Stack[T]) Len() int {
+	return len(s.items)
+}
+
+// Peek returns the top item of a non-empty stack.
+func (s Stack[T]) Peek() T {
+	return s.items[len(s.items)-1]
+}
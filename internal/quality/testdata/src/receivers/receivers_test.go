@@ -0,0 +1,24 @@
+package receivers
+
+import "testing"
+
+func TestPoint_Add(t *testing.T) {
+	got := Point{X: 1, Y: 2}.Add(Point{X: 3, Y: 4})
+	if got != (Point{X: 4, Y: 6}) {
+		t.Errorf("Add() = %v, want {4 6}", got)
+	}
+}
+
+func TestStack_Len(t *testing.T) {
+	s := &Stack[int]{items: []int{1, 2}}
+	if n := s.Len(); n != 2 {
+		t.Errorf("Len() = %d, want 2", n)
+	}
+}
+
+func TestStack_Peek(t *testing.T) {
+	s := Stack[string]{items: []string{"a"}}
+	if top := s.Peek(); top != "a" {
+		t.Errorf("Peek() = %q, want a", top)
+	}
+}
@@ -49,3 +49,14 @@ func TestAbs(t *testing.T) {
 		}
 	}
 }
+
+func TestAbs_Subtests(t *testing.T) {
+	t.Run("negative input", func(t *testing.T) {
+		if got := Abs(-7); got != 7 {
+			t.Errorf("Abs(-7) = %d, want 7", got)
+		}
+	})
+	t.Run("smoke", func(t *testing.T) {
+		Abs(0)
+	})
+}
@@ -0,0 +1,31 @@
+// Package valuedomain is a test fixture for value-domain coverage
+// of functions returning enumerable constants.
+package valuedomain
+
+// Label is a classification result.
+type Label int
+
+const (
+	Contractual Label = iota
+	Incidental
+	Ambiguous
+)
+
+// Classify maps a confidence score to a label.
+func Classify(score int) Label {
+	if score >= 80 {
+		return Contractual
+	}
+	if score < 50 {
+		return Incidental
+	}
+	return Ambiguous
+}
+
+// Valid reports whether s is non-empty.
+func Valid(s string) bool {
+	if s == "" {
+		return false
+	}
+	return true
+}
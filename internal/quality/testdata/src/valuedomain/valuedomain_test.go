@@ -0,0 +1,35 @@
+package valuedomain
+
+import "testing"
+
+func TestClassify_High(t *testing.T) {
+	got := Classify(90)
+	if got != Contractual {
+		t.Errorf("Classify(90) = %v, want Contractual", got)
+	}
+}
+
+func TestClassify_Table(t *testing.T) {
+	tests := []struct {
+		name  string
+		score int
+		want  Label
+	}{
+		{name: "low", score: 10, want: Incidental},
+		{name: "high", score: 95},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Classify(tt.score); got != tt.want {
+				t.Errorf("Classify(%d) = %v, want %v", tt.score, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValid(t *testing.T) {
+	ok := Valid("x")
+	if ok != true {
+		t.Error("Valid(\"x\") = false, want true")
+	}
+}
@@ -0,0 +1,219 @@
+package quality
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// computeValueCoverage reports, for each covered contractual effect
+// with a constant Domain, which domain values the test's assertions
+// expect. Any assertion on a ReturnValue covers the position, so a
+// test checking only `got != Contractual` covers Classify fully; the
+// value coverage shows that Incidental and Ambiguous were never
+// asserted.
+//
+// The expected values of an assertion are the constants it compares
+// the result against:
+//
+//	if got != Contractual { ... }            // Contractual
+//	assert.Equal(t, Incidental, got)         // Incidental
+//	assert.True(t, ok)                       // true
+//	assert.True(t, got == Ambiguous)         // Ambiguous
+//	if got != tt.want { ... }                // every row's want
+//
+// Operands are followed through locals (`want := Ambiguous`) and
+// table fields, but not into calls, so the arguments of the call
+// under test are not mistaken for expectations.
+func computeValueCoverage(
+	testDecl *ast.FuncDecl,
+	testPkg *packages.Package,
+	sites []AssertionSite,
+	mappings []taxonomy.AssertionMapping,
+	effects []taxonomy.SideEffect,
+) []taxonomy.ValueCoverage {
+	if testPkg == nil || testPkg.TypesInfo == nil {
+		return nil
+	}
+	domains := make(map[string]taxonomy.SideEffect)
+	for _, e := range effects {
+		if len(e.Domain) > 0 && isContractual(e) {
+			domains[e.ID] = e
+		}
+	}
+	if len(domains) == 0 {
+		return nil
+	}
+
+	v := &valueScanner{info: testPkg.TypesInfo}
+	if testDecl != nil && testDecl.Body != nil {
+		s := &subtestScanner{root: testDecl.Body, info: testPkg.TypesInfo, fset: testPkg.Fset}
+		s.scanStmts(testDecl.Body.List, testDecl.Name.Name)
+		for _, scope := range s.scopes {
+			if scope.row != nil {
+				v.rows = append(v.rows, scope.row)
+			}
+		}
+	}
+
+	sitesByLoc := make(map[string][]AssertionSite, len(sites))
+	for _, site := range sites {
+		sitesByLoc[site.Location] = append(sitesByLoc[site.Location], site)
+	}
+
+	asserted := make(map[string]map[string]bool)
+	for _, m := range mappings {
+		if _, ok := domains[m.SideEffectID]; !ok {
+			continue
+		}
+		if asserted[m.SideEffectID] == nil {
+			asserted[m.SideEffectID] = make(map[string]bool)
+		}
+		for _, site := range sitesByLoc[m.AssertionLocation] {
+			for _, val := range v.expected(site) {
+				asserted[m.SideEffectID][val.ExactString()] = true
+			}
+		}
+	}
+
+	var out []taxonomy.ValueCoverage
+	for _, e := range effects {
+		got, ok := asserted[e.ID]
+		if !ok {
+			continue
+		}
+		vc := taxonomy.ValueCoverage{SideEffectID: e.ID}
+		for _, d := range domains[e.ID].Domain {
+			vc.Values = append(vc.Values, d.String())
+			if got[d.Value] {
+				vc.Asserted = append(vc.Asserted, d.String())
+			}
+		}
+		out = append(out, vc)
+	}
+	return out
+}
+
+// valueScanner extracts the constants an assertion expects.
+type valueScanner struct {
+	info *types.Info
+	rows []*tableRow
+}
+
+// expected returns the constant operands of the assertion at site.
+func (v *valueScanner) expected(site AssertionSite) []constant.Value {
+	if site.Expr == nil {
+		return nil
+	}
+	var operands []ast.Expr
+	switch expr := ast.Unparen(site.Expr).(type) {
+	case *ast.BinaryExpr:
+		// `if got != want { t.Error(...) }` expects got == want.
+		if expr.Op != token.NEQ {
+			return nil
+		}
+		operands = []ast.Expr{expr.X, expr.Y}
+	case *ast.CallExpr:
+		sel, ok := expr.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return nil
+		}
+		switch sel.Sel.Name {
+		case "Equal", "EqualValues", "Exactly":
+			if len(expr.Args) >= 3 {
+				operands = expr.Args[1:3]
+			}
+		case "Diff":
+			if len(expr.Args) >= 2 {
+				operands = expr.Args[:2]
+			}
+		case "True", "False":
+			if len(expr.Args) < 2 {
+				return nil
+			}
+			// assert.True(t, got == Ambiguous) expects Ambiguous;
+			// assert.True(t, ok) expects ok to be true.
+			if cmp, ok := ast.Unparen(expr.Args[1]).(*ast.BinaryExpr); ok {
+				if cmp.Op != token.EQL || sel.Sel.Name != "True" {
+					return nil
+				}
+				operands = []ast.Expr{cmp.X, cmp.Y}
+				break
+			}
+			return []constant.Value{constant.MakeBool(sel.Sel.Name == "True")}
+		}
+	}
+
+	var vals []constant.Value
+	for _, op := range operands {
+		vals = append(vals, v.constants(op, site, 0)...)
+	}
+	return vals
+}
+
+// constants resolves expr to the constants it can hold: its own
+// value, the initializer of a local, or a table field across the
+// rows that execute the assertion.
+func (v *valueScanner) constants(expr ast.Expr, site AssertionSite, depth int) []constant.Value {
+	if depth > 4 {
+		return nil
+	}
+	expr = ast.Unparen(expr)
+	if tv, ok := v.info.Types[expr]; ok && tv.Value != nil {
+		return []constant.Value{tv.Value}
+	}
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if rhs := definingExpr(site.FuncDecl, v.info, v.info.Uses[e]); rhs != nil {
+			return v.constants(rhs, site, depth+1)
+		}
+	case *ast.SelectorExpr:
+		var vals []constant.Value
+		for _, row := range v.rows {
+			name, ok := row.table.field(e, v.info)
+			if !ok || !row.executes(site.Expr.Pos(), v.info) {
+				continue
+			}
+			field := row.fields[name]
+			if field == nil {
+				// An omitted field holds its zero value, which for
+				// an iota enumeration is usually its first member.
+				if zero := zeroConstant(v.info.TypeOf(e)); zero != nil {
+					vals = append(vals, zero)
+				}
+				continue
+			}
+			if tv, ok := v.info.Types[field]; ok && tv.Value != nil {
+				vals = append(vals, tv.Value)
+			}
+		}
+		return vals
+	}
+	return nil
+}
+
+// zeroConstant returns the zero value of a basic type, or nil for
+// other types.
+func zeroConstant(t types.Type) constant.Value {
+	if t == nil {
+		return nil
+	}
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+	switch {
+	case basic.Info()&types.IsBoolean != 0:
+		return constant.MakeBool(false)
+	case basic.Info()&types.IsString != 0:
+		return constant.MakeString("")
+	case basic.Info()&types.IsNumeric != 0:
+		return constant.MakeInt64(0)
+	}
+	return nil
+}
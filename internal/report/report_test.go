@@ -158,6 +158,35 @@ func TestWriteText_HasSideEffects(t *testing.T) {
 	}
 }
 
+func TestWriteText_ConditionalEffects(t *testing.T) {
+	results := sampleResults()
+	effects := results[0].SideEffects
+	effects[0].Occurrences = []taxonomy.Occurrence{
+		{Location: "store.go:45:3", Conditions: []string{"err != nil"}},
+		{Location: "store.go:56:2"},
+	}
+	effects[1].Occurrences = []taxonomy.Occurrence{
+		{Location: "store.go:45:3", Conditions: []string{"item.ID == 0", "err != nil"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteText(&buf, results); err != nil {
+		t.Fatal(err)
+	}
+
+	output := stripANSI(buf.String())
+	if !strings.Contains(output, "Conditional effects:") {
+		t.Fatalf("text output missing conditional effects:\n%s", output)
+	}
+	if !strings.Contains(output, "when item.ID == 0 && err != nil") {
+		t.Errorf("text output missing ErrorReturn condition:\n%s", output)
+	}
+	// The ReturnValue also occurs unconditionally, so it is not listed.
+	if strings.Contains(output, "      ReturnValue") {
+		t.Errorf("unconditional ReturnValue listed as conditional:\n%s", output)
+	}
+}
+
 func TestWriteText_HasSummary(t *testing.T) {
 	var buf bytes.Buffer
 	if err := WriteText(&buf, sampleResults()); err != nil {
@@ -439,6 +468,57 @@ func TestWriteJSON_EmptyResults_ValidAgainstSchema(t *testing.T) {
 	}
 }
 
+// TestSchemaWithCustomTypes verifies that output containing a custom
+// effect type from a detection rule validates only against the schema
+// extended with that type.
+func TestSchemaWithCustomTypes(t *testing.T) {
+	compile := func(schema string) *jsonschema.Schema {
+		t.Helper()
+		sch, err := jsonschema.UnmarshalJSON(strings.NewReader(schema))
+		if err != nil {
+			t.Fatalf("failed to parse schema JSON: %v", err)
+		}
+		compiler := jsonschema.NewCompiler()
+		if err := compiler.AddResource("schema.json", sch); err != nil {
+			t.Fatalf("failed to add schema resource: %v", err)
+		}
+		compiled, err := compiler.Compile("schema.json")
+		if err != nil {
+			t.Fatalf("failed to compile schema: %v", err)
+		}
+		return compiled
+	}
+
+	results := sampleResults()
+	results[0].SideEffects = append(results[0].SideEffects, taxonomy.SideEffect{
+		ID:          "se-custom01",
+		Type:        "EventPublish",
+		Tier:        taxonomy.TierP2,
+		Location:    "orders.go:12:2",
+		Description: "calls Producer.Send (detection rule)",
+		Target:      "Producer.Send",
+	})
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, results, "0.1.0"); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+	inst, err := jsonschema.UnmarshalJSON(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("failed to parse JSON output: %v", err)
+	}
+
+	if err := compile(Schema).Validate(inst); err == nil {
+		t.Error("base schema accepted undeclared custom type EventPublish")
+	}
+	if SchemaWithCustomTypes(nil) != Schema {
+		t.Error("SchemaWithCustomTypes(nil) differs from Schema")
+	}
+	extended := SchemaWithCustomTypes([]taxonomy.CustomType{{Name: "EventPublish", Tier: taxonomy.TierP2}})
+	if err := compile(extended).Validate(inst); err != nil {
+		t.Errorf("JSON output does not conform to extended schema:\n%v", err)
+	}
+}
+
 // TestQualitySchema_Compiles verifies the QualitySchema constant is
 // valid JSON Schema that can be compiled without errors. This also
 // exercises the QualitySchema constant to prevent it from being
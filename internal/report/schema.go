@@ -1,6 +1,27 @@
 // Package report provides output formatters for Gaze analysis results.
 package report
 
+import (
+	"strings"
+
+	"github.com/unbound-force/gaze/internal/taxonomy"
+)
+
+// SchemaWithCustomTypes returns Schema with the custom side effect
+// types declared by .gaze.yaml detection rules appended to the
+// SideEffect type enum, so that output containing them validates.
+func SchemaWithCustomTypes(custom []taxonomy.CustomType) string {
+	if len(custom) == 0 {
+		return Schema
+	}
+	var extra strings.Builder
+	for _, ct := range custom {
+		extra.WriteString(`, "` + string(ct.Name) + `"`)
+	}
+	const last = `"ClosureCaptureMutation"`
+	return strings.Replace(Schema, last, last+extra.String(), 1)
+}
+
 // Schema is the JSON Schema (Draft 2020-12) for the Gaze analysis
 // JSON output. It documents the structure returned by WriteJSON.
 const Schema = `{
@@ -37,6 +58,10 @@ const Schema = `{
       "type": "object",
       "required": ["package", "function", "signature", "location"],
       "properties": {
+        "function_id": {
+          "type": "string",
+          "description": "Canonical function identity shared by analyze, quality and crap output: import path, receiver type and name (e.g., 'example.com/store.(*Cache[K]).Get')"
+        },
         "package": {
           "type": "string",
           "description": "Full import path"
@@ -65,21 +90,25 @@ const Schema = `{
       "properties": {
         "id": {
           "type": "string",
-          "description": "Stable identifier (se-XXXXXXXX)"
+          "description": "Stable identifier (se- and 16 hex characters) derived from the package, receiver-qualified function, effect type and what the effect acts on; unchanged when code moves"
         },
         "type": {
           "type": "string",
-          "description": "Side effect type from taxonomy",
+          "description": "Side effect type from taxonomy, or a custom type declared by a detection rule in .gaze.yaml",
           "enum": [
             "ReturnValue", "ErrorReturn", "SentinelError",
             "ReceiverMutation", "PointerArgMutation",
             "SliceMutation", "MapMutation", "GlobalMutation",
             "WriterOutput", "HTTPResponseWrite",
+            "HTTPStatusCode", "HTTPHeader",
             "ChannelSend", "ChannelClose", "DeferredReturnMutation",
+            "ErrorWrap", "RespectsCancellation", "GRPCStatusCode",
             "FileSystemWrite", "FileSystemDelete", "FileSystemMeta",
             "DatabaseWrite", "DatabaseTransaction",
             "GoroutineSpawn", "Panic", "CallbackInvocation",
-            "LogWrite", "ContextCancellation",
+            "LogWrite", "ContextCancellation", "InterfaceInteraction",
+            "NetworkDial", "HTTPRequest", "RPCCall",
+            "MessagePublish", "MetricEmit",
             "StdoutWrite", "StderrWrite", "EnvVarMutation",
             "MutexOp", "WaitGroupOp", "AtomicOp",
             "TimeDependency", "ProcessExit", "RecoverBehavior",
@@ -105,12 +134,61 @@ const Schema = `{
           "type": "string",
           "description": "Affected entity (field, variable, type, etc.)"
         },
+        "domain": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/DomainValue" },
+          "description": "Distinct constants that can reach a ReturnValue position, when every return yields a constant"
+        },
+        "fields": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Struct fields the function sets: fields of a returned struct, or nested paths of a mutated receiver field (e.g., Nested.Value)"
+        },
+        "sentinels": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Upstream errors of an ErrorWrap that are package-level error variables, named as in target (e.g., ErrNotFound)"
+        },
+        "occurrences": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/Occurrence" },
+          "description": "Every site where the effect happens, with the branch conditions guarding each"
+        },
         "classification": {
           "$ref": "#/$defs/Classification",
           "description": "Contractual classification (only present when --classify is used)"
         }
       }
     },
+    "Occurrence": {
+      "type": "object",
+      "required": ["location"],
+      "properties": {
+        "location": {
+          "type": "string",
+          "description": "Source position of the site"
+        },
+        "conditions": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Branch conditions that hold whenever the site executes, outermost first (e.g., err != nil)"
+        }
+      }
+    },
+    "DomainValue": {
+      "type": "object",
+      "required": ["value"],
+      "properties": {
+        "value": {
+          "type": "string",
+          "description": "Exact constant value (e.g., 2, true, \"open\")"
+        },
+        "name": {
+          "type": "string",
+          "description": "Declared constant of the result type with this value"
+        }
+      }
+    },
     "Classification": {
       "type": "object",
       "required": ["label", "confidence", "signals"],
@@ -220,6 +298,15 @@ const QualitySchema = `{
           "type": "string",
           "description": "Name of the test function"
         },
+        "test_package": {
+          "type": "string",
+          "description": "Import path of the package declaring the test (external test packages end in _test)"
+        },
+        "test_kind": {
+          "type": "string",
+          "enum": ["test", "example", "fuzz", "property"],
+          "description": "Form of the test function: TestXxx, ExampleXxx with an output comment, FuzzXxx, or a TestXxx checking testing/quick or rapid properties"
+        },
         "test_location": {
           "type": "string",
           "description": "Source position (file:line)"
@@ -247,13 +334,88 @@ const QualitySchema = `{
           "maximum": 100,
           "description": "Fraction of test assertions successfully pattern-matched (0-100)"
         },
+        "subtests": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/SubtestCoverage" },
+          "description": "Contract coverage per t.Run subtest and per table-driven test row"
+        },
+        "value_coverage": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/ValueCoverage" },
+          "description": "Domain values asserted for each covered return position with an enumerable constant domain"
+        },
+        "field_coverage": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/FieldCoverage" },
+          "description": "Fields asserted for each covered struct return and for the receiver's mutated fields"
+        },
         "metadata": { "$ref": "#/$defs/Metadata" }
       }
     },
+    "SubtestCoverage": {
+      "type": "object",
+      "required": ["name", "location", "contract_coverage"],
+      "properties": {
+        "name": {
+          "type": "string",
+          "description": "Full subtest name (e.g., TestGreet/empty); unnamed table rows are numbered (#00)"
+        },
+        "location": {
+          "type": "string",
+          "description": "Source position of the t.Run call or table row literal"
+        },
+        "contract_coverage": { "$ref": "#/$defs/ContractCoverage" },
+        "want_fields": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Table fields referenced by the assertions executed for this row"
+        }
+      }
+    },
+    "ValueCoverage": {
+      "type": "object",
+      "required": ["side_effect_id", "values", "asserted"],
+      "properties": {
+        "side_effect_id": { "type": "string" },
+        "values": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "The return position's constant domain"
+        },
+        "asserted": {
+          "oneOf": [
+            { "type": "array", "items": { "type": "string" } },
+            { "type": "null" }
+          ],
+          "description": "Domain values the test compares the result against"
+        }
+      }
+    },
+    "FieldCoverage": {
+      "type": "object",
+      "required": ["side_effect_id", "type", "fields", "asserted"],
+      "properties": {
+        "side_effect_id": { "type": "string" },
+        "type": { "type": "string" },
+        "fields": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Fields the function sets, in declaration order"
+        },
+        "asserted": {
+          "oneOf": [
+            { "type": "array", "items": { "type": "string" } },
+            { "type": "null" }
+          ],
+          "description": "Fields read by some assertion; deep-equality assertions read every field"
+        }
+      }
+    },
     "FunctionTarget": {
       "type": "object",
       "required": ["package", "function", "signature", "location"],
       "properties": {
+        "function_id": { "type": "string" },
         "package": { "type": "string" },
         "function": { "type": "string" },
         "receiver": { "type": "string" },
@@ -306,6 +468,11 @@ const QualitySchema = `{
             { "type": "null" }
           ],
           "description": "Go code snippets suggesting how to assert on each discarded return. Parallel to discarded_returns: len(discarded_return_hints) == len(discarded_returns). Omitted when there are no discarded returns."
+        },
+        "property_covered": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "IDs of covered contractual effects asserted by at least one property-based invariant (fuzz target, testing/quick, or rapid). Omitted when there are none."
         }
       }
     },
@@ -348,7 +515,7 @@ const QualitySchema = `{
         },
         "assertion_type": {
           "type": "string",
-          "enum": ["equality", "error_check", "nil_check", "diff_check", "custom"],
+          "enum": ["equality", "error_check", "nil_check", "diff_check", "custom", "example_output", "property", "interaction", "error_match"],
           "description": "Kind of assertion"
         },
         "side_effect_id": {
@@ -361,6 +528,10 @@ const QualitySchema = `{
           "maximum": 100,
           "description": "Mapping confidence (0-100)"
         },
+        "strict": {
+          "type": "boolean",
+          "description": "True for an interaction assertion that also pins the call count or order (Times, InOrder). Omitted when false."
+        },
         "unmapped_reason": {
           "type": "string",
           "enum": ["helper_param", "inline_call", "no_effect_match"],
@@ -390,7 +561,7 @@ const QualitySchema = `{
         "total_tests": { "type": "integer" },
         "average_contract_coverage": {
           "type": "number",
-          "description": "Mean coverage across tests (0-100)"
+          "description": "Mean coverage across tests, with untested contracts counted as 0% (0-100)"
         },
         "total_over_specifications": { "type": "integer" },
         "worst_coverage_tests": {
@@ -400,7 +571,82 @@ const QualitySchema = `{
           ],
           "description": "Bottom 5 tests by coverage"
         },
-        "assertion_detection_confidence": { "type": "integer" }
+        "assertion_detection_confidence": { "type": "integer" },
+        "function_coverage": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/FunctionCoverage" },
+          "description": "Contract coverage per function, unioned across all tests that target it"
+        },
+        "untested_contracts": {
+          "type": "array",
+          "items": { "$ref": "#/$defs/FunctionCoverage" },
+          "description": "Functions with contractual effects but no paired test; counted as 0% in average_contract_coverage"
+        },
+        "never_executed_contracts": {
+          "type": "integer",
+          "description": "Contractual effects whose source line the coverage profile shows never ran (only with --coverprofile)"
+        }
+      }
+    },
+    "FunctionCoverage": {
+      "type": "object",
+      "required": ["function", "contract_coverage", "effects", "tests"],
+      "properties": {
+        "function": { "$ref": "#/$defs/FunctionTarget" },
+        "contract_coverage": { "$ref": "#/$defs/ContractCoverage" },
+        "effects": {
+          "oneOf": [
+            { "type": "array", "items": { "$ref": "#/$defs/EffectCoverage" } },
+            { "type": "null" }
+          ],
+          "description": "Contractual effects with the tests that assert on each"
+        },
+        "tests": {
+          "oneOf": [
+            { "type": "array", "items": { "type": "string" } },
+            { "type": "null" }
+          ],
+          "description": "Test functions targeting this function"
+        }
+      }
+    },
+    "EffectCoverage": {
+      "type": "object",
+      "required": ["side_effect_id", "type", "covered_by"],
+      "properties": {
+        "side_effect_id": { "type": "string" },
+        "type": { "type": "string" },
+        "covered_by": {
+          "oneOf": [
+            { "type": "array", "items": { "type": "string" } },
+            { "type": "null" }
+          ],
+          "description": "Test functions asserting on this effect; empty when untested"
+        },
+        "values": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Constant domain of a ReturnValue position"
+        },
+        "asserted_values": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Domain values asserted by any test"
+        },
+        "fields": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Struct fields set by a ReturnValue or ReceiverMutation effect"
+        },
+        "asserted_fields": {
+          "type": "array",
+          "items": { "type": "string" },
+          "description": "Fields asserted by any test"
+        },
+        "never_executed": {
+          "type": "boolean",
+          "description": "True when the coverage profile shows the effect's source line never ran (only with --coverprofile)"
+        }
       }
     },
     "Metadata": {
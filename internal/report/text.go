@@ -189,6 +189,8 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 		_, _ = fmt.Fprintln(w, t)
 	}
 
+	writeConditional(w, result.SideEffects, s)
+
 	// Tier summary.
 	tierCounts := make(map[taxonomy.Tier]int)
 	for _, e := range result.SideEffects {
@@ -210,3 +212,38 @@ func writeOneResult(w io.Writer, result taxonomy.AnalysisResult, s Styles, showC
 
 	return nil
 }
+
+// writeConditional lists the effects that happen only under some
+// branch conditions, such as an error returned only when a call
+// fails, with the conditions guarding each site.
+func writeConditional(w io.Writer, effects []taxonomy.SideEffect, s Styles) {
+	header := false
+	for _, e := range effects {
+		if !conditional(e) {
+			continue
+		}
+		if !header {
+			_, _ = fmt.Fprintln(w, "    Conditional effects:")
+			header = true
+		}
+		_, _ = fmt.Fprintf(w, "      %s\n", e.Type)
+		for _, o := range e.Occurrences {
+			_, _ = fmt.Fprintln(w, s.Muted.Render(
+				"        when "+strings.Join(o.Conditions, " && ")))
+		}
+	}
+}
+
+// conditional reports whether every occurrence of e is guarded by a
+// condition.
+func conditional(e taxonomy.SideEffect) bool {
+	if len(e.Occurrences) == 0 {
+		return false
+	}
+	for _, o := range e.Occurrences {
+		if len(o.Conditions) == 0 {
+			return false
+		}
+	}
+	return true
+}
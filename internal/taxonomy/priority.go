@@ -10,6 +10,20 @@ func TierOf(t SideEffectType) Tier {
 	return tier
 }
 
+// IsBuiltin reports whether t is one of the side effect types defined
+// by the taxonomy, as opposed to a custom type declared in .gaze.yaml.
+func IsBuiltin(t SideEffectType) bool {
+	_, ok := tierMap[t]
+	return ok
+}
+
+// CustomType is a side effect type declared by a detection rule in
+// .gaze.yaml rather than by the taxonomy.
+type CustomType struct {
+	Name SideEffectType
+	Tier Tier
+}
+
 var tierMap = map[SideEffectType]Tier{
 	// P0
 	ReturnValue:        TierP0,
@@ -24,21 +38,32 @@ var tierMap = map[SideEffectType]Tier{
 	GlobalMutation:         TierP1,
 	WriterOutput:           TierP1,
 	HTTPResponseWrite:      TierP1,
+	HTTPStatusCode:         TierP1,
+	HTTPHeader:             TierP1,
 	ChannelSend:            TierP1,
 	ChannelClose:           TierP1,
 	DeferredReturnMutation: TierP1,
+	ErrorWrap:              TierP1,
+	RespectsCancellation:   TierP1,
+	GRPCStatusCode:         TierP1,
 
 	// P2
-	FileSystemWrite:     TierP2,
-	FileSystemDelete:    TierP2,
-	FileSystemMeta:      TierP2,
-	DatabaseWrite:       TierP2,
-	DatabaseTransaction: TierP2,
-	GoroutineSpawn:      TierP2,
-	Panic:               TierP2,
-	CallbackInvocation:  TierP2,
-	LogWrite:            TierP2,
-	ContextCancellation: TierP2,
+	FileSystemWrite:      TierP2,
+	FileSystemDelete:     TierP2,
+	FileSystemMeta:       TierP2,
+	DatabaseWrite:        TierP2,
+	DatabaseTransaction:  TierP2,
+	GoroutineSpawn:       TierP2,
+	Panic:                TierP2,
+	CallbackInvocation:   TierP2,
+	LogWrite:             TierP2,
+	ContextCancellation:  TierP2,
+	InterfaceInteraction: TierP2,
+	NetworkDial:          TierP2,
+	HTTPRequest:          TierP2,
+	RPCCall:              TierP2,
+	MessagePublish:       TierP2,
+	MetricEmit:           TierP2,
 
 	// P3
 	StdoutWrite:     TierP3,
@@ -6,6 +6,8 @@ import (
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -28,23 +30,34 @@ const (
 	GlobalMutation         SideEffectType = "GlobalMutation"
 	WriterOutput           SideEffectType = "WriterOutput"
 	HTTPResponseWrite      SideEffectType = "HTTPResponseWrite"
+	HTTPStatusCode         SideEffectType = "HTTPStatusCode"
+	HTTPHeader             SideEffectType = "HTTPHeader"
 	ChannelSend            SideEffectType = "ChannelSend"
 	ChannelClose           SideEffectType = "ChannelClose"
 	DeferredReturnMutation SideEffectType = "DeferredReturnMutation"
+	ErrorWrap              SideEffectType = "ErrorWrap"
+	RespectsCancellation   SideEffectType = "RespectsCancellation"
+	GRPCStatusCode         SideEffectType = "GRPCStatusCode"
 )
 
 // P2 — Important.
 const (
-	FileSystemWrite     SideEffectType = "FileSystemWrite"
-	FileSystemDelete    SideEffectType = "FileSystemDelete"
-	FileSystemMeta      SideEffectType = "FileSystemMeta"
-	DatabaseWrite       SideEffectType = "DatabaseWrite"
-	DatabaseTransaction SideEffectType = "DatabaseTransaction"
-	GoroutineSpawn      SideEffectType = "GoroutineSpawn"
-	Panic               SideEffectType = "Panic"
-	CallbackInvocation  SideEffectType = "CallbackInvocation"
-	LogWrite            SideEffectType = "LogWrite"
-	ContextCancellation SideEffectType = "ContextCancellation"
+	FileSystemWrite      SideEffectType = "FileSystemWrite"
+	FileSystemDelete     SideEffectType = "FileSystemDelete"
+	FileSystemMeta       SideEffectType = "FileSystemMeta"
+	DatabaseWrite        SideEffectType = "DatabaseWrite"
+	DatabaseTransaction  SideEffectType = "DatabaseTransaction"
+	GoroutineSpawn       SideEffectType = "GoroutineSpawn"
+	Panic                SideEffectType = "Panic"
+	CallbackInvocation   SideEffectType = "CallbackInvocation"
+	LogWrite             SideEffectType = "LogWrite"
+	ContextCancellation  SideEffectType = "ContextCancellation"
+	InterfaceInteraction SideEffectType = "InterfaceInteraction"
+	NetworkDial          SideEffectType = "NetworkDial"
+	HTTPRequest          SideEffectType = "HTTPRequest"
+	RPCCall              SideEffectType = "RPCCall"
+	MessagePublish       SideEffectType = "MessagePublish"
+	MetricEmit           SideEffectType = "MetricEmit"
 )
 
 // P3 — Nice to Have.
@@ -158,13 +171,109 @@ type SideEffect struct {
 	// channel name, return type, etc.).
 	Target string `json:"target"`
 
+	// Domain lists the distinct constant values that can reach a
+	// ReturnValue position, when every return statement yields a
+	// constant. Empty when the value set cannot be enumerated.
+	Domain []DomainValue `json:"domain,omitempty"`
+
+	// Fields lists the struct fields the function sets: for a
+	// ReturnValue of struct or pointer-to-struct type, the fields
+	// of the returned value; for a ReceiverMutation of a struct
+	// field, the nested paths written (e.g., "Nested.Value").
+	Fields []string `json:"fields,omitempty"`
+
+	// Sentinels lists the upstream errors of an ErrorWrap that are
+	// package-level error variables, named as in Target (e.g.,
+	// "ErrNotFound", "fs.ErrNotExist"). The other names in Target
+	// are callees or local variables.
+	Sentinels []string `json:"sentinels,omitempty"`
+
+	// Occurrences lists every site at which the effect happens,
+	// with the conditions that guard each one. Location is the
+	// first of them. Empty for package-level effects.
+	Occurrences []Occurrence `json:"occurrences,omitempty"`
+
 	// Classification is the contractual classification of this
 	// side effect. Nil when classification has not been performed.
 	Classification *Classification `json:"classification,omitempty"`
 }
 
+// Occurrence is one site at which a side effect happens.
+type Occurrence struct {
+	// Location is the source position (file:line:col).
+	Location string `json:"location"`
+
+	// Conditions are the branch conditions that must hold for
+	// execution to reach this site, outermost first (e.g.,
+	// "err != nil"). Empty when the site runs on every path.
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// ParseLocation splits a "file:line:col" or "file:line" source
+// position, as found in Location fields, into its parts. col is 0
+// when the position has no column. The file may itself contain
+// colons (e.g., a Windows drive letter).
+func ParseLocation(loc string) (file string, line, col int, ok bool) {
+	parts := strings.Split(loc, ":")
+	n := len(parts)
+	if n >= 3 {
+		l, errLine := strconv.Atoi(parts[n-2])
+		c, errCol := strconv.Atoi(parts[n-1])
+		if errLine == nil && errCol == nil {
+			return strings.Join(parts[:n-2], ":"), l, c, true
+		}
+	}
+	if n >= 2 {
+		if l, err := strconv.Atoi(parts[n-1]); err == nil {
+			return strings.Join(parts[:n-1], ":"), l, 0, true
+		}
+	}
+	return "", 0, 0, false
+}
+
+// DomainValue is one constant a return position can yield.
+type DomainValue struct {
+	// Value is the exact constant value as printed by
+	// go/constant (e.g., "2", "true", "\"open\"").
+	Value string `json:"value"`
+
+	// Name is the declared constant with this value and the
+	// result's type (e.g., "Contractual"). Empty for literals
+	// with no named constant.
+	Name string `json:"name,omitempty"`
+}
+
+// String returns the constant's name, or its value when unnamed.
+func (d DomainValue) String() string {
+	if d.Name != "" {
+		return d.Name
+	}
+	return d.Value
+}
+
+// FunctionID canonically identifies a function across the analyze,
+// quality and crap commands: the import path, then the receiver type
+// as written in the declaration (pointer-ness and type parameters
+// included) and the name, e.g. "example.com/store.(*Cache[K]).Get"
+// or "example.com/store.Open". Results of the three commands can be
+// joined on it.
+type FunctionID string
+
+// NewFunctionID returns the FunctionID of the function name declared
+// in package pkgPath with the given receiver type ("" for package-level
+// functions), formatted as by go/types.ExprString (e.g., "*Cache[K]").
+func NewFunctionID(pkgPath, receiver, name string) FunctionID {
+	if receiver != "" {
+		return FunctionID(fmt.Sprintf("%s.(%s).%s", pkgPath, receiver, name))
+	}
+	return FunctionID(pkgPath + "." + name)
+}
+
 // FunctionTarget identifies the function under analysis.
 type FunctionTarget struct {
+	// ID is the canonical identity of the function.
+	ID FunctionID `json:"function_id,omitempty"`
+
 	// Package is the full import path.
 	Package string `json:"package"`
 
@@ -182,6 +291,15 @@ type FunctionTarget struct {
 	Location string `json:"location"`
 }
 
+// FunctionID returns the target's canonical identity: ID when set,
+// otherwise derived from Package, Receiver and Function.
+func (ft FunctionTarget) FunctionID() FunctionID {
+	if ft.ID != "" {
+		return ft.ID
+	}
+	return NewFunctionID(ft.Package, ft.Receiver, ft.Function)
+}
+
 // QualifiedName returns the fully qualified function name including
 // receiver if present. E.g., "(*Store).Save" or "ParseConfig".
 func (ft FunctionTarget) QualifiedName() string {
@@ -241,6 +359,22 @@ const (
 	AssertionNilCheck   AssertionType = "nil_check"
 	AssertionDiffCheck  AssertionType = "diff_check"
 	AssertionCustom     AssertionType = "custom"
+
+	// AssertionExampleOutput is an Example function's "// Output:"
+	// comment, or a value the example prints for it to check.
+	AssertionExampleOutput AssertionType = "example_output"
+
+	// AssertionProperty is an invariant checked over generated
+	// inputs by a fuzz target or a property-based test.
+	AssertionProperty AssertionType = "property"
+
+	// AssertionInteraction is a mock expectation on a call the
+	// target makes to an injected dependency.
+	AssertionInteraction AssertionType = "interaction"
+
+	// AssertionErrorMatch is an errors.Is or errors.As check on a
+	// returned error, which also asserts the target's error wrapping.
+	AssertionErrorMatch AssertionType = "error_match"
 )
 
 // UnmappedReasonType enumerates the reasons why an assertion could not
@@ -282,6 +416,11 @@ type AssertionMapping struct {
 	// Confidence is the mapping confidence (0-100).
 	Confidence int `json:"confidence"`
 
+	// Strict marks an interaction assertion that also pins the call
+	// count or order of the interaction (e.g., Times, InOrder).
+	// Omitted from JSON when false.
+	Strict bool `json:"strict,omitempty"`
+
 	// UnmappedReason explains why this assertion could not be linked to
 	// a side effect. Only populated for unmapped assertions (Confidence 0,
 	// SideEffectID empty). Omitted from JSON for mapped assertions.
@@ -318,6 +457,12 @@ type ContractCoverage struct {
 	// DiscardedReturns: len(DiscardedReturnHints) == len(DiscardedReturns).
 	// Omitted from JSON when there are no discarded returns.
 	DiscardedReturnHints []string `json:"discarded_return_hints,omitempty"`
+
+	// PropertyCovered lists the IDs of covered contractual effects
+	// that are asserted by at least one property-based invariant
+	// (a fuzz target, testing/quick, or rapid) rather than only by
+	// fixed examples. Omitted from JSON when there are none.
+	PropertyCovered []string `json:"property_covered,omitempty"`
 }
 
 // OverSpecificationScore measures how many incidental side effects
@@ -336,12 +481,79 @@ type OverSpecificationScore struct {
 	Suggestions []string `json:"suggestions"`
 }
 
+// SubtestCoverage is the contract coverage attributable to one
+// t.Run subtest or one row of a table-driven test.
+type SubtestCoverage struct {
+	// Name is the full subtest name as reported by go test
+	// (e.g., "TestGreet/empty_name"). Table rows without a name
+	// field are numbered ("TestAbs/#01").
+	Name string `json:"name"`
+
+	// Location is the source position of the t.Run call or of the
+	// table row literal.
+	Location string `json:"location"`
+
+	// ContractCoverage counts only the assertions that execute for
+	// this subtest or row.
+	ContractCoverage ContractCoverage `json:"contract_coverage"`
+
+	// WantFields lists the table fields referenced by the assertions
+	// that execute for this row (e.g., "want", "wantErr"). Empty for
+	// t.Run subtests outside a table.
+	WantFields []string `json:"want_fields,omitempty"`
+}
+
+// ValueCoverage records which values of a return position's
+// constant domain a test compares the result against.
+type ValueCoverage struct {
+	// SideEffectID references the ReturnValue effect.
+	SideEffectID string `json:"side_effect_id"`
+
+	// Values is the effect's domain, by constant name where one is
+	// declared, in detection order.
+	Values []string `json:"values"`
+
+	// Asserted lists the values some assertion expects, in domain
+	// order. Empty when the result is only checked against
+	// non-constant expectations.
+	Asserted []string `json:"asserted"`
+}
+
+// FieldCoverage records which of the fields a function sets on a
+// returned struct or on its receiver a test asserts on.
+type FieldCoverage struct {
+	// SideEffectID references the ReturnValue or ReceiverMutation
+	// effect.
+	SideEffectID string `json:"side_effect_id"`
+
+	// Type is the side effect type.
+	Type SideEffectType `json:"type"`
+
+	// Fields lists the fields the function sets, in declaration
+	// order.
+	Fields []string `json:"fields"`
+
+	// Asserted lists the fields read by some assertion, in Fields
+	// order. A deep-equality assertion on the whole value asserts
+	// every field.
+	Asserted []string `json:"asserted"`
+}
+
 // QualityReport is the complete test quality output for one
 // test-target pair.
 type QualityReport struct {
 	// TestFunction is the name of the test function.
 	TestFunction string `json:"test_function"`
 
+	// TestPackage is the import path of the package declaring the
+	// test function. External test packages carry the "_test"
+	// suffix (e.g., "example.com/store_test").
+	TestPackage string `json:"test_package,omitempty"`
+
+	// TestKind is the form of the test function: "test", "example",
+	// "fuzz", or "property".
+	TestKind string `json:"test_kind,omitempty"`
+
 	// TestLocation is the source position of the test function.
 	TestLocation string `json:"test_location"`
 
@@ -366,16 +578,84 @@ type QualityReport struct {
 	// assertions that were successfully pattern-matched (0-100).
 	AssertionDetectionConfidence int `json:"assertion_detection_confidence"`
 
+	// Subtests breaks contract coverage down per t.Run subtest and
+	// per table-driven test row. Omitted for flat tests.
+	Subtests []SubtestCoverage `json:"subtests,omitempty"`
+
+	// ValueCoverage lists, for each covered return position with an
+	// enumerable constant domain, the values the test asserts.
+	ValueCoverage []ValueCoverage `json:"value_coverage,omitempty"`
+
+	// FieldCoverage lists, for each covered struct return and for
+	// the receiver's mutated fields, the fields the test asserts.
+	FieldCoverage []FieldCoverage `json:"field_coverage,omitempty"`
+
 	// Metadata contains run information.
 	Metadata Metadata `json:"metadata"`
 }
 
+// EffectCoverage records which tests assert on one contractual
+// side effect of a function.
+type EffectCoverage struct {
+	// SideEffectID references the side effect.
+	SideEffectID string `json:"side_effect_id"`
+
+	// Type is the side effect type.
+	Type SideEffectType `json:"type"`
+
+	// CoveredBy lists the test functions that assert on the effect,
+	// sorted by name. Empty when no test covers it.
+	CoveredBy []string `json:"covered_by"`
+
+	// Values is the effect's constant domain, for ReturnValue
+	// positions whose values can be enumerated.
+	Values []string `json:"values,omitempty"`
+
+	// AssertedValues lists the domain values asserted by any test,
+	// in domain order.
+	AssertedValues []string `json:"asserted_values,omitempty"`
+
+	// Fields lists the struct fields the function sets, for
+	// ReturnValue and ReceiverMutation effects.
+	Fields []string `json:"fields,omitempty"`
+
+	// AssertedFields lists the fields asserted by any test, in
+	// Fields order.
+	AssertedFields []string `json:"asserted_fields,omitempty"`
+
+	// NeverExecuted marks an effect whose source line no test ran,
+	// according to the coverage profile supplied. Always false when
+	// no profile was given.
+	NeverExecuted bool `json:"never_executed,omitempty"`
+}
+
+// FunctionCoverage is the contract coverage of one function,
+// unioned across every test that targets it. An effect counts as
+// covered when any test asserts on it.
+type FunctionCoverage struct {
+	// Function identifies the function.
+	Function FunctionTarget `json:"function"`
+
+	// ContractCoverage is the union coverage metric. Gaps lists the
+	// contractual effects no test asserts on.
+	ContractCoverage ContractCoverage `json:"contract_coverage"`
+
+	// Effects lists every contractual effect with the tests that
+	// cover it, in detection order.
+	Effects []EffectCoverage `json:"effects"`
+
+	// Tests lists the test functions targeting the function,
+	// sorted by name.
+	Tests []string `json:"tests"`
+}
+
 // PackageSummary holds aggregate quality metrics for a package.
 type PackageSummary struct {
 	// TotalTests is the number of test functions analyzed.
 	TotalTests int `json:"total_tests"`
 
-	// AverageContractCoverage is the mean coverage across tests.
+	// AverageContractCoverage is the mean coverage across tests,
+	// with each untested contract counted as a 0% entry.
 	AverageContractCoverage float64 `json:"average_contract_coverage"`
 
 	// TotalOverSpecifications is the sum of incidental assertion
@@ -388,13 +668,67 @@ type PackageSummary struct {
 	// AssertionDetectionConfidence is the aggregate detection
 	// confidence across all tests.
 	AssertionDetectionConfidence int `json:"assertion_detection_confidence"`
+
+	// FunctionCoverage lists the union contract coverage of each
+	// tested function across all tests that target it.
+	FunctionCoverage []FunctionCoverage `json:"function_coverage,omitempty"`
+
+	// UntestedContracts lists analyzed functions that have
+	// contractual effects but no paired test. Each has 0% coverage
+	// and lists all contractual effects as gaps.
+	UntestedContracts []FunctionCoverage `json:"untested_contracts,omitempty"`
+
+	// NeverExecutedContracts counts the contractual effects, across
+	// FunctionCoverage and UntestedContracts, whose source line the
+	// coverage profile shows was never executed.
+	NeverExecutedContracts int `json:"never_executed_contracts,omitempty"`
 }
 
-// GenerateID produces a stable, deterministic ID for a side effect
-// based on its context. The ID is a sha256 hash truncated to 8 hex
-// characters, prefixed with "se-".
-func GenerateID(pkg, function, effectType, location string) string {
-	input := fmt.Sprintf("%s:%s:%s:%s", pkg, function, effectType, location)
+// GenerateID produces a stable, deterministic ID for a side effect.
+// The ID is a sha256 hash truncated to 16 hex characters, prefixed
+// with "se-". function is the qualified function name, including its
+// receiver (e.g., "(*Store).Save"), so that same-named methods of
+// different types get different IDs. key identifies what the effect
+// acts on (a field, parameter, callee or result position), never
+// where it is in the source, so that IDs survive code motion.
+func GenerateID(pkg, function, effectType, key string) string {
+	input := strings.Join([]string{pkg, function, effectType, key}, "\x00")
 	hash := sha256.Sum256([]byte(input))
-	return fmt.Sprintf("se-%x", hash[:4])
+	return fmt.Sprintf("se-%x", hash[:8])
+}
+
+// DisambiguateIDs makes the side effect IDs across results unique.
+// Two effects hash to the same ID only when their inputs match, as
+// for effects of several init functions in one package, or when the
+// hash collides. Every effect after the first with a given ID gets
+// an ID derived from it and an ordinal, and a warning naming both
+// effects is added to its result's metadata. Returns the number of
+// IDs changed.
+func DisambiguateIDs(results []AnalysisResult) int {
+	owner := make(map[string]string)
+	changed := 0
+	for i := range results {
+		r := &results[i]
+		name := r.Target.Package + "." + r.Target.QualifiedName()
+		for j := range r.SideEffects {
+			e := &r.SideEffects[j]
+			first, taken := owner[e.ID]
+			if !taken {
+				owner[e.ID] = name
+				continue
+			}
+			id := e.ID
+			for n := 2; taken; n++ {
+				id = GenerateID(e.ID, "", "", strconv.Itoa(n))
+				_, taken = owner[id]
+			}
+			r.Metadata.Warnings = append(r.Metadata.Warnings, fmt.Sprintf(
+				"side effect ID %s of %s %s collides with an effect of %s; using %s",
+				e.ID, name, e.Type, first, id))
+			owner[id] = name
+			e.ID = id
+			changed++
+		}
+	}
+	return changed
 }
@@ -17,8 +17,8 @@ func TestGenerateID_Deterministic(t *testing.T) {
 func TestGenerateID_Format(t *testing.T) {
 	id := GenerateID("pkg/foo", "Save", "ReceiverMutation", "foo.go:10:2")
 
-	if len(id) != 11 { // "se-" + 8 hex chars
-		t.Errorf("expected ID length 11, got %d: %q", len(id), id)
+	if len(id) != 19 { // "se-" + 16 hex chars
+		t.Errorf("expected ID length 19, got %d: %q", len(id), id)
 	}
 	if id[:3] != "se-" {
 		t.Errorf("expected ID to start with 'se-', got %q", id)
@@ -38,6 +38,74 @@ func TestGenerateID_UniqueForDifferentInputs(t *testing.T) {
 	}
 }
 
+func TestGenerateID_FieldsAreSeparated(t *testing.T) {
+	// Joining without a separator would make these inputs identical.
+	id1 := GenerateID("pkg/foo", "Save", "ReceiverMutation", "ab")
+	id2 := GenerateID("pkg/foo", "Save", "ReceiverMutatio", "nab")
+
+	if id1 == id2 {
+		t.Errorf("inputs differing only in field boundaries should produce different IDs")
+	}
+}
+
+func TestDisambiguateIDs(t *testing.T) {
+	// Two init functions reporting the same global mutation hash to
+	// the same ID.
+	id := GenerateID("pkg/foo", "init", string(GlobalMutation), "registry")
+	results := []AnalysisResult{
+		{Target: FunctionTarget{Package: "pkg/foo", Function: "init"},
+			SideEffects: []SideEffect{{ID: id, Type: GlobalMutation}}},
+		{Target: FunctionTarget{Package: "pkg/foo", Function: "init"},
+			SideEffects: []SideEffect{{ID: id, Type: GlobalMutation}}},
+		{Target: FunctionTarget{Package: "pkg/foo", Function: "Save", Receiver: "*Store"},
+			SideEffects: []SideEffect{{ID: GenerateID("pkg/foo", "(*Store).Save", string(ReturnValue), "result:0"), Type: ReturnValue}}},
+	}
+
+	if n := DisambiguateIDs(results); n != 1 {
+		t.Fatalf("expected 1 ID changed, got %d", n)
+	}
+	if results[0].SideEffects[0].ID != id {
+		t.Errorf("first effect's ID changed to %q", results[0].SideEffects[0].ID)
+	}
+	second := results[1].SideEffects[0].ID
+	if second == id || len(second) != len(id) {
+		t.Errorf("second effect's ID = %q, want a distinct ID of the same form", second)
+	}
+	if len(results[1].Metadata.Warnings) != 1 || len(results[0].Metadata.Warnings) != 0 {
+		t.Errorf("expected one warning on the second result, got %v and %v",
+			results[0].Metadata.Warnings, results[1].Metadata.Warnings)
+	}
+
+	// The outcome is deterministic.
+	again := []AnalysisResult{results[0], {Target: results[1].Target,
+		SideEffects: []SideEffect{{ID: id, Type: GlobalMutation}}}}
+	DisambiguateIDs(again)
+	if again[1].SideEffects[0].ID != second {
+		t.Errorf("disambiguated ID not deterministic: %q != %q", again[1].SideEffects[0].ID, second)
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		loc       string
+		file      string
+		line, col int
+		ok        bool
+	}{
+		{"/src/store.go:12:5", "/src/store.go", 12, 5, true},
+		{"store.go:12", "store.go", 12, 0, true},
+		{`C:\src\store.go:12:5`, `C:\src\store.go`, 12, 5, true},
+		{"store.go", "", 0, 0, false},
+	}
+	for _, tt := range tests {
+		file, line, col, ok := ParseLocation(tt.loc)
+		if file != tt.file || line != tt.line || col != tt.col || ok != tt.ok {
+			t.Errorf("ParseLocation(%q) = %q, %d, %d, %v; want %q, %d, %d, %v",
+				tt.loc, file, line, col, ok, tt.file, tt.line, tt.col, tt.ok)
+		}
+	}
+}
+
 func TestTierOf_P0Types(t *testing.T) {
 	p0Types := []SideEffectType{
 		ReturnValue, ErrorReturn, SentinelError,
@@ -58,7 +126,9 @@ func TestTierOf_AllTypesHaveTiers(t *testing.T) {
 		// P1
 		SliceMutation, MapMutation, GlobalMutation,
 		WriterOutput, HTTPResponseWrite, ChannelSend,
-		ChannelClose, DeferredReturnMutation,
+		ChannelClose, DeferredReturnMutation, ErrorWrap,
+		RespectsCancellation, HTTPStatusCode, HTTPHeader,
+		GRPCStatusCode,
 		// P2
 		FileSystemWrite, FileSystemDelete, FileSystemMeta,
 		DatabaseWrite, DatabaseTransaction, GoroutineSpawn,
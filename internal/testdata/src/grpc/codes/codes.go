@@ -0,0 +1,27 @@
+// Package codes is a stand-in for google.golang.org/grpc/codes, shared
+// by the analysis and quality test fixtures.
+package codes
+
+// Code is a gRPC status code.
+type Code uint32
+
+// The canonical gRPC status codes.
+const (
+	OK Code = iota
+	Canceled
+	Unknown
+	InvalidArgument
+	DeadlineExceeded
+	NotFound
+	AlreadyExists
+	PermissionDenied
+	ResourceExhausted
+	FailedPrecondition
+	Aborted
+	OutOfRange
+	Unimplemented
+	Internal
+	Unavailable
+	DataLoss
+	Unauthenticated
+)
@@ -0,0 +1,62 @@
+// Package status is a stand-in for google.golang.org/grpc/status,
+// shared by the analysis and quality test fixtures.
+package status
+
+import (
+	"fmt"
+
+	"github.com/unbound-force/gaze/internal/testdata/src/grpc/codes"
+)
+
+// Status is a gRPC status.
+type Status struct {
+	code codes.Code
+	msg  string
+}
+
+// New returns a Status with the given code and message.
+func New(c codes.Code, msg string) *Status { return &Status{code: c, msg: msg} }
+
+// Error returns an error with the given code and message.
+func Error(c codes.Code, msg string) error { return New(c, msg).Err() }
+
+// Errorf returns an error with the given code and formatted message.
+func Errorf(c codes.Code, format string, a ...any) error {
+	return Error(c, fmt.Sprintf(format, a...))
+}
+
+// Code returns the code of err.
+func Code(err error) codes.Code {
+	if se, ok := err.(*statusError); ok {
+		return se.s.code
+	}
+	return codes.Unknown
+}
+
+// FromError returns the Status of err.
+func FromError(err error) (*Status, bool) {
+	if se, ok := err.(*statusError); ok {
+		return se.s, true
+	}
+	return New(codes.Unknown, err.Error()), false
+}
+
+// Code returns the status code.
+func (s *Status) Code() codes.Code { return s.code }
+
+// WithDetails returns a copy of s with details attached.
+func (s *Status) WithDetails(details ...any) (*Status, error) { return s, nil }
+
+// Err returns s as an error, or nil for OK.
+func (s *Status) Err() error {
+	if s.code == codes.OK {
+		return nil
+	}
+	return &statusError{s: s}
+}
+
+type statusError struct{ s *Status }
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("rpc error: code = %d desc = %s", e.s.code, e.s.msg)
+}